@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/x-color/vue-trello/interface/presenter/logging"
+	"github.com/x-color/vue-trello/interface/repository/rdb"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Exit codes of fsck follow fsck(8).
+const (
+	fsckOK          = 0
+	fsckRepaired    = 1
+	fsckNotRepaired = 4
+	fsckError       = 8
+)
+
+// runFsck checks ranks of all data in DB and prints anomalies per user and board.
+// Anomalies are repaired in a transaction if '-repair' flag is given.
+func runFsck(args []string, output io.Writer) int {
+	flags := flag.NewFlagSet("fsck", flag.ContinueOnError)
+	repair := flags.Bool("repair", false, "repair anomalies")
+	if err := flags.Parse(args); err != nil {
+		return fsckError
+	}
+
+	dbm, err := rdb.OpenDBManager()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return fsckError
+	}
+
+	location, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		location = time.FixedZone("Asia/Tokyo", 9*60*60)
+	}
+	logger, err := logging.NewLogger(os.Stderr, location)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return fsckError
+	}
+
+	fsckIntera, err := usecase.NewFsckInteractor(
+		&dbm.TransactionManager,
+		&dbm.BoardDBManager,
+		&dbm.ListDBManager,
+		&dbm.ItemDBManager,
+		&dbm.ChecklistDBManager,
+		&dbm.CheckItemDBManager,
+		&logger,
+	)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return fsckError
+	}
+
+	anomalies, err := fsckIntera.Check(context.Background(), *repair)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return fsckError
+	}
+
+	printAnomalies(output, anomalies)
+
+	switch {
+	case len(anomalies) == 0:
+		return fsckOK
+	case *repair:
+		return fsckRepaired
+	default:
+		return fsckNotRepaired
+	}
+}
+
+// printAnomalies prints anomalies grouped by user and board.
+// Orphans are printed in a group of unknown board because their board does not exist.
+func printAnomalies(output io.Writer, anomalies model.Anomalies) {
+	sort.SliceStable(anomalies, func(a, b int) bool {
+		if anomalies[a].UserID != anomalies[b].UserID {
+			return anomalies[a].UserID < anomalies[b].UserID
+		}
+		return anomalies[a].BoardID < anomalies[b].BoardID
+	})
+
+	userID, boardID := "", ""
+	for j, a := range anomalies {
+		newUser := j == 0 || a.UserID != userID
+		if newUser {
+			fmt.Fprintf(output, "user(%s)\n", a.UserID)
+		}
+		if newUser || a.BoardID != boardID {
+			if a.BoardID == "" {
+				fmt.Fprintf(output, "  board(unknown)\n")
+			} else {
+				fmt.Fprintf(output, "  board(%s)\n", a.BoardID)
+			}
+		}
+		userID, boardID = a.UserID, a.BoardID
+
+		status := ""
+		if a.Repaired {
+			status = " [repaired]"
+		}
+		fmt.Fprintf(output, "    %s(%s) in %s: %s (rank %q)%s\n", a.Target, a.ID, a.ParentID, a.Problem, a.Rank, status)
+	}
+
+	if len(anomalies) == 0 {
+		fmt.Fprintln(output, "no anomalies")
+		return
+	}
+	fmt.Fprintf(output, "%d anomalies\n", len(anomalies))
+}
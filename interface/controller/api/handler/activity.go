@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Activity includes response data for Activity.
+// Before and After are null if data does not exist.
+type Activity struct {
+	ID        string          `json:"id"`
+	UserID    string          `json:"user_id"`
+	UserName  string          `json:"name"`
+	Action    string          `json:"action"`
+	Target    string          `json:"target"`
+	TargetID  string          `json:"target_id"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func (a *Activity) convertFrom(activity model.Activity) {
+	a.ID = activity.ID
+	a.UserID = activity.UserID
+	a.UserName = activity.UserName
+	a.Action = string(activity.Action)
+	a.Target = string(activity.Target)
+	a.TargetID = activity.TargetID
+	a.Before = rawJSON(activity.Before)
+	a.After = rawJSON(activity.After)
+	a.CreatedAt = activity.CreatedAt
+}
+
+func rawJSON(s string) json.RawMessage {
+	if s == "" {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(s)
+}
+
+// ActivityHandler includes a interactor for Activity usecase.
+type ActivityHandler struct {
+	intractor usecase.ActivityUsecase
+}
+
+// NewActivityHandler returns a new ActivityHandler.
+func NewActivityHandler(a usecase.ActivityUsecase) ActivityHandler {
+	return ActivityHandler{
+		intractor: a,
+	}
+}
+
+// GetActivities is http handler to get activities in a board process.
+// 'cursor' query parameter is 'next' of previous page. 'limit' is 50 by default and 100 at most.
+// 'next' in response is null if there are no more activities.
+func (h *ActivityHandler) GetActivities(c echo.Context) error {
+	limit := 50
+	if l := c.QueryParam("limit"); l != "" {
+		v, err := strconv.Atoi(l)
+		if err != nil || v <= 0 || v > 100 {
+			return echo.ErrBadRequest
+		}
+		limit = v
+	}
+
+	user := model.User{ID: getUserIDFromToken(c)}
+	board := model.Board{ID: c.Param("id")}
+
+	activities, next, err := h.intractor.GetActivities(c.Request().Context(), user, board, c.QueryParam("cursor"), limit)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resActivities := []Activity{}
+	for _, activity := range activities {
+		a := Activity{}
+		a.convertFrom(activity)
+		resActivities = append(resActivities, a)
+	}
+
+	var resNext *string
+	if next != "" {
+		resNext = &next
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"activities": resActivities,
+		"next":       resNext,
+	})
+}
@@ -1,18 +1,25 @@
 package handler
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"net/http"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/labstack/echo"
+	"github.com/labstack/echo/middleware"
 	"github.com/x-color/vue-trello/model"
 	"github.com/x-color/vue-trello/usecase"
 )
 
-//SECRET uses to encode token for JWT.
-var SECRET = []byte("secret")
+// Names of a cookie and a header of CSRF token. The cookie can be read by scripts of the SPA,
+// which send the token in the header. Sites of others can not read it, so they can not forge the header.
+const (
+	CSRFCookieName = "XSRF-TOKEN"
+	CSRFHeaderName = "X-XSRF-TOKEN"
+)
 
 // User includes request data for authentication.
 type User struct {
@@ -34,15 +41,33 @@ func (u *User) convertFrom(user model.User) {
 	u.Password = ""
 }
 
-// UserHandler includes a interactor for user usecase.
+// Session includes response data for Session. Current is true for a Session of the request.
+type Session struct {
+	ID        string    `json:"id"`
+	Current   bool      `json:"current"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *Session) convertFrom(session model.Session) {
+	s.ID = session.ID
+	s.ExpiresAt = session.ExpiresAt
+	s.CreatedAt = session.CreatedAt
+}
+
+// UserHandler includes interactors for user and session usecases, and keys for JWT.
 type UserHandler struct {
 	interactor usecase.UserUsecase
+	session    usecase.SessionUsecase
+	keys       Keys
 }
 
 // NewUserHandler returns a new UserHandler.
-func NewUserHandler(u usecase.UserUsecase) UserHandler {
+func NewUserHandler(u usecase.UserUsecase, s usecase.SessionUsecase, keys Keys) UserHandler {
 	return UserHandler{
 		interactor: u,
+		session:    s,
+		keys:       keys,
 	}
 }
 
@@ -57,7 +82,7 @@ func (h *UserHandler) SignUp(c echo.Context) error {
 		return echo.ErrBadRequest
 	}
 
-	u, err := h.interactor.SignUp(user.convertTo())
+	u, err := h.interactor.SignUp(c.Request().Context(), user.convertTo())
 	if err != nil {
 		return convertToHTTPError(c, err)
 	}
@@ -74,7 +99,7 @@ func (h *UserHandler) SignIn(c echo.Context) error {
 		return err
 	}
 
-	u, err := h.interactor.SignIn(user.convertTo())
+	u, err := h.interactor.SignIn(c.Request().Context(), user.convertTo())
 	if err != nil {
 		if errors.Is(err, model.NotFoundError{}) {
 			return echo.ErrUnauthorized
@@ -82,12 +107,229 @@ func (h *UserHandler) SignIn(c echo.Context) error {
 		return convertToHTTPError(c, err)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{
-		Subject:   u.ID,
-		ExpiresAt: time.Now().Add(time.Hour * 72).Unix(),
+	if err := h.startSession(c, u); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Sign in",
 	})
+}
+
+// Password includes request data to change a password.
+type Password struct {
+	Password    string `json:"password"`
+	NewPassword string `json:"new_password"`
+}
+
+// ChangeName is http handler to change a name of a user process.
+func (h *UserHandler) ChangeName(c echo.Context) error {
+	if HasAccessToken(c) {
+		return echo.ErrForbidden
+	}
+
+	user := User{}
+	if err := c.Bind(&user); err != nil {
+		return err
+	}
 
-	t, err := token.SignedString(SECRET)
+	u := user.convertTo()
+	u.ID = getUserIDFromToken(c)
+	u.Password = ""
+
+	u, err := h.interactor.ChangeName(c.Request().Context(), u)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	r := User{}
+	r.convertFrom(u)
+	return c.JSON(http.StatusOK, r)
+}
+
+// ChangePassword is http handler to change a password of a user process.
+// All sessions of the user are revoked, and a new session is started for the request.
+func (h *UserHandler) ChangePassword(c echo.Context) error {
+	if HasAccessToken(c) {
+		return echo.ErrForbidden
+	}
+
+	password := Password{}
+	if err := c.Bind(&password); err != nil {
+		return err
+	}
+
+	if password.Password == "" || password.NewPassword == "" {
+		return echo.ErrBadRequest
+	}
+
+	u := model.User{
+		ID:       getUserIDFromToken(c),
+		Password: password.Password,
+	}
+	if err := h.interactor.ChangePassword(c.Request().Context(), u, password.NewPassword); err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	if err := h.startSession(c, u); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteAccount is http handler to delete an account of a user process.
+func (h *UserHandler) DeleteAccount(c echo.Context) error {
+	if HasAccessToken(c) {
+		return echo.ErrForbidden
+	}
+
+	user := User{}
+	if err := c.Bind(&user); err != nil {
+		return err
+	}
+
+	if user.Password == "" {
+		return echo.ErrBadRequest
+	}
+
+	u := model.User{
+		ID:       getUserIDFromToken(c),
+		Password: user.Password,
+	}
+	if err := h.interactor.Delete(c.Request().Context(), u); err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	clearTokenCookie(c)
+	if err := issueCSRFToken(c, time.Time{}); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SignOut is http handler to sign out process.
+// The session of the token is revoked, so that the token can not be used even if it is stolen.
+func (h *UserHandler) SignOut(c echo.Context) error {
+	if cookie, err := c.Cookie("token"); err == nil {
+		if token, err := h.keys.parse(cookie.Value); err == nil {
+			claims := token.Claims.(*jwt.StandardClaims)
+			err := h.session.Revoke(c.Request().Context(), model.Session{
+				ID:     claims.Id,
+				UserID: claims.Subject,
+			})
+			if err != nil && !errors.Is(err, model.NotFoundError{}) {
+				return convertToHTTPError(c, err)
+			}
+		}
+	}
+
+	clearTokenCookie(c)
+	if err := issueCSRFToken(c, time.Time{}); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Sign out",
+	})
+}
+
+// GetSessions is http handler to get sessions of a user process.
+func (h *UserHandler) GetSessions(c echo.Context) error {
+	if HasAccessToken(c) {
+		return echo.ErrForbidden
+	}
+
+	user := model.User{ID: getUserIDFromToken(c)}
+
+	sessions, err := h.session.GetSessions(c.Request().Context(), user)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	current := getSessionIDFromToken(c)
+	resSessions := []Session{}
+	for _, session := range sessions {
+		s := Session{}
+		s.convertFrom(session)
+		s.Current = s.ID == current
+		resSessions = append(resSessions, s)
+	}
+
+	return c.JSON(http.StatusOK, map[string][]Session{
+		"sessions": resSessions},
+	)
+}
+
+// SignOutAll is http handler to sign out of all devices process.
+// All sessions of a user including the current one are revoked.
+func (h *UserHandler) SignOutAll(c echo.Context) error {
+	if HasAccessToken(c) {
+		return echo.ErrForbidden
+	}
+
+	user := model.User{ID: getUserIDFromToken(c)}
+
+	if err := h.session.RevokeAll(c.Request().Context(), user); err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	clearTokenCookie(c)
+	if err := issueCSRFToken(c, time.Time{}); err != nil {
+		return echo.ErrInternalServerError
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Authenticate is middleware to authenticate a request by JWT in 'token' cookie.
+// JWT is valid only while its session exists. Requests authenticated by personal access tokens are skipped.
+func (h *UserHandler) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if HasAccessToken(c) {
+			return next(c)
+		}
+
+		cookie, err := c.Cookie("token")
+		if err != nil || cookie.Value == "" {
+			return middleware.ErrJWTMissing
+		}
+
+		token, err := h.keys.parse(cookie.Value)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired jwt")
+		}
+
+		claims := token.Claims.(*jwt.StandardClaims)
+		_, err = h.session.Authenticate(c.Request().Context(), model.Session{
+			ID:     claims.Id,
+			UserID: claims.Subject,
+		})
+		if err != nil {
+			if errors.Is(err, model.NotFoundError{}) {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired jwt")
+			}
+			return convertToHTTPError(c, err)
+		}
+
+		c.Set("user", token)
+		return next(c)
+	}
+}
+
+// startSession creates new session of a user and sets JWT of it and new CSRF token to cookies.
+func (h *UserHandler) startSession(c echo.Context, u model.User) error {
+	session, err := h.session.Create(c.Request().Context(), u)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	t, err := h.keys.sign(jwt.StandardClaims{
+		Id:        session.ID,
+		Subject:   u.ID,
+		ExpiresAt: session.ExpiresAt.Unix(),
+	})
 	if err != nil {
 		return echo.ErrInternalServerError
 	}
@@ -100,45 +342,65 @@ func (h *UserHandler) SignIn(c echo.Context) error {
 	//		 But this code is sample, it does not set this attribute and TLS.
 	// cookie.Secure = true
 	cookie.SameSite = http.SameSiteStrictMode
-	cookie.Expires = time.Now().Add(72 * time.Hour)
+	cookie.Expires = session.ExpiresAt
 	cookie.Path = "/"
 	c.SetCookie(cookie)
 
-	return c.JSON(http.StatusOK, map[string]string{
-		"message": "Sign in",
-	})
-}
-
-// SignOut is http handler to sign out process.
-func (h *UserHandler) SignOut(c echo.Context) error {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{
-		ExpiresAt: time.Now().Unix(),
-	})
-
-	t, err := token.SignedString(SECRET)
-	if err != nil {
+	if err := issueCSRFToken(c, session.ExpiresAt); err != nil {
 		return echo.ErrInternalServerError
 	}
+	return nil
+}
 
+func clearTokenCookie(c echo.Context) {
 	cookie := new(http.Cookie)
 	cookie.Name = "token"
-	cookie.Value = t
+	cookie.Value = ""
 	cookie.HttpOnly = true
 	// NOTE: It should activate Secure attribute of Cookie.
 	//		 But this code is sample, it does not set this attribute and TLS.
 	// cookie.Secure = true
 	cookie.SameSite = http.SameSiteStrictMode
+	cookie.MaxAge = -1
 	cookie.Path = "/"
 	c.SetCookie(cookie)
+}
 
-	return c.JSON(http.StatusOK, map[string]string{
-		"message": "Sign out",
-	})
+// issueCSRFToken sets new random CSRF token to a cookie. It is rotated on sign-in and sign-out,
+// so a token known before them can not be used. The cookie is deleted when the browser is closed if expires is zero.
+func issueCSRFToken(c echo.Context, expires time.Time) error {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+
+	cookie := new(http.Cookie)
+	cookie.Name = CSRFCookieName
+	cookie.Value = hex.EncodeToString(b)
+	// NOTE: It should activate Secure attribute of Cookie.
+	//		 But this code is sample, it does not set this attribute and TLS.
+	// cookie.Secure = true
+	cookie.SameSite = http.SameSiteStrictMode
+	cookie.Expires = expires
+	cookie.Path = "/"
+	c.SetCookie(cookie)
+	return nil
 }
 
 func getUserIDFromToken(c echo.Context) string {
+	if token, ok := AccessTokenOf(c); ok {
+		return token.UserID
+	}
 	user := c.Get("user").(*jwt.Token)
 	claims := user.Claims.(*jwt.StandardClaims)
 	uid := claims.Subject
 	return uid
 }
+
+func getSessionIDFromToken(c echo.Context) string {
+	user, ok := c.Get("user").(*jwt.Token)
+	if !ok {
+		return ""
+	}
+	return user.Claims.(*jwt.StandardClaims).Id
+}
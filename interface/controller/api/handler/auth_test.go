@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+func TestIssueCSRFToken(t *testing.T) {
+	e := echo.New()
+
+	// A new token is issued each time, e.g. on sign-in and sign-out
+	tokens := map[string]bool{}
+	for j := 0; j < 2; j++ {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/auth/signin", nil), rec)
+		if err := issueCSRFToken(c, time.Time{}); err != nil {
+			t.Fatal(err)
+		}
+
+		cookies := rec.Result().Cookies()
+		if len(cookies) != 1 || cookies[0].Name != CSRFCookieName {
+			t.Fatalf("cookies = %v, want %s", cookies, CSRFCookieName)
+		}
+		// Scripts of the SPA read the cookie to send it in the header
+		if c := cookies[0]; c.HttpOnly || c.SameSite != http.SameSiteStrictMode || c.Path != "/" || len(c.Value) != 64 {
+			t.Errorf("cookie = %+v, want a readable strict cookie of 32 bytes in hex", c)
+		}
+		tokens[cookies[0].Value] = true
+	}
+	if len(tokens) != 2 {
+		t.Errorf("the same token is issued twice")
+	}
+}
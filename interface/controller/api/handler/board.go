@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/labstack/echo"
@@ -9,14 +10,16 @@ import (
 )
 
 // Board includes request data for Board.
+// Version is only for response. A version expected by a request is given by If-Match header.
 type Board struct {
-	ID     string `json:"id"`
-	Title  string `json:"title"`
-	Text   string `json:"text"`
-	Lists  []List `json:"lists"`
-	Color  string `json:"color"`
-	Before string `json:"before"`
-	After  string `json:"after"`
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Text    string `json:"text"`
+	Lists   []List `json:"lists"`
+	Color   string `json:"color"`
+	Before  string `json:"before"`
+	Rank    string `json:"rank"`
+	Version int    `json:"version"`
 }
 
 func (b *Board) convertTo() model.Board {
@@ -26,7 +29,6 @@ func (b *Board) convertTo() model.Board {
 		Text:   b.Text,
 		Color:  model.Color(b.Color),
 		Before: b.Before,
-		After:  b.After,
 	}
 
 	return board
@@ -44,8 +46,8 @@ func (b *Board) convertFrom(board model.Board) {
 		lists = append(lists, list)
 	}
 	b.Lists = lists
-	b.Before = board.Before
-	b.After = board.After
+	b.Rank = board.Rank
+	b.Version = board.Version
 }
 
 // BoardHandler includes a interactor for Board usecase.
@@ -70,13 +72,14 @@ func (h *BoardHandler) Create(c echo.Context) error {
 	board := reqBoard.convertTo()
 	board.UserID = getUserIDFromToken(c)
 
-	b, err := h.intractor.Create(board)
+	b, err := h.intractor.Create(c.Request().Context(), board)
 	if err != nil {
 		return convertToHTTPError(c, err)
 	}
 
 	resBoard := Board{}
 	resBoard.convertFrom(b)
+	setETag(c, b.Version)
 
 	return c.JSON(http.StatusCreated, resBoard)
 }
@@ -91,14 +94,16 @@ func (h *BoardHandler) Update(c echo.Context) error {
 
 	board := reqBoard.convertTo()
 	board.UserID = getUserIDFromToken(c)
+	board.Version = ifMatch(c)
 
-	b, err := h.intractor.Update(board)
+	b, err := h.intractor.Update(c.Request().Context(), board)
 	if err != nil {
 		return convertToHTTPError(c, err)
 	}
 
 	resBoard := Board{}
 	resBoard.convertFrom(b)
+	setETag(c, b.Version)
 
 	return c.JSON(http.StatusOK, resBoard)
 }
@@ -113,11 +118,13 @@ func (h *BoardHandler) Move(c echo.Context) error {
 
 	board := reqBoard.convertTo()
 	board.UserID = getUserIDFromToken(c)
+	board.Version = ifMatch(c)
 
-	err := h.intractor.Move(board)
+	b, err := h.intractor.Move(c.Request().Context(), board)
 	if err != nil {
 		return convertToHTTPError(c, err)
 	}
+	setETag(c, b.Version)
 
 	return c.NoContent(http.StatusNoContent)
 }
@@ -132,8 +139,9 @@ func (h *BoardHandler) Delete(c echo.Context) error {
 
 	board := reqBoard.convertTo()
 	board.UserID = getUserIDFromToken(c)
+	board.Version = ifMatch(c)
 
-	err := h.intractor.Delete(board)
+	err := h.intractor.Delete(c.Request().Context(), board)
 	if err != nil {
 		return convertToHTTPError(c, err)
 	}
@@ -149,7 +157,7 @@ func (h *BoardHandler) Get(c echo.Context) error {
 	board := reqBoard.convertTo()
 	board.UserID = getUserIDFromToken(c)
 
-	b, err := h.intractor.Get(board)
+	b, err := h.intractor.Get(c.Request().Context(), board)
 	if err != nil {
 		return convertToHTTPError(c, err)
 	}
@@ -157,12 +165,21 @@ func (h *BoardHandler) Get(c echo.Context) error {
 	resBoard := Board{}
 	resBoard.convertFrom(b)
 
+	// The board includes its lists and items, so its ETag is also changed by them.
+	body, err := json.Marshal(resBoard)
+	if err != nil {
+		return err
+	}
+	if ifNoneMatch(c, setContentETag(c, b.Version, body)) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
 	return c.JSON(http.StatusOK, resBoard)
 }
 
 // GetBoards is http handler to get user's boards process.
 func (h *BoardHandler) GetBoards(c echo.Context) error {
-	boards, err := h.intractor.GetBoards(model.User{ID: getUserIDFromToken(c)})
+	boards, err := h.intractor.GetBoards(c.Request().Context(), model.User{ID: getUserIDFromToken(c)})
 	if err != nil {
 		return convertToHTTPError(c, err)
 	}
@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Checklist includes request data for Checklist.
+type Checklist struct {
+	ID         string      `json:"id"`
+	ItemID     string      `json:"item_id"`
+	Title      string      `json:"title"`
+	CheckItems []CheckItem `json:"check_items"`
+	Before     string      `json:"before"`
+	Rank       string      `json:"rank"`
+}
+
+func (c *Checklist) convertTo() model.Checklist {
+	checklist := model.Checklist{
+		ID:     c.ID,
+		ItemID: c.ItemID,
+		Title:  c.Title,
+		Before: c.Before,
+	}
+
+	return checklist
+}
+
+func (c *Checklist) convertFrom(checklist model.Checklist) {
+	c.ID = checklist.ID
+	c.ItemID = checklist.ItemID
+	c.Title = checklist.Title
+	c.Rank = checklist.Rank
+
+	checkItems := []CheckItem{}
+	for _, ci := range checklist.CheckItems {
+		checkItem := CheckItem{}
+		checkItem.convertFrom(ci)
+		checkItems = append(checkItems, checkItem)
+	}
+	c.CheckItems = checkItems
+}
+
+// CheckItem includes request data for CheckItem.
+type CheckItem struct {
+	ID          string `json:"id"`
+	ChecklistID string `json:"checklist_id"`
+	Title       string `json:"title"`
+	Checked     bool   `json:"checked"`
+	Before      string `json:"before"`
+	Rank        string `json:"rank"`
+}
+
+func (c *CheckItem) convertTo() model.CheckItem {
+	checkItem := model.CheckItem{
+		ID:          c.ID,
+		ChecklistID: c.ChecklistID,
+		Title:       c.Title,
+		Checked:     c.Checked,
+		Before:      c.Before,
+	}
+
+	return checkItem
+}
+
+func (c *CheckItem) convertFrom(checkItem model.CheckItem) {
+	c.ID = checkItem.ID
+	c.ChecklistID = checkItem.ChecklistID
+	c.Title = checkItem.Title
+	c.Checked = checkItem.Checked
+	c.Rank = checkItem.Rank
+}
+
+// ChecklistHandler includes a interactor for Checklist usecase.
+type ChecklistHandler struct {
+	intractor usecase.ChecklistUsecase
+}
+
+// NewChecklistHandler returns a new ChecklistHandler.
+func NewChecklistHandler(i usecase.ChecklistUsecase) ChecklistHandler {
+	return ChecklistHandler{
+		intractor: i,
+	}
+}
+
+// GetChecklists is http handler to get checklists in a item process.
+func (h *ChecklistHandler) GetChecklists(c echo.Context) error {
+	item := model.Item{
+		ID:     c.Param("id"),
+		UserID: getUserIDFromToken(c),
+	}
+
+	checklists, err := h.intractor.GetChecklists(c.Request().Context(), item)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resChecklists := []Checklist{}
+	for _, checklist := range checklists {
+		cl := Checklist{}
+		cl.convertFrom(checklist)
+		resChecklists = append(resChecklists, cl)
+	}
+
+	return c.JSON(http.StatusOK, map[string][]Checklist{
+		"checklists": resChecklists},
+	)
+}
+
+// Create is http handler to create a checklist process.
+func (h *ChecklistHandler) Create(c echo.Context) error {
+	reqChecklist := new(Checklist)
+	if err := c.Bind(reqChecklist); err != nil {
+		return err
+	}
+	reqChecklist.ItemID = c.Param("id")
+
+	checklist := reqChecklist.convertTo()
+	checklist.UserID = getUserIDFromToken(c)
+
+	cl, err := h.intractor.Create(c.Request().Context(), checklist)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resChecklist := Checklist{}
+	resChecklist.convertFrom(cl)
+
+	return c.JSON(http.StatusCreated, resChecklist)
+}
+
+// Update is http handler to update a checklist process.
+func (h *ChecklistHandler) Update(c echo.Context) error {
+	reqChecklist := new(Checklist)
+	if err := c.Bind(reqChecklist); err != nil {
+		return err
+	}
+	reqChecklist.ID = c.Param("checklist_id")
+	reqChecklist.ItemID = c.Param("id")
+
+	checklist := reqChecklist.convertTo()
+	checklist.UserID = getUserIDFromToken(c)
+
+	cl, err := h.intractor.Update(c.Request().Context(), checklist)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resChecklist := Checklist{}
+	resChecklist.convertFrom(cl)
+
+	return c.JSON(http.StatusOK, resChecklist)
+}
+
+// Move is http handler to move a checklist process.
+func (h *ChecklistHandler) Move(c echo.Context) error {
+	reqChecklist := new(Checklist)
+	if err := c.Bind(reqChecklist); err != nil {
+		return err
+	}
+	reqChecklist.ID = c.Param("checklist_id")
+	reqChecklist.ItemID = c.Param("id")
+
+	checklist := reqChecklist.convertTo()
+	checklist.UserID = getUserIDFromToken(c)
+
+	err := h.intractor.Move(c.Request().Context(), checklist)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Delete is http handler to delete a checklist process.
+func (h *ChecklistHandler) Delete(c echo.Context) error {
+	checklist := model.Checklist{
+		ID:     c.Param("checklist_id"),
+		ItemID: c.Param("id"),
+		UserID: getUserIDFromToken(c),
+	}
+
+	err := h.intractor.Delete(c.Request().Context(), checklist)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// CreateCheckItem is http handler to create a check item process.
+func (h *ChecklistHandler) CreateCheckItem(c echo.Context) error {
+	reqCheckItem := new(CheckItem)
+	if err := c.Bind(reqCheckItem); err != nil {
+		return err
+	}
+	reqCheckItem.ChecklistID = c.Param("checklist_id")
+
+	checkItem := reqCheckItem.convertTo()
+	checkItem.ItemID = c.Param("id")
+	checkItem.UserID = getUserIDFromToken(c)
+
+	ci, err := h.intractor.CreateCheckItem(c.Request().Context(), checkItem)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resCheckItem := CheckItem{}
+	resCheckItem.convertFrom(ci)
+
+	return c.JSON(http.StatusCreated, resCheckItem)
+}
+
+// UpdateCheckItem is http handler to update a check item process.
+func (h *ChecklistHandler) UpdateCheckItem(c echo.Context) error {
+	reqCheckItem := new(CheckItem)
+	if err := c.Bind(reqCheckItem); err != nil {
+		return err
+	}
+	reqCheckItem.ID = c.Param("checkitem_id")
+	reqCheckItem.ChecklistID = c.Param("checklist_id")
+
+	checkItem := reqCheckItem.convertTo()
+	checkItem.ItemID = c.Param("id")
+	checkItem.UserID = getUserIDFromToken(c)
+
+	ci, err := h.intractor.UpdateCheckItem(c.Request().Context(), checkItem)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resCheckItem := CheckItem{}
+	resCheckItem.convertFrom(ci)
+
+	return c.JSON(http.StatusOK, resCheckItem)
+}
+
+// MoveCheckItem is http handler to move a check item process.
+// 'checklist_id' in request body is a destination. It is the current checklist if it is empty.
+func (h *ChecklistHandler) MoveCheckItem(c echo.Context) error {
+	reqCheckItem := new(CheckItem)
+	if err := c.Bind(reqCheckItem); err != nil {
+		return err
+	}
+	reqCheckItem.ID = c.Param("checkitem_id")
+
+	checkItem := reqCheckItem.convertTo()
+	checkItem.ItemID = c.Param("id")
+	checkItem.UserID = getUserIDFromToken(c)
+
+	err := h.intractor.MoveCheckItem(c.Request().Context(), checkItem)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteCheckItem is http handler to delete a check item process.
+func (h *ChecklistHandler) DeleteCheckItem(c echo.Context) error {
+	checkItem := model.CheckItem{
+		ID:          c.Param("checkitem_id"),
+		ChecklistID: c.Param("checklist_id"),
+		ItemID:      c.Param("id"),
+		UserID:      getUserIDFromToken(c),
+	}
+
+	err := h.intractor.DeleteCheckItem(c.Request().Context(), checkItem)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
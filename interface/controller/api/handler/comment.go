@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Comment includes request data for Comment.
+// UserID, UserName, CreatedAt and UpdatedAt are only for response.
+type Comment struct {
+	ID        string    `json:"id"`
+	ItemID    string    `json:"item_id"`
+	UserID    string    `json:"user_id"`
+	UserName  string    `json:"name"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (c *Comment) convertTo() model.Comment {
+	comment := model.Comment{
+		ID:     c.ID,
+		ItemID: c.ItemID,
+		Text:   c.Text,
+	}
+
+	return comment
+}
+
+func (c *Comment) convertFrom(comment model.Comment) {
+	c.ID = comment.ID
+	c.ItemID = comment.ItemID
+	c.UserID = comment.UserID
+	c.UserName = comment.UserName
+	c.Text = comment.Text
+	c.CreatedAt = comment.CreatedAt
+	c.UpdatedAt = comment.UpdatedAt
+}
+
+// CommentHandler includes a interactor for Comment usecase.
+type CommentHandler struct {
+	intractor usecase.CommentUsecase
+}
+
+// NewCommentHandler returns a new CommentHandler.
+func NewCommentHandler(i usecase.CommentUsecase) CommentHandler {
+	return CommentHandler{
+		intractor: i,
+	}
+}
+
+// GetComments is http handler to get comments in a item process.
+// 'offset' and 'limit' query parameters select a page. 'limit' is 20 by default and 100 at most.
+// 'next' in response is offset of next page. It is null if there are no more comments.
+func (h *CommentHandler) GetComments(c echo.Context) error {
+	offset := 0
+	if o := c.QueryParam("offset"); o != "" {
+		v, err := strconv.Atoi(o)
+		if err != nil || v < 0 {
+			return echo.ErrBadRequest
+		}
+		offset = v
+	}
+	limit := 20
+	if l := c.QueryParam("limit"); l != "" {
+		v, err := strconv.Atoi(l)
+		if err != nil || v <= 0 || v > 100 {
+			return echo.ErrBadRequest
+		}
+		limit = v
+	}
+
+	item := model.Item{
+		ID:     c.Param("id"),
+		UserID: getUserIDFromToken(c),
+	}
+
+	comments, hasNext, err := h.intractor.GetComments(c.Request().Context(), item, offset, limit)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resComments := []Comment{}
+	for _, comment := range comments {
+		cm := Comment{}
+		cm.convertFrom(comment)
+		resComments = append(resComments, cm)
+	}
+
+	var next *int
+	if hasNext {
+		n := offset + limit
+		next = &n
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"comments": resComments,
+		"next":     next,
+	})
+}
+
+// Create is http handler to create a comment process.
+func (h *CommentHandler) Create(c echo.Context) error {
+	reqComment := new(Comment)
+	if err := c.Bind(reqComment); err != nil {
+		return err
+	}
+	reqComment.ItemID = c.Param("id")
+
+	comment := reqComment.convertTo()
+	comment.UserID = getUserIDFromToken(c)
+
+	cm, err := h.intractor.Create(c.Request().Context(), comment)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resComment := Comment{}
+	resComment.convertFrom(cm)
+
+	return c.JSON(http.StatusCreated, resComment)
+}
+
+// Update is http handler to update a comment process.
+func (h *CommentHandler) Update(c echo.Context) error {
+	reqComment := new(Comment)
+	if err := c.Bind(reqComment); err != nil {
+		return err
+	}
+	reqComment.ID = c.Param("comment_id")
+	reqComment.ItemID = c.Param("id")
+
+	comment := reqComment.convertTo()
+	comment.UserID = getUserIDFromToken(c)
+
+	cm, err := h.intractor.Update(c.Request().Context(), comment)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resComment := Comment{}
+	resComment.convertFrom(cm)
+
+	return c.JSON(http.StatusOK, resComment)
+}
+
+// Delete is http handler to delete a comment process.
+func (h *CommentHandler) Delete(c echo.Context) error {
+	comment := model.Comment{
+		ID:     c.Param("comment_id"),
+		ItemID: c.Param("id"),
+		UserID: getUserIDFromToken(c),
+	}
+
+	err := h.intractor.Delete(c.Request().Context(), comment)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
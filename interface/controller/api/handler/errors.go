@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -9,16 +10,59 @@ import (
 )
 
 func convertToHTTPError(c echo.Context, err error) error {
+	var stale model.PreconditionFailedError
+	if errors.As(err, &stale) {
+		return preconditionFailed(c, stale)
+	}
+
+	return httpErrorOf(err)
+}
+
+// httpErrorOf returns an HTTP error for an error of usecases.
+func httpErrorOf(err error) *echo.HTTPError {
 	switch {
+	case errors.Is(err, model.PreconditionFailedError{}):
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "resource has been changed")
 	case errors.Is(err, model.ConflictError{}):
 		return echo.NewHTTPError(http.StatusConflict, "resource already exists")
 	case errors.Is(err, model.InvalidContentError{}):
 		return echo.ErrBadRequest
 	case errors.Is(err, model.NotFoundError{}):
 		return echo.ErrBadRequest
+	case errors.Is(err, model.ForbiddenError{}):
+		return echo.ErrForbidden
+	case errors.Is(err, model.TimeoutError{}):
+		if errors.Is(err, context.DeadlineExceeded) {
+			return echo.NewHTTPError(http.StatusGatewayTimeout, "request timed out")
+		}
+		return echo.ErrServiceUnavailable
 	case errors.Is(err, model.ServerError{}):
 		return echo.ErrInternalServerError
 	default:
 		return echo.ErrInternalServerError
 	}
 }
+
+// preconditionFailed responds a current content of PreconditionFailedError with its ETag, so that
+// a client can merge its changes into it. Only a status is responded if the content is unknown.
+func preconditionFailed(c echo.Context, err model.PreconditionFailedError) error {
+	switch v := err.Current.(type) {
+	case model.Board:
+		b := Board{}
+		b.convertFrom(v)
+		setETag(c, v.Version)
+		return c.JSON(http.StatusPreconditionFailed, b)
+	case model.List:
+		l := List{}
+		l.convertFrom(v)
+		setETag(c, v.Version)
+		return c.JSON(http.StatusPreconditionFailed, l)
+	case model.Item:
+		i := Item{}
+		i.convertFrom(v)
+		setETag(c, v.Version)
+		return c.JSON(http.StatusPreconditionFailed, i)
+	default:
+		return httpErrorOf(err)
+	}
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo"
+)
+
+// setETag sets ETag header made from a version of a content.
+func setETag(c echo.Context, version int) {
+	c.Response().Header().Set("ETag", `"`+strconv.Itoa(version)+`"`)
+}
+
+// setContentETag sets ETag header made from a version of a content and a digest of its body, e.g. "3.5d41402abc4b2a76".
+// It is used for a content including other data, so the ETag changes when the data is changed without its version.
+// The ETag is returned to be compared with If-None-Match header.
+func setContentETag(c echo.Context, version int, body []byte) string {
+	sum := sha256.Sum256(body)
+	etag := `"` + strconv.Itoa(version) + "." + hex.EncodeToString(sum[:8]) + `"`
+	c.Response().Header().Set("ETag", etag)
+	return etag
+}
+
+// ifMatch returns a version expected by If-Match header. It is zero if the header is not given or "*".
+// A digest in an ETag set by setContentETag is ignored, because only the version is changed by a request.
+// A weak or malformed ETag can not match any version, so -1 is returned for it.
+func ifMatch(c echo.Context) int {
+	v := strings.TrimSpace(c.Request().Header.Get("If-Match"))
+	if v == "" || v == "*" {
+		return 0
+	}
+	if len(v) < 2 || !strings.HasPrefix(v, `"`) || !strings.HasSuffix(v, `"`) {
+		return -1
+	}
+	v = v[1 : len(v)-1]
+	if i := strings.Index(v, "."); i >= 0 {
+		v = v[:i]
+	}
+
+	version, err := strconv.Atoi(v)
+	if err != nil || version <= 0 {
+		return -1
+	}
+	return version
+}
+
+// ifNoneMatch checks that If-None-Match header has etag or "*". ETags are compared weakly as for GET requests.
+func ifNoneMatch(c echo.Context, etag string) bool {
+	for _, v := range strings.Split(c.Request().Header.Get("If-None-Match"), ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == "*" || v == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// boardUsecase keeps a Board and updates it with optimistic concurrency control like BoardInteractor.
+type boardUsecase struct {
+	usecase.BoardUsecase
+	board model.Board
+}
+
+func (u *boardUsecase) Get(ctx context.Context, board model.Board) (model.Board, error) {
+	return u.board, nil
+}
+
+func (u *boardUsecase) Update(ctx context.Context, board model.Board) (model.Board, error) {
+	if board.Version != 0 && board.Version != u.board.Version {
+		return model.Board{}, model.PreconditionFailedError{UserID: board.UserID, ID: board.ID, Act: "update board", Current: u.board}
+	}
+	u.board.Title = board.Title
+	u.board.Version++
+	return u.board, nil
+}
+
+func newBoardServer(u *boardUsecase) *echo.Echo {
+	h := NewBoardHandler(u)
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set("user", &jwt.Token{Claims: &jwt.StandardClaims{Subject: "u1"}})
+			return next(c)
+		}
+	})
+	e.GET("/api/boards/:id", h.Get)
+	e.PATCH("/api/boards/:id", h.Update)
+	return e
+}
+
+// request sends a request with headers given as pairs of a name and a value.
+func request(e *echo.Echo, method, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/boards/b1", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	for j := 0; j+1 < len(headers); j += 2 {
+		req.Header.Set(headers[j], headers[j+1])
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIfMatch(t *testing.T) {
+	u := &boardUsecase{board: model.Board{ID: "b1", UserID: "u1", Title: "board", Color: model.RED, Version: 4}}
+	e := newBoardServer(u)
+
+	tests := []struct {
+		name       string
+		ifMatch    string
+		title      string
+		wantStatus int
+		wantETag   string
+		wantTitle  string
+	}{
+		// A stale version is responded with the current Board and its ETag
+		{"stale", `"3"`, "stale", http.StatusPreconditionFailed, `"4"`, "board"},
+		{"weak", `W/"4"`, "weak", http.StatusPreconditionFailed, `"4"`, "board"},
+		{"malformed", `4`, "malformed", http.StatusPreconditionFailed, `"4"`, "board"},
+		{"current", `"4"`, "first", http.StatusOK, `"5"`, "first"},
+		// A digest of an ETag got with lists and items is ignored
+		{"content", `"5.0123456789abcdef"`, "second", http.StatusOK, `"6"`, "second"},
+		{"any", `*`, "third", http.StatusOK, `"7"`, "third"},
+		{"none", ``, "fourth", http.StatusOK, `"8"`, "fourth"},
+	}
+	for _, tt := range tests {
+		headers := []string{}
+		if tt.ifMatch != "" {
+			headers = append(headers, "If-Match", tt.ifMatch)
+		}
+		rec := request(e, http.MethodPatch, `{"title":"`+tt.title+`","color":"red"}`, headers...)
+
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.wantStatus)
+		}
+		if etag := rec.Header().Get("ETag"); etag != tt.wantETag {
+			t.Errorf("%s: ETag = %s, want %s", tt.name, etag, tt.wantETag)
+		}
+		b := Board{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &b); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if b.Title != tt.wantTitle || `"`+strconv.Itoa(b.Version)+`"` != tt.wantETag {
+			t.Errorf("%s: board = %+v, want title %s and version of ETag %s", tt.name, b, tt.wantTitle, tt.wantETag)
+		}
+	}
+}
+
+func TestIfNoneMatch(t *testing.T) {
+	u := &boardUsecase{board: model.Board{
+		ID:      "b1",
+		UserID:  "u1",
+		Title:   "board",
+		Color:   model.RED,
+		Version: 4,
+		Lists:   model.Lists{{ID: "l1", Title: "list", Version: 1}},
+	}}
+	e := newBoardServer(u)
+
+	rec := request(e, http.MethodGet, "")
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || !regexp.MustCompile(`^"4\.[0-9a-f]{16}"$`).MatchString(etag) {
+		t.Fatalf("GET = %d with ETag %s, want 200 with ETag of version 4 and a digest", rec.Code, etag)
+	}
+
+	// A Board not changed is not responded
+	for _, ifNoneMatch := range []string{etag, "W/" + etag, `"1", ` + etag, "*"} {
+		rec := request(e, http.MethodGet, "", "If-None-Match", ifNoneMatch)
+		if rec.Code != http.StatusNotModified || rec.Header().Get("ETag") != etag || rec.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s: GET = %d with ETag %s and %q, want 304 with ETag %s and no body",
+				ifNoneMatch, rec.Code, rec.Header().Get("ETag"), rec.Body.String(), etag)
+		}
+	}
+	if rec := request(e, http.MethodGet, "", "If-None-Match", `"4"`); rec.Code != http.StatusOK {
+		t.Errorf(`If-None-Match "4": GET = %d, want 200`, rec.Code)
+	}
+
+	// A change of a List changes the ETag even if the version of the Board is the same
+	u.board.Lists[0].Title = "changed"
+	rec = request(e, http.MethodGet, "", "If-None-Match", etag)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag || !strings.HasPrefix(rec.Header().Get("ETag"), `"4.`) {
+		t.Errorf("GET of changed list = %d with ETag %s, want 200 with new ETag of version 4", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	// A change of the Board changes the ETag
+	rec = request(e, http.MethodPatch, `{"title":"changed","color":"red"}`, "If-Match", etag)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PATCH = %d, want 200", rec.Code)
+	}
+	rec = request(e, http.MethodGet, "", "If-None-Match", etag)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("ETag"), `"5.`) {
+		t.Errorf("GET of changed board = %d with ETag %s, want 200 with ETag of version 5", rec.Code, rec.Header().Get("ETag"))
+	}
+}
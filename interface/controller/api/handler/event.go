@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// keepAliveInterval is an interval of comments written to keep an idle stream open through proxies.
+const keepAliveInterval = 15 * time.Second
+
+// EventHandler includes a interactor for Event usecase.
+type EventHandler struct {
+	intractor usecase.EventUsecase
+}
+
+// NewEventHandler returns a new EventHandler.
+func NewEventHandler(e usecase.EventUsecase) *EventHandler {
+	return &EventHandler{
+		intractor: e,
+	}
+}
+
+// Stream is http handler to stream changes in a board as Server-Sent Events process.
+// Each event has ID of an Activity, a type of "<target>.<action>" (e.g. "item.move") and the Activity as data.
+// Events after an Activity are resumed by Last-Event-ID header, or by 'cursor' query parameter.
+func (h *EventHandler) Stream(c echo.Context) error {
+	cursor := c.Request().Header.Get("Last-Event-ID")
+	if cursor == "" {
+		cursor = c.QueryParam("cursor")
+	}
+
+	user := model.User{ID: getUserIDFromToken(c)}
+	board := model.Board{ID: c.Param("id")}
+
+	s := &eventStream{c: c}
+	err := h.intractor.Stream(c.Request().Context(), user, board, cursor, s)
+	s.close()
+
+	// An error can not be responded after the stream is opened.
+	if err != nil && !s.opened {
+		return convertToHTTPError(c, err)
+	}
+	return nil
+}
+
+// eventStream writes Activities to a response as Server-Sent Events.
+type eventStream struct {
+	c      echo.Context
+	mu     sync.Mutex
+	opened bool
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func (s *eventStream) Open() error {
+	res := s.c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	s.opened = true
+	s.stop = make(chan struct{})
+	s.wg.Add(1)
+	go s.keepAlive()
+	return nil
+}
+
+func (s *eventStream) Send(activity model.Activity) error {
+	a := Activity{}
+	a.convertFrom(activity)
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	return s.write(fmt.Sprintf("id: %s\nevent: %s\ndata: %s\n\n", activity.ID, activity.Event(), data))
+}
+
+func (s *eventStream) keepAlive() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if err := s.write(": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *eventStream) write(msg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := s.c.Response()
+	if _, err := res.Write([]byte(msg)); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
+
+// close stops writing comments. Nothing is written to the response after it returns.
+func (s *eventStream) close() {
+	if s.opened {
+		close(s.stop)
+		s.wg.Wait()
+	}
+}
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// EXPORTVERSION is a version of the JSON schema of an exported Board.
+// It is increased if the schema is changed incompatibly. The schema is documented in docs/export.md.
+const EXPORTVERSION = 1
+
+// BoardExport includes data of an exported Board as JSON.
+// It is also request data to import a Board exported by this server.
+type BoardExport struct {
+	Version int          `json:"version"`
+	ID      string       `json:"id"`
+	Title   string       `json:"title"`
+	Text    string       `json:"text"`
+	Color   string       `json:"color"`
+	Lists   []ListExport `json:"lists"`
+}
+
+// ListExport includes data of an exported List.
+type ListExport struct {
+	ID    string       `json:"id"`
+	Title string       `json:"title"`
+	Items []ItemExport `json:"items"`
+}
+
+// ItemExport includes data of an exported Item.
+// StartDate and DueDate are null if they are not set.
+type ItemExport struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Text      string     `json:"text"`
+	Tags      []Tag      `json:"tags"`
+	StartDate *time.Time `json:"start_date"`
+	DueDate   *time.Time `json:"due_date"`
+	Completed bool       `json:"completed"`
+}
+
+func (b *BoardExport) convertTo() model.Board {
+	board := model.Board{
+		ID:    b.ID,
+		Title: b.Title,
+		Text:  b.Text,
+		Color: model.Color(b.Color),
+		Lists: model.Lists{},
+	}
+	for _, l := range b.Lists {
+		list := model.List{
+			ID:    l.ID,
+			Title: l.Title,
+			Items: model.Items{},
+		}
+		for _, i := range l.Items {
+			item := model.Item{
+				ID:        i.ID,
+				Title:     i.Title,
+				Text:      i.Text,
+				Tags:      model.Tags{},
+				Completed: i.Completed,
+			}
+			for _, t := range i.Tags {
+				item.Tags = append(item.Tags, model.Tag{ID: t.ID, Name: t.Name, Color: model.Color(t.Color)})
+			}
+			if i.StartDate != nil {
+				item.StartDate = *i.StartDate
+			}
+			if i.DueDate != nil {
+				item.DueDate = *i.DueDate
+			}
+			list.Items = append(list.Items, item)
+		}
+		board.Lists = append(board.Lists, list)
+	}
+	return board
+}
+
+func (b *BoardExport) convertFrom(board model.Board) {
+	b.Version = EXPORTVERSION
+	b.ID = board.ID
+	b.Title = board.Title
+	b.Text = board.Text
+	b.Color = string(board.Color)
+	b.Lists = []ListExport{}
+	for _, l := range board.Lists {
+		list := ListExport{
+			ID:    l.ID,
+			Title: l.Title,
+			Items: []ItemExport{},
+		}
+		for _, i := range l.Items {
+			item := ItemExport{
+				ID:        i.ID,
+				Title:     i.Title,
+				Text:      i.Text,
+				Tags:      []Tag{},
+				Completed: i.Completed,
+			}
+			for _, t := range i.Tags {
+				item.Tags = append(item.Tags, Tag{ID: t.ID, Name: t.Name, Color: string(t.Color)})
+			}
+			if !i.StartDate.IsZero() {
+				t := i.StartDate
+				item.StartDate = &t
+			}
+			if !i.DueDate.IsZero() {
+				t := i.DueDate
+				item.DueDate = &t
+			}
+			list.Items = append(list.Items, item)
+		}
+		b.Lists = append(b.Lists, list)
+	}
+}
+
+// ExportHandler includes a interactor for Export usecase.
+type ExportHandler struct {
+	intractor usecase.ExportUsecase
+}
+
+// NewExportHandler returns a new ExportHandler.
+func NewExportHandler(i usecase.ExportUsecase) *ExportHandler {
+	return &ExportHandler{
+		intractor: i,
+	}
+}
+
+// Export is http handler to export a board process.
+// 'format' query parameter is one of 'json', 'csv' and 'markdown'. A board is written to response as an attachment.
+func (h *ExportHandler) Export(c echo.Context) error {
+	format := c.QueryParam("format")
+	var contentType, ext string
+	switch format {
+	case "json":
+		contentType, ext = echo.MIMEApplicationJSONCharsetUTF8, "json"
+	case "csv":
+		contentType, ext = "text/csv; charset=UTF-8", "csv"
+	case "markdown":
+		contentType, ext = "text/markdown; charset=UTF-8", "md"
+	default:
+		return echo.ErrBadRequest
+	}
+
+	board := model.Board{
+		ID:     c.Param("id"),
+		UserID: getUserIDFromToken(c),
+	}
+
+	b, err := h.intractor.Export(c.Request().Context(), board)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, contentType)
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"board-%s.%s\"", b.ID, ext))
+	res.WriteHeader(http.StatusOK)
+
+	switch format {
+	case "json":
+		resBoard := BoardExport{}
+		resBoard.convertFrom(b)
+		return json.NewEncoder(res).Encode(resBoard)
+	case "csv":
+		return writeBoardCSV(res, b)
+	default:
+		return writeBoardMarkdown(res, b)
+	}
+}
+
+// writeBoardCSV writes Items in a Board as CSV. A row is an Item and Items are written in order of Lists.
+func writeBoardCSV(res *echo.Response, board model.Board) error {
+	w := csv.NewWriter(res)
+	header := []string{"list", "title", "text", "tags", "start_date", "due_date", "completed"}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	for _, l := range board.Lists {
+		for _, i := range l.Items {
+			tags := []string{}
+			for _, t := range i.Tags {
+				tags = append(tags, t.Name)
+			}
+			record := []string{
+				l.Title,
+				i.Title,
+				i.Text,
+				strings.Join(tags, ";"),
+				formatDate(i.StartDate),
+				formatDate(i.DueDate),
+				fmt.Sprint(i.Completed),
+			}
+			if err := w.Write(record); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// writeBoardMarkdown writes a Board as Markdown. Lists are headings and Items are task lists.
+func writeBoardMarkdown(res *echo.Response, board model.Board) error {
+	if _, err := fmt.Fprintf(res, "# %s\n", board.Title); err != nil {
+		return err
+	}
+	if board.Text != "" {
+		fmt.Fprintf(res, "\n%s\n", board.Text)
+	}
+	for _, l := range board.Lists {
+		fmt.Fprintf(res, "\n## %s\n\n", l.Title)
+		for _, i := range l.Items {
+			check := " "
+			if i.Completed {
+				check = "x"
+			}
+			line := fmt.Sprintf("- [%s] %s", check, i.Title)
+			for _, t := range i.Tags {
+				line += fmt.Sprintf(" `%s`", t.Name)
+			}
+			if !i.DueDate.IsZero() {
+				line += " (due " + formatDate(i.DueDate) + ")"
+			}
+			if _, err := fmt.Fprintln(res, line); err != nil {
+				return err
+			}
+			if i.Text != "" {
+				fmt.Fprintf(res, "\n  %s\n\n", strings.ReplaceAll(i.Text, "\n", "\n  "))
+			}
+		}
+		res.Flush()
+	}
+	return nil
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
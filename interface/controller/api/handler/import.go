@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Skip includes response data for data skipped by an import.
+type Skip struct {
+	Target string `json:"target"`
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+func (s *Skip) convertFrom(skip model.Skip) {
+	s.Target = string(skip.Target)
+	s.ID = skip.ID
+	s.Name = skip.Name
+	s.Reason = skip.Reason
+}
+
+// ImportHandler includes a interactor for Import usecase.
+type ImportHandler struct {
+	intractor usecase.ImportUsecase
+}
+
+// NewImportHandler returns a new ImportHandler.
+func NewImportHandler(i usecase.ImportUsecase) *ImportHandler {
+	return &ImportHandler{
+		intractor: i,
+	}
+}
+
+// Import is http handler to import a board process.
+// 'format' query parameter is a format of request body. It is 'json' (a board exported by this server as JSON)
+// or 'trello' (a board exported from Trello as JSON).
+// 'skipped' in response includes data which is not imported or changed to be imported.
+func (h *ImportHandler) Import(c echo.Context) error {
+	board := model.Board{}
+	skips := model.Skips{}
+
+	switch c.QueryParam("format") {
+	case "json":
+		reqBoard := new(BoardExport)
+		if err := c.Bind(reqBoard); err != nil {
+			return err
+		}
+		if reqBoard.Version != EXPORTVERSION {
+			return echo.NewHTTPError(http.StatusBadRequest, "unsupported version")
+		}
+		board = reqBoard.convertTo()
+	case "trello":
+		reqBoard := new(trelloBoard)
+		if err := c.Bind(reqBoard); err != nil {
+			return err
+		}
+		board, skips = reqBoard.convertTo()
+	default:
+		return echo.ErrBadRequest
+	}
+	board.UserID = getUserIDFromToken(c)
+
+	b, s, err := h.intractor.Import(c.Request().Context(), board)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resBoard := Board{}
+	resBoard.convertFrom(b)
+
+	resSkips := []Skip{}
+	for _, skip := range append(skips, s...) {
+		rs := Skip{}
+		rs.convertFrom(skip)
+		resSkips = append(resSkips, rs)
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"board":   resBoard,
+		"skipped": resSkips,
+	})
+}
@@ -2,6 +2,8 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/labstack/echo"
 	"github.com/x-color/vue-trello/model"
@@ -9,14 +11,23 @@ import (
 )
 
 // Item includes request data for Item.
+// StartDate and DueDate are null if they are not set.
+// CheckItemsDone, CheckItemsTotal and Version are only for response.
+// A version expected by a request is given by If-Match header.
 type Item struct {
-	ID     string   `json:"id"`
-	ListID string   `json:"list_id"`
-	Title  string   `json:"title"`
-	Text   string   `json:"text"`
-	Tags   []string `json:"tags"`
-	Before string   `json:"before"`
-	After  string   `json:"after"`
+	ID              string     `json:"id"`
+	ListID          string     `json:"list_id"`
+	Title           string     `json:"title"`
+	Text            string     `json:"text"`
+	Tags            []string   `json:"tags"`
+	StartDate       *time.Time `json:"start_date"`
+	DueDate         *time.Time `json:"due_date"`
+	Completed       bool       `json:"completed"`
+	CheckItemsDone  int        `json:"check_items_done"`
+	CheckItemsTotal int        `json:"check_items_total"`
+	Before          string     `json:"before"`
+	Rank            string     `json:"rank"`
+	Version         int        `json:"version"`
 }
 
 func (i *Item) convertTo() model.Item {
@@ -26,13 +37,20 @@ func (i *Item) convertTo() model.Item {
 	}
 
 	item := model.Item{
-		ID:     i.ID,
-		ListID: i.ListID,
-		Title:  i.Title,
-		Text:   i.Text,
-		Tags:   tags,
-		Before: i.Before,
-		After:  i.After,
+		ID:        i.ID,
+		ListID:    i.ListID,
+		Title:     i.Title,
+		Text:      i.Text,
+		Tags:      tags,
+		Completed: i.Completed,
+		Before:    i.Before,
+	}
+
+	if i.StartDate != nil {
+		item.StartDate = *i.StartDate
+	}
+	if i.DueDate != nil {
+		item.DueDate = *i.DueDate
 	}
 
 	return item
@@ -49,8 +67,22 @@ func (i *Item) convertFrom(item model.Item) {
 	i.Title = item.Title
 	i.Text = item.Text
 	i.Tags = tags
-	i.Before = item.Before
-	i.After = item.After
+	i.Completed = item.Completed
+	i.CheckItemsDone = item.CheckItemsDone
+	i.CheckItemsTotal = item.CheckItemsTotal
+	i.Rank = item.Rank
+	i.Version = item.Version
+
+	i.StartDate = nil
+	if !item.StartDate.IsZero() {
+		t := item.StartDate
+		i.StartDate = &t
+	}
+	i.DueDate = nil
+	if !item.DueDate.IsZero() {
+		t := item.DueDate
+		i.DueDate = &t
+	}
 }
 
 // ItemHandler includes a interactor for Item usecase.
@@ -75,13 +107,14 @@ func (h *ItemHandler) Create(c echo.Context) error {
 	item := reqItem.convertTo()
 	item.UserID = getUserIDFromToken(c)
 
-	i, err := h.intractor.Create(item)
+	i, err := h.intractor.Create(c.Request().Context(), item)
 	if err != nil {
 		return convertToHTTPError(c, err)
 	}
 
 	resItem := Item{}
 	resItem.convertFrom(i)
+	setETag(c, i.Version)
 
 	return c.JSON(http.StatusCreated, resItem)
 }
@@ -96,14 +129,16 @@ func (h *ItemHandler) Update(c echo.Context) error {
 
 	item := reqItem.convertTo()
 	item.UserID = getUserIDFromToken(c)
+	item.Version = ifMatch(c)
 
-	i, err := h.intractor.Update(item)
+	i, err := h.intractor.Update(c.Request().Context(), item)
 	if err != nil {
 		return convertToHTTPError(c, err)
 	}
 
 	resItem := Item{}
 	resItem.convertFrom(i)
+	setETag(c, i.Version)
 
 	return c.JSON(http.StatusOK, resItem)
 }
@@ -118,11 +153,13 @@ func (h *ItemHandler) Move(c echo.Context) error {
 
 	item := reqItem.convertTo()
 	item.UserID = getUserIDFromToken(c)
+	item.Version = ifMatch(c)
 
-	err := h.intractor.Move(item)
+	i, err := h.intractor.Move(c.Request().Context(), item)
 	if err != nil {
 		return convertToHTTPError(c, err)
 	}
+	setETag(c, i.Version)
 
 	return c.NoContent(http.StatusNoContent)
 }
@@ -137,11 +174,49 @@ func (h *ItemHandler) Delete(c echo.Context) error {
 
 	item := reqItem.convertTo()
 	item.UserID = getUserIDFromToken(c)
+	item.Version = ifMatch(c)
 
-	err := h.intractor.Delete(item)
+	err := h.intractor.Delete(c.Request().Context(), item)
 	if err != nil {
 		return convertToHTTPError(c, err)
 	}
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// GetDue is http handler to get overdue and upcoming items process.
+// 'days' query parameter is a period of upcoming items. It is 7 days by default.
+func (h *ItemHandler) GetDue(c echo.Context) error {
+	days := 7
+	if d := c.QueryParam("days"); d != "" {
+		v, err := strconv.Atoi(d)
+		if err != nil || v < 0 {
+			return echo.ErrBadRequest
+		}
+		days = v
+	}
+
+	user := model.User{ID: getUserIDFromToken(c)}
+	overdue, upcoming, err := h.intractor.GetDue(c.Request().Context(), user, time.Duration(days)*24*time.Hour)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resOverdue := []Item{}
+	for _, item := range overdue {
+		i := Item{}
+		i.convertFrom(item)
+		resOverdue = append(resOverdue, i)
+	}
+	resUpcoming := []Item{}
+	for _, item := range upcoming {
+		i := Item{}
+		i.convertFrom(item)
+		resUpcoming = append(resUpcoming, i)
+	}
+
+	return c.JSON(http.StatusOK, map[string][]Item{
+		"overdue":  resOverdue,
+		"upcoming": resUpcoming,
+	})
+}
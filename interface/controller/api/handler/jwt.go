@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Keys includes secrets to sign and verify JWT by their IDs ('kid' header of JWT).
+// JWT is signed by the key of signingKID and verified by the key named in its header,
+// so that a key can be rotated by adding a new signing key and removing the old one later.
+type Keys struct {
+	signingKID string
+	secrets    map[string][]byte
+}
+
+// NewKeys returns Keys. secrets must include a key of signingKID.
+func NewKeys(signingKID string, secrets map[string][]byte) (Keys, error) {
+	if _, ok := secrets[signingKID]; !ok {
+		return Keys{}, fmt.Errorf("signing key %q is not found", signingKID)
+	}
+
+	k := Keys{
+		signingKID: signingKID,
+		secrets:    map[string][]byte{},
+	}
+	for kid, secret := range secrets {
+		if len(secret) == 0 {
+			return Keys{}, fmt.Errorf("key %q is empty", kid)
+		}
+		k.secrets[kid] = secret
+	}
+	return k, nil
+}
+
+// sign returns JWT of claims signed by the signing key.
+func (k Keys) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = k.signingKID
+	return token.SignedString(k.secrets[k.signingKID])
+}
+
+// parse verifies JWT by the key named in its header and returns it.
+func (k Keys) parse(s string) (*jwt.Token, error) {
+	token, err := jwt.ParseWithClaims(s, &jwt.StandardClaims{}, func(t *jwt.Token) (interface{}, error) {
+		if t.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		kid, _ := t.Header["kid"].(string)
+		secret, ok := k.secrets[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q", kid)
+		}
+		return secret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return token, nil
+}
@@ -9,13 +9,15 @@ import (
 )
 
 // List includes request data for List.
+// Version is only for response. A version expected by a request is given by If-Match header.
 type List struct {
 	ID      string `json:"id"`
 	BoardID string `json:"board_id"`
 	Title   string `json:"title"`
 	Items   []Item `json:"items"`
 	Before  string `json:"before"`
-	After   string `json:"after"`
+	Rank    string `json:"rank"`
+	Version int    `json:"version"`
 }
 
 func (l *List) convertTo() model.List {
@@ -24,7 +26,6 @@ func (l *List) convertTo() model.List {
 		BoardID: l.BoardID,
 		Title:   l.Title,
 		Before:  l.Before,
-		After:   l.After,
 	}
 
 	return list
@@ -34,8 +35,8 @@ func (l *List) convertFrom(list model.List) {
 	l.ID = list.ID
 	l.BoardID = list.BoardID
 	l.Title = list.Title
-	l.Before = list.Before
-	l.After = list.After
+	l.Rank = list.Rank
+	l.Version = list.Version
 
 	items := []Item{}
 	for _, i := range list.Items {
@@ -69,13 +70,14 @@ func (h *ListHandler) Create(c echo.Context) error {
 	list := reqList.convertTo()
 	list.UserID = getUserIDFromToken(c)
 
-	l, err := h.intractor.Create(list)
+	l, err := h.intractor.Create(c.Request().Context(), list)
 	if err != nil {
 		return convertToHTTPError(c, err)
 	}
 
 	resList := List{}
 	resList.convertFrom(l)
+	setETag(c, l.Version)
 
 	return c.JSON(http.StatusCreated, resList)
 }
@@ -90,14 +92,16 @@ func (h *ListHandler) Update(c echo.Context) error {
 
 	list := reqList.convertTo()
 	list.UserID = getUserIDFromToken(c)
+	list.Version = ifMatch(c)
 
-	l, err := h.intractor.Update(list)
+	l, err := h.intractor.Update(c.Request().Context(), list)
 	if err != nil {
 		return convertToHTTPError(c, err)
 	}
 
 	resList := List{}
 	resList.convertFrom(l)
+	setETag(c, l.Version)
 
 	return c.JSON(http.StatusOK, resList)
 }
@@ -112,11 +116,13 @@ func (h *ListHandler) Move(c echo.Context) error {
 
 	list := reqList.convertTo()
 	list.UserID = getUserIDFromToken(c)
+	list.Version = ifMatch(c)
 
-	err := h.intractor.Move(list)
+	l, err := h.intractor.Move(c.Request().Context(), list)
 	if err != nil {
 		return convertToHTTPError(c, err)
 	}
+	setETag(c, l.Version)
 
 	return c.NoContent(http.StatusNoContent)
 }
@@ -131,8 +137,9 @@ func (h *ListHandler) Delete(c echo.Context) error {
 
 	list := reqList.convertTo()
 	list.UserID = getUserIDFromToken(c)
+	list.Version = ifMatch(c)
 
-	err := h.intractor.Delete(list)
+	err := h.intractor.Delete(c.Request().Context(), list)
 	if err != nil {
 		return convertToHTTPError(c, err)
 	}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Member includes request data for Member.
+type Member struct {
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+	Role   string `json:"role"`
+}
+
+func (m *Member) convertTo() model.Member {
+	member := model.Member{
+		UserID:   m.UserID,
+		UserName: m.Name,
+		Role:     model.Role(m.Role),
+	}
+
+	return member
+}
+
+func (m *Member) convertFrom(member model.Member) {
+	m.UserID = member.UserID
+	m.Name = member.UserName
+	m.Role = string(member.Role)
+}
+
+// MemberHandler includes a interactor for Member usecase.
+type MemberHandler struct {
+	intractor usecase.MemberUsecase
+}
+
+// NewMemberHandler returns a new MemberHandler.
+func NewMemberHandler(m usecase.MemberUsecase) MemberHandler {
+	return MemberHandler{
+		intractor: m,
+	}
+}
+
+// GetMembers is http handler to get members of a board process.
+func (h *MemberHandler) GetMembers(c echo.Context) error {
+	user := model.User{ID: getUserIDFromToken(c)}
+	board := model.Board{ID: c.Param("id")}
+
+	members, err := h.intractor.GetMembers(c.Request().Context(), user, board)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resMembers := []Member{}
+	for _, member := range members {
+		m := Member{}
+		m.convertFrom(member)
+		resMembers = append(resMembers, m)
+	}
+
+	return c.JSON(http.StatusOK, map[string][]Member{
+		"members": resMembers},
+	)
+}
+
+// Invite is http handler to invite a user to a board process.
+func (h *MemberHandler) Invite(c echo.Context) error {
+	reqMember := new(Member)
+	if err := c.Bind(reqMember); err != nil {
+		return err
+	}
+
+	member := reqMember.convertTo()
+	member.BoardID = c.Param("id")
+	user := model.User{ID: getUserIDFromToken(c)}
+
+	m, err := h.intractor.Invite(c.Request().Context(), user, member)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resMember := Member{}
+	resMember.convertFrom(m)
+
+	return c.JSON(http.StatusCreated, resMember)
+}
+
+// Update is http handler to change a role of a member process.
+func (h *MemberHandler) Update(c echo.Context) error {
+	reqMember := new(Member)
+	if err := c.Bind(reqMember); err != nil {
+		return err
+	}
+	reqMember.UserID = c.Param("user_id")
+
+	member := reqMember.convertTo()
+	member.BoardID = c.Param("id")
+	user := model.User{ID: getUserIDFromToken(c)}
+
+	m, err := h.intractor.Update(c.Request().Context(), user, member)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resMember := Member{}
+	resMember.convertFrom(m)
+
+	return c.JSON(http.StatusOK, resMember)
+}
+
+// Remove is http handler to remove a member from a board process.
+func (h *MemberHandler) Remove(c echo.Context) error {
+	member := model.Member{
+		BoardID: c.Param("id"),
+		UserID:  c.Param("user_id"),
+	}
+	user := model.User{ID: getUserIDFromToken(c)}
+
+	err := h.intractor.Remove(c.Request().Context(), user, member)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
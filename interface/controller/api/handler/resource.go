@@ -8,11 +8,30 @@ import (
 	"github.com/x-color/vue-trello/usecase"
 )
 
-// Tag includes response data for Tag.
+// Tag includes request and response data for Tag.
+// BoardID is empty if a Tag does not belong to a Board.
 type Tag struct {
-	ID    string `json:"id"`
-	Name  string `json:"title"`
-	Color string `json:"color"`
+	ID      string `json:"id"`
+	BoardID string `json:"board_id"`
+	Name    string `json:"title"`
+	Color   string `json:"color"`
+}
+
+func (t *Tag) convertTo() model.Tag {
+	tag := model.Tag{
+		ID:      t.ID,
+		BoardID: t.BoardID,
+		Name:    t.Name,
+		Color:   model.Color(t.Color),
+	}
+	return tag
+}
+
+func (t *Tag) convertFrom(tag model.Tag) {
+	t.ID = tag.ID
+	t.BoardID = tag.BoardID
+	t.Name = tag.Name
+	t.Color = string(tag.Color)
 }
 
 // Resources includes response data for Resources.
@@ -24,11 +43,9 @@ type Resources struct {
 func (r *Resources) convertFrom(tags model.Tags, colors model.Colors) {
 	r.Tags = []Tag{}
 	for _, tag := range tags {
-		r.Tags = append(r.Tags, Tag{
-			ID:    tag.ID,
-			Name:  tag.Name,
-			Color: string(tag.Color),
-		})
+		t := Tag{}
+		t.convertFrom(tag)
+		r.Tags = append(r.Tags, t)
 	}
 
 	cs := []string{}
@@ -53,7 +70,9 @@ func NewResourceHandler(r usecase.ResourceUsecase) ResourceHandler {
 
 // Get is http handler to get resources process.
 func (h *ResourceHandler) Get(c echo.Context) error {
-	tags, colors, err := h.intractor.GetAllTagsandColors()
+	user := model.User{ID: getUserIDFromToken(c)}
+
+	tags, colors, err := h.intractor.GetAllTagsandColors(c.Request().Context(), user)
 	if err != nil {
 		return convertToHTTPError(c, err)
 	}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Change includes response data for a changed Board, List or Item. Data is null if it is deleted.
+type Change struct {
+	Target  string      `json:"target"`
+	ID      string      `json:"id"`
+	Deleted bool        `json:"deleted"`
+	Data    interface{} `json:"data"`
+}
+
+// Feed includes response data for changes since a cursor.
+type Feed struct {
+	Cursor  string   `json:"cursor"`
+	More    bool     `json:"more"`
+	Boards  []string `json:"boards"`
+	Changes []Change `json:"changes"`
+}
+
+func (f *Feed) convertFrom(feed usecase.Feed) {
+	f.Cursor = feed.Cursor
+	f.More = feed.More
+	f.Boards = append([]string{}, feed.BoardIDs...)
+	f.Changes = []Change{}
+	for _, c := range feed.Changes {
+		change := Change{
+			Target:  string(c.Target),
+			ID:      c.ID,
+			Deleted: c.Deleted,
+		}
+		if !c.Deleted {
+			change.Data = dataOf(c.Target, c.Board, c.List, c.Item)
+		}
+		f.Changes = append(f.Changes, change)
+	}
+}
+
+// Operation includes request data for an operation made offline.
+// Data is a Board, List or Item for Target, and Version is a version expected like If-Match header.
+type Operation struct {
+	Action  string          `json:"action"`
+	Target  string          `json:"target"`
+	Version int             `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func (o *Operation) convertTo() (usecase.Operation, error) {
+	op := usecase.Operation{
+		Action: model.Action(o.Action),
+		Target: model.Target(o.Target),
+	}
+	if len(o.Data) == 0 {
+		o.Data = json.RawMessage("{}")
+	}
+
+	var err error
+	switch op.Target {
+	case model.BOARD:
+		b := Board{}
+		err = json.Unmarshal(o.Data, &b)
+		op.Board = b.convertTo()
+		op.Board.Version = o.Version
+	case model.LIST:
+		l := List{}
+		err = json.Unmarshal(o.Data, &l)
+		op.List = l.convertTo()
+		op.List.Version = o.Version
+	case model.ITEM:
+		i := Item{}
+		err = json.Unmarshal(o.Data, &i)
+		op.Item = i.convertTo()
+		op.Item.Version = o.Version
+	}
+	return op, err
+}
+
+// OperationResult includes response data for a result of an operation.
+// Status is a HTTP status of the operation. Data is current data if Status is 412.
+type OperationResult struct {
+	Status  int         `json:"status"`
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data"`
+}
+
+// SyncHandler includes a interactor for Sync usecase.
+type SyncHandler struct {
+	intractor usecase.SyncUsecase
+}
+
+// NewSyncHandler returns a new SyncHandler.
+func NewSyncHandler(i usecase.SyncUsecase) *SyncHandler {
+	return &SyncHandler{
+		intractor: i,
+	}
+}
+
+// Changes is http handler to get changes since a cursor process.
+func (h *SyncHandler) Changes(c echo.Context) error {
+	user := model.User{ID: getUserIDFromToken(c)}
+
+	feed, err := h.intractor.Changes(c.Request().Context(), user, c.QueryParam("since"))
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resFeed := Feed{}
+	resFeed.convertFrom(feed)
+
+	return c.JSON(http.StatusOK, resFeed)
+}
+
+// Apply is http handler to apply operations made offline process.
+// Results of operations are responded in order even if some of them fail.
+func (h *SyncHandler) Apply(c echo.Context) error {
+	req := struct {
+		Operations []Operation `json:"operations"`
+	}{}
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+
+	ops := []usecase.Operation{}
+	for _, o := range req.Operations {
+		op, err := o.convertTo()
+		if err != nil {
+			return echo.ErrBadRequest
+		}
+		ops = append(ops, op)
+	}
+
+	user := model.User{ID: getUserIDFromToken(c)}
+	results := h.intractor.Apply(c.Request().Context(), user, ops)
+
+	resResults := []OperationResult{}
+	for j, r := range results {
+		res := OperationResult{
+			Status: http.StatusOK,
+			Data:   dataOf(ops[j].Target, r.Board, r.List, r.Item),
+		}
+		switch ops[j].Action {
+		case model.CREATE:
+			res.Status = http.StatusCreated
+		case model.DELETE:
+			res.Status = http.StatusNoContent
+			res.Data = nil
+		}
+
+		if r.Err != nil {
+			httpErr := httpErrorOf(r.Err)
+			res.Status = httpErr.Code
+			res.Message, _ = httpErr.Message.(string)
+			res.Data = nil
+
+			var stale model.PreconditionFailedError
+			if errors.As(r.Err, &stale) {
+				switch v := stale.Current.(type) {
+				case model.Board:
+					res.Data = dataOf(model.BOARD, v, model.List{}, model.Item{})
+				case model.List:
+					res.Data = dataOf(model.LIST, model.Board{}, v, model.Item{})
+				case model.Item:
+					res.Data = dataOf(model.ITEM, model.Board{}, model.List{}, v)
+				}
+			}
+		}
+		resResults = append(resResults, res)
+	}
+
+	return c.JSON(http.StatusOK, map[string][]OperationResult{
+		"results": resResults},
+	)
+}
+
+// dataOf returns response data of a Board, List or Item for target.
+func dataOf(target model.Target, board model.Board, list model.List, item model.Item) interface{} {
+	switch target {
+	case model.BOARD:
+		b := Board{}
+		b.convertFrom(board)
+		return b
+	case model.LIST:
+		l := List{}
+		l.convertFrom(list)
+		return l
+	case model.ITEM:
+		i := Item{}
+		i.convertFrom(item)
+		return i
+	}
+	return nil
+}
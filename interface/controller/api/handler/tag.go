@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// TagHandler includes a interactor for Tag usecase.
+type TagHandler struct {
+	intractor usecase.TagUsecase
+}
+
+// NewTagHandler returns a new TagHandler.
+func NewTagHandler(i usecase.TagUsecase) *TagHandler {
+	return &TagHandler{
+		intractor: i,
+	}
+}
+
+// Create is http handler to create a tag process.
+// A tag is created in a board if 'board_id' is given, otherwise it is created for a user.
+func (h *TagHandler) Create(c echo.Context) error {
+	reqTag := new(Tag)
+	if err := c.Bind(reqTag); err != nil {
+		return err
+	}
+
+	tag := reqTag.convertTo()
+	tag.UserID = getUserIDFromToken(c)
+
+	t, err := h.intractor.Create(c.Request().Context(), tag)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resTag := Tag{}
+	resTag.convertFrom(t)
+
+	return c.JSON(http.StatusCreated, resTag)
+}
+
+// Update is http handler to update a tag process.
+func (h *TagHandler) Update(c echo.Context) error {
+	reqTag := new(Tag)
+	if err := c.Bind(reqTag); err != nil {
+		return err
+	}
+	reqTag.ID = c.Param("id")
+
+	tag := reqTag.convertTo()
+	tag.UserID = getUserIDFromToken(c)
+
+	t, err := h.intractor.Update(c.Request().Context(), tag)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resTag := Tag{}
+	resTag.convertFrom(t)
+
+	return c.JSON(http.StatusOK, resTag)
+}
+
+// Delete is http handler to delete a tag process.
+func (h *TagHandler) Delete(c echo.Context) error {
+	reqTag := new(Tag)
+	if err := c.Bind(reqTag); err != nil {
+		return err
+	}
+	reqTag.ID = c.Param("id")
+
+	tag := reqTag.convertTo()
+	tag.UserID = getUserIDFromToken(c)
+
+	if err := h.intractor.Delete(c.Request().Context(), tag); err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// accessTokenKey is a key of AccessToken authenticating a request in echo.Context.
+const accessTokenKey = "access_token"
+
+// AccessToken includes request data for AccessToken.
+// Token is only responded when AccessToken is created. ExpiresAt is null if it never expires.
+type AccessToken struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Token     string     `json:"token,omitempty"`
+	ReadOnly  bool       `json:"read_only"`
+	BoardID   string     `json:"board_id"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (a *AccessToken) convertTo() model.AccessToken {
+	token := model.AccessToken{
+		Name:     a.Name,
+		ReadOnly: a.ReadOnly,
+		BoardID:  a.BoardID,
+	}
+	if a.ExpiresAt != nil {
+		token.ExpiresAt = *a.ExpiresAt
+	}
+
+	return token
+}
+
+func (a *AccessToken) convertFrom(token model.AccessToken) {
+	a.ID = token.ID
+	a.Name = token.Name
+	a.Token = token.Token
+	a.ReadOnly = token.ReadOnly
+	a.BoardID = token.BoardID
+	a.ExpiresAt = nil
+	if !token.ExpiresAt.IsZero() {
+		t := token.ExpiresAt
+		a.ExpiresAt = &t
+	}
+	a.CreatedAt = token.CreatedAt
+}
+
+// AccessTokenHandler includes a interactor for AccessToken usecase.
+type AccessTokenHandler struct {
+	intractor usecase.AccessTokenUsecase
+}
+
+// NewAccessTokenHandler returns a new AccessTokenHandler.
+func NewAccessTokenHandler(i usecase.AccessTokenUsecase) *AccessTokenHandler {
+	return &AccessTokenHandler{
+		intractor: i,
+	}
+}
+
+// Authenticate is middleware to authenticate a request by a personal access token in 'Authorization: Bearer' header.
+// Requests without the header are passed through to be authenticated by JWT in cookie.
+func (h *AccessTokenHandler) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		auth := c.Request().Header.Get(echo.HeaderAuthorization)
+		if !strings.HasPrefix(auth, "Bearer ") {
+			return next(c)
+		}
+
+		ctx := c.Request().Context()
+		token, err := h.intractor.Authenticate(ctx, strings.TrimPrefix(auth, "Bearer "))
+		if err != nil {
+			if errors.Is(err, model.NotFoundError{}) {
+				return echo.ErrUnauthorized
+			}
+			return convertToHTTPError(c, err)
+		}
+
+		c.Set(accessTokenKey, token)
+		c.SetRequest(c.Request().WithContext(usecase.WithAccessToken(ctx, token)))
+		return next(c)
+	}
+}
+
+// AccessTokenOf returns AccessToken authenticating a request.
+func AccessTokenOf(c echo.Context) (model.AccessToken, bool) {
+	token, ok := c.Get(accessTokenKey).(model.AccessToken)
+	return token, ok
+}
+
+// HasAccessToken checks that a request is authenticated by a personal access token.
+func HasAccessToken(c echo.Context) bool {
+	_, ok := AccessTokenOf(c)
+	return ok
+}
+
+// GetTokens is http handler to get personal access tokens of a user process.
+func (h *AccessTokenHandler) GetTokens(c echo.Context) error {
+	if HasAccessToken(c) {
+		return echo.ErrForbidden
+	}
+
+	user := model.User{ID: getUserIDFromToken(c)}
+
+	tokens, err := h.intractor.GetTokens(c.Request().Context(), user)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resTokens := []AccessToken{}
+	for _, token := range tokens {
+		a := AccessToken{}
+		a.convertFrom(token)
+		resTokens = append(resTokens, a)
+	}
+
+	return c.JSON(http.StatusOK, map[string][]AccessToken{
+		"tokens": resTokens},
+	)
+}
+
+// Create is http handler to create a personal access token process.
+// Tokens can only be managed by a signed in user, so that a leaked token can not make others.
+func (h *AccessTokenHandler) Create(c echo.Context) error {
+	if HasAccessToken(c) {
+		return echo.ErrForbidden
+	}
+
+	reqToken := new(AccessToken)
+	if err := c.Bind(reqToken); err != nil {
+		return err
+	}
+
+	token := reqToken.convertTo()
+	token.UserID = getUserIDFromToken(c)
+
+	t, err := h.intractor.Create(c.Request().Context(), token)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resToken := AccessToken{}
+	resToken.convertFrom(t)
+
+	return c.JSON(http.StatusCreated, resToken)
+}
+
+// Revoke is http handler to revoke a personal access token process.
+func (h *AccessTokenHandler) Revoke(c echo.Context) error {
+	if HasAccessToken(c) {
+		return echo.ErrForbidden
+	}
+
+	token := model.AccessToken{
+		ID:     c.Param("id"),
+		UserID: getUserIDFromToken(c),
+	}
+
+	if err := h.intractor.Revoke(c.Request().Context(), token); err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
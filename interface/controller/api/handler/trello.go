@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/x-color/vue-trello/model"
+)
+
+// trelloColors maps colors of Trello onto Color.
+// Colors of Trello having '_light' or '_dark' suffix are mapped as colors without it.
+var trelloColors = map[string]model.Color{
+	"red":    model.RED,
+	"orange": model.YELLOW,
+	"yellow": model.YELLOW,
+	"green":  model.GREEN,
+	"lime":   model.GREEN,
+	"blue":   model.BLUE,
+	"sky":    model.BLUE,
+}
+
+// trelloBoard includes request data for a Board exported from Trello as JSON.
+// Only fields used to import a Board are defined.
+type trelloBoard struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Desc  string `json:"desc"`
+	Prefs struct {
+		Background string `json:"background"`
+	} `json:"prefs"`
+	Labels     []trelloLabel     `json:"labels"`
+	Lists      []trelloList      `json:"lists"`
+	Cards      []trelloCard      `json:"cards"`
+	Checklists []trelloChecklist `json:"checklists"`
+}
+
+type trelloLabel struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type trelloList struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Closed bool    `json:"closed"`
+	Pos    float64 `json:"pos"`
+}
+
+type trelloCard struct {
+	ID          string     `json:"id"`
+	IDList      string     `json:"idList"`
+	Name        string     `json:"name"`
+	Desc        string     `json:"desc"`
+	IDLabels    []string   `json:"idLabels"`
+	Closed      bool       `json:"closed"`
+	Pos         float64    `json:"pos"`
+	Start       *time.Time `json:"start"`
+	Due         *time.Time `json:"due"`
+	DueComplete bool       `json:"dueComplete"`
+}
+
+type trelloChecklist struct {
+	ID         string            `json:"id"`
+	IDCard     string            `json:"idCard"`
+	Name       string            `json:"name"`
+	Pos        float64           `json:"pos"`
+	CheckItems []trelloCheckItem `json:"checkItems"`
+}
+
+type trelloCheckItem struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	State string  `json:"state"`
+	Pos   float64 `json:"pos"`
+}
+
+// convertTo returns a Board including Lists and Items in order of Trello, and data skipped in conversion.
+// Labels are converted to Tags without ID, and labels having other colors are blue. Checklists are
+// flattened into a text of an Item.
+// Archived lists and cards are skipped.
+func (t *trelloBoard) convertTo() (model.Board, model.Skips) {
+	skips := model.Skips{}
+
+	color, ok := trelloColor(t.Prefs.Background)
+	if !ok {
+		color = model.BLUE
+		skips = append(skips, model.Skip{
+			Target: model.BOARD,
+			ID:     t.ID,
+			Name:   t.Name,
+			Reason: "background '" + t.Prefs.Background + "' is replaced with " + string(color),
+		})
+	}
+	board := model.Board{
+		ID:    t.ID,
+		Title: t.Name,
+		Text:  t.Desc,
+		Color: color,
+		Lists: model.Lists{},
+	}
+
+	tags := map[string]model.Tag{}
+	for _, l := range t.Labels {
+		c, ok := trelloColor(l.Color)
+		if !ok {
+			c = model.BLUE
+			if l.Color != "" {
+				skips = append(skips, model.Skip{
+					Target: model.TAG,
+					ID:     l.ID,
+					Name:   l.Name,
+					Reason: "color '" + l.Color + "' is replaced with " + string(c),
+				})
+			}
+		}
+		// Labels of Trello may have no name, but Tags must have it.
+		name := l.Name
+		if name == "" {
+			name = string(c)
+		}
+		tags[l.ID] = model.Tag{Name: name, Color: c}
+	}
+
+	checklists := map[string][]trelloChecklist{}
+	for _, c := range t.Checklists {
+		checklists[c.IDCard] = append(checklists[c.IDCard], c)
+	}
+
+	sort.SliceStable(t.Lists, func(a, b int) bool {
+		return t.Lists[a].Pos < t.Lists[b].Pos
+	})
+	sort.SliceStable(t.Cards, func(a, b int) bool {
+		return t.Cards[a].Pos < t.Cards[b].Pos
+	})
+
+	index := map[string]int{}
+	for _, l := range t.Lists {
+		if l.Closed {
+			skips = append(skips, model.Skip{Target: model.LIST, ID: l.ID, Name: l.Name, Reason: "list is archived"})
+			continue
+		}
+		index[l.ID] = len(board.Lists)
+		board.Lists = append(board.Lists, model.List{
+			ID:    l.ID,
+			Title: l.Name,
+			Items: model.Items{},
+		})
+	}
+
+	for _, c := range t.Cards {
+		j, ok := index[c.IDList]
+		switch {
+		case c.Closed:
+			skips = append(skips, model.Skip{Target: model.ITEM, ID: c.ID, Name: c.Name, Reason: "card is archived"})
+			continue
+		case !ok:
+			skips = append(skips, model.Skip{Target: model.ITEM, ID: c.ID, Name: c.Name, Reason: "list of card is skipped"})
+			continue
+		}
+
+		item := model.Item{
+			ID:        c.ID,
+			Title:     c.Name,
+			Text:      flattenChecklists(c.Desc, checklists[c.ID]),
+			Tags:      model.Tags{},
+			Completed: c.DueComplete,
+		}
+		if c.Start != nil {
+			item.StartDate = *c.Start
+		}
+		if c.Due != nil {
+			item.DueDate = *c.Due
+		}
+		for _, id := range c.IDLabels {
+			if tag, ok := tags[id]; ok {
+				item.Tags = append(item.Tags, tag)
+			}
+		}
+		board.Lists[j].Items = append(board.Lists[j].Items, item)
+	}
+
+	return board, skips
+}
+
+func trelloColor(color string) (model.Color, bool) {
+	color = strings.TrimSuffix(strings.TrimSuffix(color, "_light"), "_dark")
+	c, ok := trelloColors[color]
+	return c, ok
+}
+
+// flattenChecklists appends checklists to text as Markdown task lists.
+func flattenChecklists(text string, checklists []trelloChecklist) string {
+	sort.SliceStable(checklists, func(a, b int) bool {
+		return checklists[a].Pos < checklists[b].Pos
+	})
+
+	var b strings.Builder
+	b.WriteString(text)
+	for _, c := range checklists {
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString("## " + c.Name + "\n")
+
+		sort.SliceStable(c.CheckItems, func(x, y int) bool {
+			return c.CheckItems[x].Pos < c.CheckItems[y].Pos
+		})
+		for _, ci := range c.CheckItems {
+			if ci.State == "complete" {
+				b.WriteString("\n- [x] " + ci.Name)
+			} else {
+				b.WriteString("\n- [ ] " + ci.Name)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Webhook includes request data for Webhook.
+// Secret is only responded when Webhook is created. It is generated if it is not given.
+type Webhook struct {
+	ID        string    `json:"id"`
+	BoardID   string    `json:"board_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (w *Webhook) convertTo() model.Webhook {
+	webhook := model.Webhook{
+		ID:      w.ID,
+		BoardID: w.BoardID,
+		URL:     w.URL,
+		Secret:  w.Secret,
+		Events:  w.Events,
+	}
+
+	return webhook
+}
+
+func (w *Webhook) convertFrom(webhook model.Webhook) {
+	w.ID = webhook.ID
+	w.BoardID = webhook.BoardID
+	w.URL = webhook.URL
+	w.Secret = webhook.Secret
+	w.Events = append([]string{}, webhook.Events...)
+	w.CreatedAt = webhook.CreatedAt
+}
+
+// Delivery includes response data for Delivery.
+// NextAttemptAt is null unless Delivery is pending.
+type Delivery struct {
+	ID             string     `json:"id"`
+	ActivityID     string     `json:"activity_id"`
+	Event          string     `json:"event"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"response_status"`
+	Error          string     `json:"error"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (d *Delivery) convertFrom(delivery model.Delivery) {
+	d.ID = delivery.ID
+	d.ActivityID = delivery.ActivityID
+	d.Event = delivery.Event
+	d.Status = string(delivery.Status)
+	d.Attempts = delivery.Attempts
+	d.ResponseStatus = delivery.ResponseStatus
+	d.Error = delivery.Error
+	d.CreatedAt = delivery.CreatedAt
+	d.UpdatedAt = delivery.UpdatedAt
+
+	d.NextAttemptAt = nil
+	if delivery.Status == model.PENDING {
+		t := delivery.NextAttemptAt
+		d.NextAttemptAt = &t
+	}
+}
+
+// WebhookHandler includes a interactor for Webhook usecase.
+type WebhookHandler struct {
+	intractor usecase.WebhookUsecase
+}
+
+// NewWebhookHandler returns a new WebhookHandler.
+func NewWebhookHandler(i usecase.WebhookUsecase) *WebhookHandler {
+	return &WebhookHandler{
+		intractor: i,
+	}
+}
+
+// GetWebhooks is http handler to get webhooks in a board process.
+func (h *WebhookHandler) GetWebhooks(c echo.Context) error {
+	board := model.Board{
+		ID:     c.Param("id"),
+		UserID: getUserIDFromToken(c),
+	}
+
+	webhooks, err := h.intractor.GetWebhooks(c.Request().Context(), board)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resWebhooks := []Webhook{}
+	for _, webhook := range webhooks {
+		w := Webhook{}
+		w.convertFrom(webhook)
+		resWebhooks = append(resWebhooks, w)
+	}
+
+	return c.JSON(http.StatusOK, map[string][]Webhook{
+		"webhooks": resWebhooks},
+	)
+}
+
+// Create is http handler to register a webhook process.
+func (h *WebhookHandler) Create(c echo.Context) error {
+	reqWebhook := new(Webhook)
+	if err := c.Bind(reqWebhook); err != nil {
+		return err
+	}
+	reqWebhook.BoardID = c.Param("id")
+
+	webhook := reqWebhook.convertTo()
+	webhook.UserID = getUserIDFromToken(c)
+
+	w, err := h.intractor.Create(c.Request().Context(), webhook)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resWebhook := Webhook{}
+	resWebhook.convertFrom(w)
+
+	return c.JSON(http.StatusCreated, resWebhook)
+}
+
+// Delete is http handler to delete a webhook process.
+func (h *WebhookHandler) Delete(c echo.Context) error {
+	webhook := model.Webhook{
+		ID:      c.Param("webhook_id"),
+		BoardID: c.Param("id"),
+		UserID:  getUserIDFromToken(c),
+	}
+
+	if err := h.intractor.Delete(c.Request().Context(), webhook); err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetDeliveries is http handler to get deliveries of a webhook process.
+// 'offset' and 'limit' query parameters select a page. 'limit' is 20 by default and 100 at most.
+// 'next' in response is offset of next page. It is null if there are no more deliveries.
+func (h *WebhookHandler) GetDeliveries(c echo.Context) error {
+	offset := 0
+	if o := c.QueryParam("offset"); o != "" {
+		v, err := strconv.Atoi(o)
+		if err != nil || v < 0 {
+			return echo.ErrBadRequest
+		}
+		offset = v
+	}
+	limit := 20
+	if l := c.QueryParam("limit"); l != "" {
+		v, err := strconv.Atoi(l)
+		if err != nil || v <= 0 || v > 100 {
+			return echo.ErrBadRequest
+		}
+		limit = v
+	}
+
+	webhook := model.Webhook{
+		ID:      c.Param("webhook_id"),
+		BoardID: c.Param("id"),
+		UserID:  getUserIDFromToken(c),
+	}
+
+	deliveries, hasNext, err := h.intractor.GetDeliveries(c.Request().Context(), webhook, offset, limit)
+	if err != nil {
+		return convertToHTTPError(c, err)
+	}
+
+	resDeliveries := []Delivery{}
+	for _, delivery := range deliveries {
+		d := Delivery{}
+		d.convertFrom(delivery)
+		resDeliveries = append(resDeliveries, d)
+	}
+
+	var next *int
+	if hasNext {
+		n := offset + limit
+		next = &n
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"deliveries": resDeliveries,
+		"next":       next,
+	})
+}
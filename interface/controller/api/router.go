@@ -1,10 +1,12 @@
 package api
 
 import (
+	"context"
+	"crypto/subtle"
 	"errors"
 	"net/http"
+	"time"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/labstack/echo"
 	"github.com/labstack/echo/middleware"
 	"github.com/x-color/vue-trello/interface/controller/api/handler"
@@ -13,11 +15,23 @@ import (
 
 // InteraBox includes all usecases interactors.
 type InteraBox struct {
-	item     usecase.ItemUsecase
-	list     usecase.ListUsecase
-	board    usecase.BoardUsecase
-	user     usecase.UserUsecase
-	resource usecase.ResourceUsecase
+	item      usecase.ItemUsecase
+	list      usecase.ListUsecase
+	board     usecase.BoardUsecase
+	user      usecase.UserUsecase
+	resource  usecase.ResourceUsecase
+	member    usecase.MemberUsecase
+	checklist usecase.ChecklistUsecase
+	comment   usecase.CommentUsecase
+	activity  usecase.ActivityUsecase
+	importer  usecase.ImportUsecase
+	exporter  usecase.ExportUsecase
+	tag       usecase.TagUsecase
+	event     usecase.EventUsecase
+	sync      usecase.SyncUsecase
+	webhook   usecase.WebhookUsecase
+	token     usecase.AccessTokenUsecase
+	session   usecase.SessionUsecase
 }
 
 // NewInteraBox retruns new InteraBox.
@@ -27,27 +41,63 @@ func NewInteraBox(
 	boardIntera usecase.BoardUsecase,
 	userIntera usecase.UserUsecase,
 	resourceIntera usecase.ResourceUsecase,
+	memberIntera usecase.MemberUsecase,
+	checklistIntera usecase.ChecklistUsecase,
+	commentIntera usecase.CommentUsecase,
+	activityIntera usecase.ActivityUsecase,
+	importIntera usecase.ImportUsecase,
+	exportIntera usecase.ExportUsecase,
+	tagIntera usecase.TagUsecase,
+	eventIntera usecase.EventUsecase,
+	syncIntera usecase.SyncUsecase,
+	webhookIntera usecase.WebhookUsecase,
+	tokenIntera usecase.AccessTokenUsecase,
+	sessionIntera usecase.SessionUsecase,
 ) (InteraBox, error) {
-	if itemIntera == nil || listIntera == nil || boardIntera == nil || userIntera == nil || resourceIntera == nil {
+	if itemIntera == nil || listIntera == nil || boardIntera == nil || userIntera == nil || resourceIntera == nil || memberIntera == nil || checklistIntera == nil || commentIntera == nil || activityIntera == nil || importIntera == nil || exportIntera == nil || tagIntera == nil || eventIntera == nil || syncIntera == nil || webhookIntera == nil || tokenIntera == nil || sessionIntera == nil {
 		return InteraBox{}, errors.New("interactors are nil at least one")
 	}
 	b := InteraBox{
-		item:     itemIntera,
-		list:     listIntera,
-		board:    boardIntera,
-		user:     userIntera,
-		resource: resourceIntera,
+		item:      itemIntera,
+		list:      listIntera,
+		board:     boardIntera,
+		user:      userIntera,
+		resource:  resourceIntera,
+		member:    memberIntera,
+		checklist: checklistIntera,
+		comment:   commentIntera,
+		activity:  activityIntera,
+		importer:  importIntera,
+		exporter:  exportIntera,
+		tag:       tagIntera,
+		event:     eventIntera,
+		sync:      syncIntera,
+		webhook:   webhookIntera,
+		token:     tokenIntera,
+		session:   sessionIntera,
 	}
 	return b, nil
 }
 
-// NewRouter defines routing and returns echo instance.
-func NewRouter(b InteraBox) *echo.Echo {
-	userHandler := handler.NewUserHandler(b.user)
+// NewRouter defines routing and returns echo instance. JWT is signed and verified by keys.
+// A context of each request is canceled after timeout unless timeout is 0.
+func NewRouter(b InteraBox, keys handler.Keys, timeout time.Duration) *echo.Echo {
+	userHandler := handler.NewUserHandler(b.user, b.session, keys)
 	itemHandler := handler.NewItemHandler(b.item)
 	listHandler := handler.NewListHandler(b.list)
 	boardHandler := handler.NewBoardHandler(b.board)
 	resourceHandler := handler.NewResourceHandler(b.resource)
+	memberHandler := handler.NewMemberHandler(b.member)
+	checklistHandler := handler.NewChecklistHandler(b.checklist)
+	commentHandler := handler.NewCommentHandler(b.comment)
+	activityHandler := handler.NewActivityHandler(b.activity)
+	importHandler := handler.NewImportHandler(b.importer)
+	exportHandler := handler.NewExportHandler(b.exporter)
+	tagHandler := handler.NewTagHandler(b.tag)
+	eventHandler := handler.NewEventHandler(b.event)
+	syncHandler := handler.NewSyncHandler(b.sync)
+	webhookHandler := handler.NewWebhookHandler(b.webhook)
+	tokenHandler := handler.NewAccessTokenHandler(b.token)
 
 	echo.NotFoundHandler = func(c echo.Context) error {
 		return c.Redirect(http.StatusMovedPermanently, "/?redirect="+c.Request().URL.Path)
@@ -57,6 +107,7 @@ func NewRouter(b InteraBox) *echo.Echo {
 
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
+	e.Use(setDeadline(timeout, "/api/boards/:id/stream"))
 
 	e.Static("/", "web/dist")
 	e.File("/", "web/dist/index.html")
@@ -66,38 +117,105 @@ func NewRouter(b InteraBox) *echo.Echo {
 	auth.POST("/signin", userHandler.SignIn)
 	auth.GET("/signout", userHandler.SignOut)
 
+	jwtAuth := userHandler.Authenticate
+
+	// EventSource can not set headers, so the stream is only authenticated by the cookie.
+	// It is safe from CSRF since it changes nothing.
+	e.GET("/api/boards/:id/stream", eventHandler.Stream, jwtAuth)
+
+	// Requests by personal access tokens are not authenticated by cookie, so they are safe from CSRF.
+	// Tokens restricted to a board can not use paths about all boards of a user.
 	api := e.Group("/api")
-	api.Use(middleware.JWTWithConfig(middleware.JWTConfig{
-		Claims:      &jwt.StandardClaims{},
-		SigningKey:  handler.SECRET,
-		TokenLookup: "cookie:token",
-	}))
+	api.Use(tokenHandler.Authenticate)
+	api.Use(jwtAuth)
 	api.Use(checkContentType("application/json; charset=UTF-8"))
-	api.Use(checkCSRFToken())
+	api.Use(checkCSRFToken(handler.HasAccessToken))
+	api.Use(restrictAccessToken(
+		"/api/boards",
+		"/api/boards/import",
+		"/api/resources",
+		"/api/items/due",
+		"/api/tags",
+		"/api/tags/:id",
+		"/api/sync",
+	))
 
 	api.GET("/boards", boardHandler.GetBoards)
 	api.GET("/boards/:id", boardHandler.Get)
 	api.GET("/resources", resourceHandler.Get)
+	api.GET("/items/due", itemHandler.GetDue)
+	api.GET("/items/:id/checklists", checklistHandler.GetChecklists)
+	api.GET("/boards/:id/members", memberHandler.GetMembers)
+	api.GET("/items/:id/comments", commentHandler.GetComments)
+	api.GET("/boards/:id/activity", activityHandler.GetActivities)
+	api.GET("/boards/:id/export", exportHandler.Export)
+	api.GET("/sync", syncHandler.Changes)
+	api.GET("/boards/:id/webhooks", webhookHandler.GetWebhooks)
+	api.GET("/boards/:id/webhooks/:webhook_id/deliveries", webhookHandler.GetDeliveries)
+	api.GET("/tokens", tokenHandler.GetTokens)
+	api.GET("/sessions", userHandler.GetSessions)
 
 	api.DELETE("/items/:id", itemHandler.Delete)
 	api.DELETE("/lists/:id", listHandler.Delete)
 	api.DELETE("/boards/:id", boardHandler.Delete)
+	api.DELETE("/boards/:id/members/:user_id", memberHandler.Remove)
+	api.DELETE("/items/:id/checklists/:checklist_id", checklistHandler.Delete)
+	api.DELETE("/items/:id/checklists/:checklist_id/checkitems/:checkitem_id", checklistHandler.DeleteCheckItem)
+	api.DELETE("/items/:id/comments/:comment_id", commentHandler.Delete)
+	api.DELETE("/tags/:id", tagHandler.Delete)
+	api.DELETE("/boards/:id/webhooks/:webhook_id", webhookHandler.Delete)
+	api.DELETE("/tokens/:id", tokenHandler.Revoke)
+	api.DELETE("/sessions", userHandler.SignOutAll)
+	api.DELETE("/user", userHandler.DeleteAccount)
 
 	api.POST("/items", itemHandler.Create)
 	api.POST("/lists", listHandler.Create)
 	api.POST("/boards", boardHandler.Create)
+	api.POST("/boards/:id/members", memberHandler.Invite)
+	api.POST("/items/:id/checklists", checklistHandler.Create)
+	api.POST("/items/:id/checklists/:checklist_id/checkitems", checklistHandler.CreateCheckItem)
+	api.POST("/items/:id/comments", commentHandler.Create)
+	api.POST("/boards/import", importHandler.Import)
+	api.POST("/tags", tagHandler.Create)
+	api.POST("/sync", syncHandler.Apply)
+	api.POST("/boards/:id/webhooks", webhookHandler.Create)
+	api.POST("/tokens", tokenHandler.Create)
 
 	api.PATCH("/items/:id", itemHandler.Update)
 	api.PATCH("/lists/:id", listHandler.Update)
 	api.PATCH("/boards/:id", boardHandler.Update)
+	api.PATCH("/boards/:id/members/:user_id", memberHandler.Update)
+	api.PATCH("/items/:id/checklists/:checklist_id", checklistHandler.Update)
+	api.PATCH("/items/:id/checklists/:checklist_id/checkitems/:checkitem_id", checklistHandler.UpdateCheckItem)
+	api.PATCH("/items/:id/comments/:comment_id", commentHandler.Update)
+	api.PATCH("/tags/:id", tagHandler.Update)
+	api.PATCH("/user", userHandler.ChangeName)
+	api.PATCH("/user/password", userHandler.ChangePassword)
 
 	api.PATCH("/items/:id/move", itemHandler.Move)
 	api.PATCH("/lists/:id/move", listHandler.Move)
 	api.PATCH("/boards/:id/move", boardHandler.Move)
+	api.PATCH("/items/:id/checklists/:checklist_id/move", checklistHandler.Move)
+	api.PATCH("/items/:id/checklists/:checklist_id/checkitems/:checkitem_id/move", checklistHandler.MoveCheckItem)
 
 	return e
 }
 
+// setDeadline sets timeout to a context of each request except for long-lived requests to paths.
+func setDeadline(timeout time.Duration, paths ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if timeout == 0 || contains(paths, c.Path()) {
+				return next(c)
+			}
+			ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+			defer cancel()
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
 func checkContentType(typ string) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -110,14 +228,49 @@ func checkContentType(typ string) echo.MiddlewareFunc {
 	}
 }
 
-func checkCSRFToken() echo.MiddlewareFunc {
+// checkCSRFToken checks that CSRF token in the header is the same as one in the cookie (double-submit cookie).
+// The tokens are compared in constant time not to leak the token by timing.
+func checkCSRFToken(skipper middleware.Skipper) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			csrf := c.Request().Header.Get("X-XSRF-TOKEN")
-			if csrf == "" {
+			if skipper(c) {
+				return next(c)
+			}
+			csrf := c.Request().Header.Get(handler.CSRFHeaderName)
+			cookie, err := c.Cookie(handler.CSRFCookieName)
+			if err != nil || csrf == "" || subtle.ConstantTimeCompare([]byte(csrf), []byte(cookie.Value)) != 1 {
 				return echo.ErrForbidden
 			}
 			return next(c)
 		}
 	}
 }
+
+// restrictAccessToken rejects requests by personal access tokens out of their scopes.
+// Read-only tokens can only get data, and tokens restricted to a board can not use userPaths.
+func restrictAccessToken(userPaths ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, ok := handler.AccessTokenOf(c)
+			if !ok {
+				return next(c)
+			}
+			if token.ReadOnly && c.Request().Method != http.MethodGet {
+				return echo.ErrForbidden
+			}
+			if token.BoardID != "" && contains(userPaths, c.Path()) {
+				return echo.ErrForbidden
+			}
+			return next(c)
+		}
+	}
+}
+
+func contains(paths []string, path string) bool {
+	for _, p := range paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
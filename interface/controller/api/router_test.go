@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo"
+	"github.com/x-color/vue-trello/interface/controller/api/handler"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// tokenUsecase authenticates only "valid" as a personal access token.
+type tokenUsecase struct {
+	usecase.AccessTokenUsecase
+}
+
+func (tokenUsecase) Authenticate(ctx context.Context, token string) (model.AccessToken, error) {
+	if token != "valid" {
+		return model.AccessToken{}, model.NotFoundError{UserID: "(No-ID)", ID: "(No-ID)", Act: "authenticate token"}
+	}
+	return model.AccessToken{ID: "t1", UserID: "u1"}, nil
+}
+
+func TestCheckCSRFToken(t *testing.T) {
+	e := echo.New()
+	api := e.Group("/api")
+	api.Use(handler.NewAccessTokenHandler(tokenUsecase{}).Authenticate)
+	api.Use(checkCSRFToken(handler.HasAccessToken))
+	api.POST("/boards", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		cookie string
+		want   int
+	}{
+		{"valid", map[string]string{handler.CSRFHeaderName: "token"}, "token", http.StatusNoContent},
+		{"mismatched", map[string]string{handler.CSRFHeaderName: "forged"}, "token", http.StatusForbidden},
+		{"longer", map[string]string{handler.CSRFHeaderName: "token0"}, "token", http.StatusForbidden},
+		{"missing header", map[string]string{}, "token", http.StatusForbidden},
+		{"empty header and cookie", map[string]string{handler.CSRFHeaderName: ""}, "", http.StatusForbidden},
+		{"missing cookie", map[string]string{handler.CSRFHeaderName: "token"}, "", http.StatusForbidden},
+		// Requests by personal access tokens are not authenticated by cookies
+		{"bearer", map[string]string{echo.HeaderAuthorization: "Bearer valid"}, "", http.StatusNoContent},
+		{"invalid bearer", map[string]string{echo.HeaderAuthorization: "Bearer invalid"}, "", http.StatusUnauthorized},
+		{"other scheme", map[string]string{echo.HeaderAuthorization: "Basic dXNlcjpwYXNz"}, "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/api/boards", nil)
+		for k, v := range tt.header {
+			req.Header.Set(k, v)
+		}
+		if tt.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: handler.CSRFCookieName, Value: tt.cookie})
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
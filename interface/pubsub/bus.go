@@ -0,0 +1,85 @@
+package pubsub
+
+import (
+	"sync"
+
+	"github.com/x-color/vue-trello/model"
+)
+
+// bufferSize is a number of Activities kept for a subscriber until it receives them.
+const bufferSize = 64
+
+// Bus is in-process pub/sub of Activities in Boards. Publish never blocks. A subscriber whose buffer
+// is full is dropped by closing its channel, and it is expected to resume from the last Activity.
+// Activities committed by concurrent transactions can be published in another order than they are
+// recorded, so subscribers needing the order read them from a repository.
+type Bus struct {
+	mu       sync.Mutex
+	subs     map[string]map[chan model.Activity]bool
+	handlers []func(activities ...model.Activity)
+}
+
+// NewBus returns a new Bus.
+func NewBus() *Bus {
+	return &Bus{
+		subs: map[string]map[chan model.Activity]bool{},
+	}
+}
+
+// Publish sends Activities to subscribers of their Boards in order, and then calls handlers with them.
+func (b *Bus) Publish(activities ...model.Activity) {
+	b.mu.Lock()
+	for _, a := range activities {
+		for ch := range b.subs[a.BoardID] {
+			select {
+			case ch <- a:
+			default:
+				b.drop(a.BoardID, ch)
+			}
+		}
+	}
+	handlers := b.handlers
+	b.mu.Unlock()
+
+	for _, h := range handlers {
+		h(activities...)
+	}
+}
+
+// Handle adds a handler called with Activities published to all Boards. It is called synchronously
+// by a publisher, so it can save Activities before the publisher responds.
+func (b *Bus) Handle(handler func(activities ...model.Activity)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// Subscribe returns a channel of Activities published to a Board and a function to stop the subscription.
+func (b *Bus) Subscribe(boardID string) (<-chan model.Activity, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan model.Activity, bufferSize)
+	if b.subs[boardID] == nil {
+		b.subs[boardID] = map[chan model.Activity]bool{}
+	}
+	b.subs[boardID][ch] = true
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.subs[boardID][ch] {
+			b.drop(boardID, ch)
+		}
+	}
+	return ch, cancel
+}
+
+func (b *Bus) drop(boardID string, ch chan model.Activity) {
+	delete(b.subs[boardID], ch)
+	if len(b.subs[boardID]) == 0 {
+		delete(b.subs, boardID)
+	}
+	close(ch)
+}
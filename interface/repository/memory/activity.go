@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// ActivityDBManager is DB manager for Activity in memory.
+type ActivityDBManager struct{}
+
+// Create registers a Activity.
+func (*ActivityDBManager) Create(ctx context.Context, tx usecase.Transaction, activity model.Activity) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("activity", activity.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if _, ok := s.get("activities", activity.ID); ok {
+			return model.ServerError{
+				UserID: activity.UserID,
+				Err:    nil,
+				ID:     activity.ID,
+				Act:    "create activity",
+			}
+		}
+		activity.UserName = ""
+		s.put(t, "activities", activity.ID, activity)
+		return nil
+	})
+}
+
+// FindPage gets Activities in a Board from newest to oldest.
+// If cursor is not empty, it gets Activities older than the Activity had cursor as ID.
+func (*ActivityDBManager) FindPage(ctx context.Context, tx usecase.Transaction, boardID, cursor string, limit int) (model.Activities, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Activities{}, err
+	}
+
+	activities := model.Activities{}
+	found := true
+	read(tx, func(s *store) {
+		var until uint64
+		if cursor != "" {
+			r, ok := s.get("activities", cursor)
+			if !ok || r.(model.Activity).BoardID != boardID {
+				found = false
+				return
+			}
+			until = s.seqOf("activities", cursor)
+		}
+
+		rows := s.rows("activities")
+		for j := len(rows) - 1; j >= 0 && (limit < 0 || len(activities) < limit); j-- {
+			a := rows[j].(model.Activity)
+			if a.BoardID == boardID && (until == 0 || s.seqOf("activities", a.ID) < until) {
+				activities = append(activities, a)
+			}
+		}
+	})
+	if !found {
+		return model.Activities{}, notFound(cursor, "(No-ID)", "find cursor of activities")
+	}
+	return activities, nil
+}
+
+// FindSince gets Activities in a Board recorded after the Activity had cursor as ID from oldest to newest.
+func (*ActivityDBManager) FindSince(ctx context.Context, tx usecase.Transaction, boardID, cursor string, limit int) (model.Activities, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Activities{}, err
+	}
+
+	if err := validatePrimaryKeys("activity", cursor); err != nil {
+		return model.Activities{}, err
+	}
+
+	activities := model.Activities{}
+	found := true
+	read(tx, func(s *store) {
+		r, ok := s.get("activities", cursor)
+		if !ok || r.(model.Activity).BoardID != boardID {
+			found = false
+			return
+		}
+		since := s.seqOf("activities", cursor)
+
+		for _, row := range s.rows("activities") {
+			if limit >= 0 && len(activities) >= limit {
+				break
+			}
+			a := row.(model.Activity)
+			if a.BoardID == boardID && s.seqOf("activities", a.ID) > since {
+				activities = append(activities, a)
+			}
+		}
+	})
+	if !found {
+		return model.Activities{}, notFound(cursor, "(No-ID)", "find cursor of activities")
+	}
+	return activities, nil
+}
+
+// Find gets Activities in order of recording.
+func (*ActivityDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.ActivityFilter) (model.Activities, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Activities{}, err
+	}
+
+	activities := model.Activities{}
+	found := true
+	read(tx, func(s *store) {
+		var since uint64
+		if filter.After != "" {
+			if _, ok := s.get("activities", filter.After); !ok {
+				found = false
+				return
+			}
+			since = s.seqOf("activities", filter.After)
+		}
+
+		for _, r := range s.rows("activities") {
+			a := r.(model.Activity)
+			if in(filter.IDs, a.ID) && in(filter.BoardIDs, a.BoardID) && s.seqOf("activities", a.ID) > since {
+				activities = append(activities, a)
+			}
+		}
+	})
+	if !found {
+		return model.Activities{}, notFound(filter.After, "(No-ID)", "find cursor of activities")
+	}
+
+	if filter.Desc {
+		for i, j := 0, len(activities)-1; i < j; i, j = i+1, j-1 {
+			activities[i], activities[j] = activities[j], activities[i]
+		}
+	}
+	from, to := pageRange(len(activities), filter.Page)
+	return activities[from:to], nil
+}
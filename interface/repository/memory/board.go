@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"context"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// BoardDBManager is DB manager for Board in memory.
+type BoardDBManager struct{}
+
+// Create registers a Board.
+func (*BoardDBManager) Create(ctx context.Context, tx usecase.Transaction, board model.Board) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("board", board.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if _, ok := s.get("boards", board.ID); ok {
+			return model.ServerError{
+				UserID: board.UserID,
+				Err:    nil,
+				ID:     board.ID,
+				Act:    "create board",
+			}
+		}
+		s.put(t, "boards", board.ID, savedBoard(board))
+		return nil
+	})
+}
+
+// Update updates specific fields of a Board and bumps its version.
+// If board.Version is not zero, the Board is updated only if it still has the version.
+func (*BoardDBManager) Update(ctx context.Context, tx usecase.Transaction, board model.Board, updates map[string]interface{}) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("board", board.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		old, ok := s.get("boards", board.ID)
+		saved := 0
+		if ok {
+			saved = old.(model.Board).Version
+		}
+		if err := checkVersion(board.Version, saved, board.ID, board.UserID, "update board"); err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		b := apply(old, updates).(model.Board)
+		b.Version++
+		s.put(t, "boards", board.ID, savedBoard(b))
+		return nil
+	})
+}
+
+// Delete removes a Board.
+// If board.Version is not zero, the Board is removed only if it still has the version.
+func (*BoardDBManager) Delete(ctx context.Context, tx usecase.Transaction, board model.Board) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("board", board.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		saved := 0
+		if old, ok := s.get("boards", board.ID); ok {
+			saved = old.(model.Board).Version
+		}
+		if err := checkVersion(board.Version, saved, board.ID, board.UserID, "delete board"); err != nil {
+			return err
+		}
+
+		s.remove(t, "boards", board.ID)
+		return nil
+	})
+}
+
+// FindByID gets a Board had specific ID.
+func (*BoardDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.Board, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Board{}, err
+	}
+
+	if err := validatePrimaryKeys("board", id); err != nil {
+		return model.Board{}, err
+	}
+
+	var board model.Board
+	var ok bool
+	read(tx, func(s *store) {
+		var r interface{}
+		if r, ok = s.get("boards", id); ok {
+			board = r.(model.Board)
+		}
+	})
+	if !ok {
+		return model.Board{}, notFound(id, "(No-ID)", "find board")
+	}
+	return board, nil
+}
+
+// Find gets Boards matched with a filter.
+func (*BoardDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.BoardFilter) (model.Boards, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Boards{}, err
+	}
+
+	boards := model.Boards{}
+	var err error
+	read(tx, func(s *store) {
+		for _, r := range s.rows("boards") {
+			b := r.(model.Board)
+			if in(filter.IDs, b.ID) && in(filter.UserIDs, b.UserID) && contains(b.Title, filter.TitleContains) {
+				boards = append(boards, b)
+			}
+		}
+
+		err = sortRows("boards", boards, filter.Sort, []usecase.SortKey{usecase.SortByRank, usecase.SortByTitle, usecase.SortByCreatedAt}, func(i int, key usecase.SortKey) interface{} {
+			switch key {
+			case usecase.SortByRank:
+				return boards[i].Rank
+			case usecase.SortByTitle:
+				return boards[i].Title
+			case usecase.SortByCreatedAt:
+				return s.seqOf("boards", boards[i].ID)
+			}
+			return nil
+		}, func(i int) string {
+			return boards[i].ID
+		})
+	})
+	if err != nil {
+		return model.Boards{}, err
+	}
+
+	from, to := pageRange(len(boards), filter.Page)
+	return boards[from:to], nil
+}
+
+// savedBoard drops fields which are not saved in DB.
+func savedBoard(board model.Board) model.Board {
+	board.Lists = nil
+	board.Before = ""
+	return board
+}
@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// CheckItemDBManager is DB manager for CheckItem in memory.
+type CheckItemDBManager struct{}
+
+// Create registers a CheckItem.
+func (*CheckItemDBManager) Create(ctx context.Context, tx usecase.Transaction, checkItem model.CheckItem) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("check item", checkItem.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if _, ok := s.get("check_items", checkItem.ID); ok {
+			return model.ServerError{
+				UserID: checkItem.UserID,
+				Err:    nil,
+				ID:     checkItem.ID,
+				Act:    "create check item",
+			}
+		}
+		s.put(t, "check_items", checkItem.ID, savedCheckItem(checkItem))
+		return nil
+	})
+}
+
+// Update updates specific fields of a CheckItem.
+func (*CheckItemDBManager) Update(ctx context.Context, tx usecase.Transaction, checkItem model.CheckItem, updates map[string]interface{}) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("check item", checkItem.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if old, ok := s.get("check_items", checkItem.ID); ok {
+			s.put(t, "check_items", checkItem.ID, savedCheckItem(apply(old, updates).(model.CheckItem)))
+		}
+		return nil
+	})
+}
+
+// Delete removes a CheckItem.
+func (*CheckItemDBManager) Delete(ctx context.Context, tx usecase.Transaction, checkItem model.CheckItem) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("check item", checkItem.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		s.remove(t, "check_items", checkItem.ID)
+		return nil
+	})
+}
+
+// FindByID gets a CheckItem had specific ID.
+func (*CheckItemDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.CheckItem, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.CheckItem{}, err
+	}
+
+	if err := validatePrimaryKeys("check item", id); err != nil {
+		return model.CheckItem{}, err
+	}
+
+	var checkItem model.CheckItem
+	var ok bool
+	read(tx, func(s *store) {
+		var r interface{}
+		if r, ok = s.get("check_items", id); ok {
+			checkItem = r.(model.CheckItem)
+		}
+	})
+	if !ok {
+		return model.CheckItem{}, notFound(id, "(No-ID)", "find check item")
+	}
+	return checkItem, nil
+}
+
+// Find gets CheckItems matched with a filter.
+func (*CheckItemDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.CheckItemFilter) (model.CheckItems, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.CheckItems{}, err
+	}
+
+	checkItems := model.CheckItems{}
+	var err error
+	read(tx, func(s *store) {
+		for _, r := range s.rows("check_items") {
+			c := r.(model.CheckItem)
+			if in(filter.IDs, c.ID) && in(filter.ChecklistIDs, c.ChecklistID) && in(filter.ItemIDs, c.ItemID) {
+				checkItems = append(checkItems, c)
+			}
+		}
+
+		err = sortRows("check_items", checkItems, filter.Sort, []usecase.SortKey{usecase.SortByRank, usecase.SortByTitle, usecase.SortByCreatedAt}, func(i int, key usecase.SortKey) interface{} {
+			switch key {
+			case usecase.SortByRank:
+				return checkItems[i].Rank
+			case usecase.SortByTitle:
+				return checkItems[i].Title
+			case usecase.SortByCreatedAt:
+				return s.seqOf("check_items", checkItems[i].ID)
+			}
+			return nil
+		}, func(i int) string {
+			return checkItems[i].ID
+		})
+	})
+	if err != nil {
+		return model.CheckItems{}, err
+	}
+
+	from, to := pageRange(len(checkItems), filter.Page)
+	return checkItems[from:to], nil
+}
+
+// savedCheckItem drops fields which are not saved in DB.
+func savedCheckItem(checkItem model.CheckItem) model.CheckItem {
+	checkItem.Before = ""
+	return checkItem
+}
@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// ChecklistDBManager is DB manager for Checklist in memory.
+type ChecklistDBManager struct{}
+
+// Create registers a Checklist.
+func (*ChecklistDBManager) Create(ctx context.Context, tx usecase.Transaction, checklist model.Checklist) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("checklist", checklist.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if _, ok := s.get("checklists", checklist.ID); ok {
+			return model.ServerError{
+				UserID: checklist.UserID,
+				Err:    nil,
+				ID:     checklist.ID,
+				Act:    "create checklist",
+			}
+		}
+		s.put(t, "checklists", checklist.ID, savedChecklist(checklist))
+		return nil
+	})
+}
+
+// Update updates specific fields of a Checklist.
+func (*ChecklistDBManager) Update(ctx context.Context, tx usecase.Transaction, checklist model.Checklist, updates map[string]interface{}) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("checklist", checklist.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if old, ok := s.get("checklists", checklist.ID); ok {
+			s.put(t, "checklists", checklist.ID, savedChecklist(apply(old, updates).(model.Checklist)))
+		}
+		return nil
+	})
+}
+
+// Delete removes a Checklist.
+func (*ChecklistDBManager) Delete(ctx context.Context, tx usecase.Transaction, checklist model.Checklist) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("checklist", checklist.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		s.remove(t, "checklists", checklist.ID)
+		return nil
+	})
+}
+
+// FindByID gets a Checklist had specific ID.
+func (*ChecklistDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.Checklist, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Checklist{}, err
+	}
+
+	if err := validatePrimaryKeys("checklist", id); err != nil {
+		return model.Checklist{}, err
+	}
+
+	var checklist model.Checklist
+	var ok bool
+	read(tx, func(s *store) {
+		var r interface{}
+		if r, ok = s.get("checklists", id); ok {
+			checklist = loadedChecklist(r.(model.Checklist))
+		}
+	})
+	if !ok {
+		return model.Checklist{}, notFound(id, "(No-ID)", "find checklist")
+	}
+	return checklist, nil
+}
+
+// Find gets Checklists matched with a filter.
+func (*ChecklistDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.ChecklistFilter) (model.Checklists, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Checklists{}, err
+	}
+
+	checklists := model.Checklists{}
+	var err error
+	read(tx, func(s *store) {
+		for _, r := range s.rows("checklists") {
+			c := r.(model.Checklist)
+			if in(filter.IDs, c.ID) && in(filter.ItemIDs, c.ItemID) {
+				checklists = append(checklists, loadedChecklist(c))
+			}
+		}
+
+		err = sortRows("checklists", checklists, filter.Sort, []usecase.SortKey{usecase.SortByRank, usecase.SortByTitle, usecase.SortByCreatedAt}, func(i int, key usecase.SortKey) interface{} {
+			switch key {
+			case usecase.SortByRank:
+				return checklists[i].Rank
+			case usecase.SortByTitle:
+				return checklists[i].Title
+			case usecase.SortByCreatedAt:
+				return s.seqOf("checklists", checklists[i].ID)
+			}
+			return nil
+		}, func(i int) string {
+			return checklists[i].ID
+		})
+	})
+	if err != nil {
+		return model.Checklists{}, err
+	}
+
+	from, to := pageRange(len(checklists), filter.Page)
+	return checklists[from:to], nil
+}
+
+// savedChecklist drops fields which are not saved in DB.
+func savedChecklist(checklist model.Checklist) model.Checklist {
+	checklist.CheckItems = nil
+	checklist.Before = ""
+	return checklist
+}
+
+// loadedChecklist fills fields like Checklists got from DB.
+func loadedChecklist(checklist model.Checklist) model.Checklist {
+	checklist.CheckItems = model.CheckItems{}
+	return checklist
+}
@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// CommentDBManager is DB manager for Comment in memory.
+type CommentDBManager struct{}
+
+// Create registers a Comment.
+func (*CommentDBManager) Create(ctx context.Context, tx usecase.Transaction, comment model.Comment) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("comment", comment.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if _, ok := s.get("comments", comment.ID); ok {
+			return model.ServerError{
+				UserID: comment.UserID,
+				Err:    nil,
+				ID:     comment.ID,
+				Act:    "create comment",
+			}
+		}
+		s.put(t, "comments", comment.ID, savedComment(comment))
+		return nil
+	})
+}
+
+// Update updates specific fields of a Comment. UpdatedAt is set to the current time like in DB.
+func (*CommentDBManager) Update(ctx context.Context, tx usecase.Transaction, comment model.Comment, updates map[string]interface{}) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("comment", comment.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if old, ok := s.get("comments", comment.ID); ok {
+			c := apply(old, updates).(model.Comment)
+			c.UpdatedAt = time.Now()
+			s.put(t, "comments", comment.ID, savedComment(c))
+		}
+		return nil
+	})
+}
+
+// Delete removes a Comment.
+func (*CommentDBManager) Delete(ctx context.Context, tx usecase.Transaction, comment model.Comment) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("comment", comment.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		s.remove(t, "comments", comment.ID)
+		return nil
+	})
+}
+
+// FindByID gets a Comment had specific ID.
+func (*CommentDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.Comment, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Comment{}, err
+	}
+
+	if err := validatePrimaryKeys("comment", id); err != nil {
+		return model.Comment{}, err
+	}
+
+	var comment model.Comment
+	var ok bool
+	read(tx, func(s *store) {
+		var r interface{}
+		if r, ok = s.get("comments", id); ok {
+			comment = r.(model.Comment)
+		}
+	})
+	if !ok {
+		return model.Comment{}, notFound(id, "(No-ID)", "find comment")
+	}
+	return comment, nil
+}
+
+// Find gets Comments matched with a filter.
+func (*CommentDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.CommentFilter) (model.Comments, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Comments{}, err
+	}
+
+	comments := model.Comments{}
+	var err error
+	read(tx, func(s *store) {
+		for _, r := range s.rows("comments") {
+			c := r.(model.Comment)
+			if in(filter.IDs, c.ID) && in(filter.ItemIDs, c.ItemID) && in(filter.UserIDs, c.UserID) && contains(c.Text, filter.TextContains) {
+				comments = append(comments, c)
+			}
+		}
+
+		err = sortRows("comments", comments, filter.Sort, []usecase.SortKey{usecase.SortByCreatedAt}, func(i int, key usecase.SortKey) interface{} {
+			switch key {
+			case usecase.SortByCreatedAt:
+				return comments[i].CreatedAt
+			}
+			return nil
+		}, func(i int) string {
+			return comments[i].ID
+		})
+	})
+	if err != nil {
+		return model.Comments{}, err
+	}
+
+	from, to := pageRange(len(comments), filter.Page)
+	return comments[from:to], nil
+}
+
+// savedComment drops fields which are not saved in DB.
+func savedComment(comment model.Comment) model.Comment {
+	comment.UserName = ""
+	return comment
+}
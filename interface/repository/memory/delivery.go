@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// DeliveryDBManager is DB manager for Delivery in memory.
+type DeliveryDBManager struct{}
+
+// Create registers a Delivery.
+func (*DeliveryDBManager) Create(ctx context.Context, tx usecase.Transaction, delivery model.Delivery) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("delivery", delivery.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if _, ok := s.get("deliveries", delivery.ID); ok {
+			return model.ServerError{
+				UserID: "(No-ID)",
+				Err:    nil,
+				ID:     delivery.ID,
+				Act:    "create delivery",
+			}
+		}
+		s.put(t, "deliveries", delivery.ID, delivery)
+		return nil
+	})
+}
+
+// Update updates specific fields of a Delivery. UpdatedAt is set to the current time like in DB.
+func (*DeliveryDBManager) Update(ctx context.Context, tx usecase.Transaction, delivery model.Delivery, updates map[string]interface{}) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("delivery", delivery.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if old, ok := s.get("deliveries", delivery.ID); ok {
+			d := apply(old, updates).(model.Delivery)
+			d.UpdatedAt = time.Now()
+			s.put(t, "deliveries", delivery.ID, d)
+		}
+		return nil
+	})
+}
+
+// Claim takes a pending Delivery for an attempt if it still has Attempts of delivery.
+func (*DeliveryDBManager) Claim(ctx context.Context, tx usecase.Transaction, delivery model.Delivery, until time.Time) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("delivery", delivery.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		r, ok := s.get("deliveries", delivery.ID)
+		d, _ := r.(model.Delivery)
+		if !ok || d.Status != model.PENDING || d.Attempts != delivery.Attempts {
+			return model.PreconditionFailedError{
+				UserID: "(No-ID)",
+				Err:    nil,
+				ID:     delivery.ID,
+				Act:    "claim delivery",
+			}
+		}
+		d.Attempts++
+		d.NextAttemptAt = until
+		d.UpdatedAt = time.Now()
+		s.put(t, "deliveries", delivery.ID, d)
+		return nil
+	})
+}
+
+// Delete removes a Delivery.
+func (*DeliveryDBManager) Delete(ctx context.Context, tx usecase.Transaction, delivery model.Delivery) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("delivery", delivery.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		s.remove(t, "deliveries", delivery.ID)
+		return nil
+	})
+}
+
+// Find gets Deliveries matched with a filter.
+func (*DeliveryDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.DeliveryFilter) (model.Deliveries, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Deliveries{}, err
+	}
+
+	statuses := []string(nil)
+	if filter.Statuses != nil {
+		statuses = []string{}
+		for _, s := range filter.Statuses {
+			statuses = append(statuses, string(s))
+		}
+	}
+
+	deliveries := model.Deliveries{}
+	var err error
+	read(tx, func(s *store) {
+		for _, r := range s.rows("deliveries") {
+			d := r.(model.Delivery)
+			due := filter.DueUntil.IsZero() || !d.NextAttemptAt.After(filter.DueUntil)
+			if in(filter.IDs, d.ID) && in(filter.WebhookIDs, d.WebhookID) && in(statuses, string(d.Status)) && due {
+				deliveries = append(deliveries, d)
+			}
+		}
+
+		err = sortRows("deliveries", deliveries, filter.Sort, []usecase.SortKey{usecase.SortByCreatedAt}, func(i int, key usecase.SortKey) interface{} {
+			switch key {
+			case usecase.SortByCreatedAt:
+				return s.seqOf("deliveries", deliveries[i].ID)
+			}
+			return nil
+		}, func(i int) string {
+			return deliveries[i].ID
+		})
+	})
+	if err != nil {
+		return model.Deliveries{}, err
+	}
+
+	from, to := pageRange(len(deliveries), filter.Page)
+	return deliveries[from:to], nil
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// in checks that a value is one of values. Any value is in nil values like in DB managers.
+func in(values []string, value string) bool {
+	if values == nil {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// contains checks that a text includes a sub text case-insensitively.
+func contains(text, sub string) bool {
+	return strings.Contains(strings.ToLower(text), strings.ToLower(sub))
+}
+
+// sortRows sorts rows, which is a slice, by a sort key and then by ID.
+// Value returns a value of a key of the i-th row, and keys are keys which a table supports.
+// Rows are ordered by sequence numbers for SortByCreatedAt if they have no creation time.
+// A zero time is ordered first like NULL in DB.
+func sortRows(table string, rows interface{}, s usecase.Sort, keys []usecase.SortKey, value func(i int, key usecase.SortKey) interface{}, id func(i int) string) error {
+	if s.Key == "" {
+		return nil
+	}
+
+	supported := false
+	for _, k := range keys {
+		supported = supported || k == s.Key
+	}
+	if !supported {
+		return model.InvalidContentError{
+			UserID: "(No-ID)",
+			Err:    nil,
+			ID:     "(No-ID)",
+			Act:    "validate sort key(" + string(s.Key) + ") of " + table,
+		}
+	}
+
+	sort.SliceStable(rows, func(a, b int) bool {
+		c := compare(value(a, s.Key), value(b, s.Key))
+		if s.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+		return id(a) < id(b)
+	})
+	return nil
+}
+
+func compare(a, b interface{}) int {
+	switch av := a.(type) {
+	case string:
+		return strings.Compare(av, b.(string))
+	case uint64:
+		bv := b.(uint64)
+		switch {
+		case av == bv:
+			return 0
+		case av < bv:
+			return -1
+		default:
+			return 1
+		}
+	case time.Time:
+		bv := b.(time.Time)
+		switch {
+		case av.Equal(bv):
+			return 0
+		case av.IsZero() || (!bv.IsZero() && av.Before(bv)):
+			return -1
+		default:
+			return 1
+		}
+	}
+	return 0
+}
+
+// pageRange returns a range of rows in a page.
+func pageRange(n int, page usecase.Page) (int, int) {
+	from := page.Offset
+	if from > n {
+		from = n
+	}
+	to := n
+	if page.Limit > 0 && from+page.Limit < n {
+		to = from + page.Limit
+	}
+	return from, to
+}
@@ -0,0 +1,234 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// ItemDBManager is DB manager for Item in memory.
+// Tags of Items are saved as IDs and filled from saved Tags when Items are got.
+type ItemDBManager struct{}
+
+// Create registers a Item.
+func (*ItemDBManager) Create(ctx context.Context, tx usecase.Transaction, item model.Item) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("item", item.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if _, ok := s.get("items", item.ID); ok {
+			return model.ServerError{
+				UserID: item.UserID,
+				Err:    nil,
+				ID:     item.ID,
+				Act:    "create item",
+			}
+		}
+		if err := validateTags(s, item); err != nil {
+			return err
+		}
+		s.put(t, "items", item.ID, savedItem(item))
+		return nil
+	})
+}
+
+// Update updates specific fields of a Item and bumps its version. Tags are replaced with Tags had IDs in updates["Tags"].
+// If item.Version is not zero, the Item is updated only if it still has the version.
+func (*ItemDBManager) Update(ctx context.Context, tx usecase.Transaction, item model.Item, updates map[string]interface{}) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("item", item.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		old, ok := s.get("items", item.ID)
+		saved := 0
+		if ok {
+			saved = old.(model.Item).Version
+		}
+		if err := checkVersion(item.Version, saved, item.ID, item.UserID, "update item"); err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		i := apply(old, updates).(model.Item)
+		i.Version++
+		if v, ok := updates["Tags"]; ok {
+			i.Tags = model.Tags{}
+			for _, id := range v.([]string) {
+				i.Tags = append(i.Tags, model.Tag{ID: id})
+			}
+			if err := validateTags(s, i); err != nil {
+				return err
+			}
+		}
+		s.put(t, "items", item.ID, savedItem(i))
+		return nil
+	})
+}
+
+// Delete removes a Item.
+// If item.Version is not zero, the Item is removed only if it still has the version.
+func (*ItemDBManager) Delete(ctx context.Context, tx usecase.Transaction, item model.Item) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("item", item.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		saved := 0
+		if old, ok := s.get("items", item.ID); ok {
+			saved = old.(model.Item).Version
+		}
+		if err := checkVersion(item.Version, saved, item.ID, item.UserID, "delete item"); err != nil {
+			return err
+		}
+
+		s.remove(t, "items", item.ID)
+		return nil
+	})
+}
+
+// FindByID gets a Item had specific ID.
+func (*ItemDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.Item, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Item{}, err
+	}
+
+	if err := validatePrimaryKeys("item", id); err != nil {
+		return model.Item{}, err
+	}
+
+	var item model.Item
+	var ok bool
+	read(tx, func(s *store) {
+		var r interface{}
+		if r, ok = s.get("items", id); ok {
+			item = loadedItem(s, r.(model.Item))
+		}
+	})
+	if !ok {
+		return model.Item{}, notFound(id, "(No-ID)", "find item")
+	}
+	return item, nil
+}
+
+// Find gets Items matched with a filter.
+func (*ItemDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.ItemFilter) (model.Items, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Items{}, err
+	}
+
+	items := model.Items{}
+	var err error
+	read(tx, func(s *store) {
+		for _, r := range s.rows("items") {
+			i := r.(model.Item)
+			if in(filter.IDs, i.ID) && in(filter.ListIDs, i.ListID) && hasTag(i, filter.TagIDs) && contains(i.Title, filter.TitleContains) &&
+				(filter.Completed == nil || i.Completed == *filter.Completed) &&
+				(filter.DueUntil.IsZero() || !i.DueDate.IsZero() && !i.DueDate.After(filter.DueUntil)) {
+				items = append(items, loadedItem(s, i))
+			}
+		}
+
+		err = sortRows("items", items, filter.Sort, []usecase.SortKey{usecase.SortByRank, usecase.SortByTitle, usecase.SortByDueDate, usecase.SortByCreatedAt}, func(i int, key usecase.SortKey) interface{} {
+			switch key {
+			case usecase.SortByRank:
+				return items[i].Rank
+			case usecase.SortByTitle:
+				return items[i].Title
+			case usecase.SortByDueDate:
+				return items[i].DueDate
+			case usecase.SortByCreatedAt:
+				return s.seqOf("items", items[i].ID)
+			}
+			return nil
+		}, func(i int) string {
+			return items[i].ID
+		})
+	})
+	if err != nil {
+		return model.Items{}, err
+	}
+
+	from, to := pageRange(len(items), filter.Page)
+	return items[from:to], nil
+}
+
+// hasTag checks that a Item has one of Tags. Any Item matches nil tagIDs.
+func hasTag(item model.Item, tagIDs []string) bool {
+	if tagIDs == nil {
+		return true
+	}
+	for _, t := range item.Tags {
+		if in(tagIDs, t.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTags checks that Tags attached to a Item exist like foreign keys in DB.
+func validateTags(s *store, item model.Item) error {
+	for _, t := range item.Tags {
+		if _, ok := s.get("tags", t.ID); !ok {
+			return model.ServerError{
+				UserID: item.UserID,
+				Err:    nil,
+				ID:     item.ID,
+				Act:    "attach tag(" + t.ID + ") to item",
+			}
+		}
+	}
+	return nil
+}
+
+// savedItem drops fields which are not saved in DB. Tags are saved as IDs, and dates are
+// saved in UTC without fractional seconds.
+func savedItem(item model.Item) model.Item {
+	tags := model.Tags{}
+	for _, t := range item.Tags {
+		tags = append(tags, model.Tag{ID: t.ID})
+	}
+	item.Tags = tags
+	item.StartDate = convertTime(item.StartDate)
+	item.DueDate = convertTime(item.DueDate)
+	item.CheckItemsDone = 0
+	item.CheckItemsTotal = 0
+	item.Before = ""
+	return item
+}
+
+// loadedItem fills Tags of a Item. Removed Tags are dropped.
+func loadedItem(s *store, item model.Item) model.Item {
+	tags := model.Tags{}
+	for _, t := range item.Tags {
+		if r, ok := s.get("tags", t.ID); ok {
+			tags = append(tags, r.(model.Tag))
+		}
+	}
+	item.Tags = tags
+	return item
+}
+
+func convertTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.UTC().Truncate(time.Second)
+}
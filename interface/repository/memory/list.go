@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"context"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// ListDBManager is DB manager for List in memory.
+type ListDBManager struct{}
+
+// Create registers a List.
+func (*ListDBManager) Create(ctx context.Context, tx usecase.Transaction, list model.List) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("list", list.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if _, ok := s.get("lists", list.ID); ok {
+			return model.ServerError{
+				UserID: list.UserID,
+				Err:    nil,
+				ID:     list.ID,
+				Act:    "create list",
+			}
+		}
+		s.put(t, "lists", list.ID, savedList(list))
+		return nil
+	})
+}
+
+// Update updates specific fields of a List and bumps its version.
+// If list.Version is not zero, the List is updated only if it still has the version.
+func (*ListDBManager) Update(ctx context.Context, tx usecase.Transaction, list model.List, updates map[string]interface{}) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("list", list.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		old, ok := s.get("lists", list.ID)
+		saved := 0
+		if ok {
+			saved = old.(model.List).Version
+		}
+		if err := checkVersion(list.Version, saved, list.ID, list.UserID, "update list"); err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+
+		l := apply(old, updates).(model.List)
+		l.Version++
+		s.put(t, "lists", list.ID, savedList(l))
+		return nil
+	})
+}
+
+// Delete removes a List.
+// If list.Version is not zero, the List is removed only if it still has the version.
+func (*ListDBManager) Delete(ctx context.Context, tx usecase.Transaction, list model.List) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("list", list.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		saved := 0
+		if old, ok := s.get("lists", list.ID); ok {
+			saved = old.(model.List).Version
+		}
+		if err := checkVersion(list.Version, saved, list.ID, list.UserID, "delete list"); err != nil {
+			return err
+		}
+
+		s.remove(t, "lists", list.ID)
+		return nil
+	})
+}
+
+// FindByID gets a List had specific ID.
+func (*ListDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.List, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.List{}, err
+	}
+
+	if err := validatePrimaryKeys("list", id); err != nil {
+		return model.List{}, err
+	}
+
+	var list model.List
+	var ok bool
+	read(tx, func(s *store) {
+		var r interface{}
+		if r, ok = s.get("lists", id); ok {
+			list = r.(model.List)
+		}
+	})
+	if !ok {
+		return model.List{}, notFound(id, "(No-ID)", "find list")
+	}
+	return list, nil
+}
+
+// Find gets Lists matched with a filter.
+func (*ListDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.ListFilter) (model.Lists, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Lists{}, err
+	}
+
+	lists := model.Lists{}
+	var err error
+	read(tx, func(s *store) {
+		for _, r := range s.rows("lists") {
+			l := r.(model.List)
+			if in(filter.IDs, l.ID) && in(filter.BoardIDs, l.BoardID) && contains(l.Title, filter.TitleContains) {
+				lists = append(lists, l)
+			}
+		}
+
+		err = sortRows("lists", lists, filter.Sort, []usecase.SortKey{usecase.SortByRank, usecase.SortByTitle, usecase.SortByCreatedAt}, func(i int, key usecase.SortKey) interface{} {
+			switch key {
+			case usecase.SortByRank:
+				return lists[i].Rank
+			case usecase.SortByTitle:
+				return lists[i].Title
+			case usecase.SortByCreatedAt:
+				return s.seqOf("lists", lists[i].ID)
+			}
+			return nil
+		}, func(i int) string {
+			return lists[i].ID
+		})
+	})
+	if err != nil {
+		return model.Lists{}, err
+	}
+
+	from, to := pageRange(len(lists), filter.Page)
+	return lists[from:to], nil
+}
+
+// savedList drops fields which are not saved in DB.
+func savedList(list model.List) model.List {
+	list.Items = nil
+	list.Before = ""
+	return list
+}
@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// MemberDBManager is DB manager for Member in memory.
+// A Member is identified by a pair of BoardID and UserID.
+type MemberDBManager struct{}
+
+func memberKey(boardID, userID string) string {
+	return boardID + "/" + userID
+}
+
+// Create registers a Member.
+func (*MemberDBManager) Create(ctx context.Context, tx usecase.Transaction, member model.Member) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("member", member.BoardID, member.UserID); err != nil {
+		return err
+	}
+
+	key := memberKey(member.BoardID, member.UserID)
+	return write(tx, func(s *store, t *Transaction) error {
+		if _, ok := s.get("members", key); ok {
+			return model.ServerError{
+				UserID: member.UserID,
+				Err:    nil,
+				ID:     member.BoardID,
+				Act:    "create member",
+			}
+		}
+		s.put(t, "members", key, savedMember(member))
+		return nil
+	})
+}
+
+// Update updates specific fields of a Member.
+func (*MemberDBManager) Update(ctx context.Context, tx usecase.Transaction, member model.Member, updates map[string]interface{}) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("member", member.BoardID, member.UserID); err != nil {
+		return err
+	}
+
+	key := memberKey(member.BoardID, member.UserID)
+	return write(tx, func(s *store, t *Transaction) error {
+		if old, ok := s.get("members", key); ok {
+			s.put(t, "members", key, savedMember(apply(old, updates).(model.Member)))
+		}
+		return nil
+	})
+}
+
+// Delete removes a Member.
+func (*MemberDBManager) Delete(ctx context.Context, tx usecase.Transaction, member model.Member) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("member", member.BoardID, member.UserID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		s.remove(t, "members", memberKey(member.BoardID, member.UserID))
+		return nil
+	})
+}
+
+// FindByID gets a Member of a Board.
+func (*MemberDBManager) FindByID(ctx context.Context, tx usecase.Transaction, boardID, userID string) (model.Member, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Member{}, err
+	}
+
+	if err := validatePrimaryKeys("member", boardID, userID); err != nil {
+		return model.Member{}, err
+	}
+
+	var member model.Member
+	var ok bool
+	read(tx, func(s *store) {
+		var r interface{}
+		if r, ok = s.get("members", memberKey(boardID, userID)); ok {
+			member = r.(model.Member)
+		}
+	})
+	if !ok {
+		return model.Member{}, notFound(boardID, userID, "find member")
+	}
+	return member, nil
+}
+
+// Find gets Members matched with a filter.
+func (*MemberDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.MemberFilter) (model.Members, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Members{}, err
+	}
+
+	var roles []string
+	if filter.Roles != nil {
+		roles = []string{}
+		for _, role := range filter.Roles {
+			roles = append(roles, string(role))
+		}
+	}
+
+	members := model.Members{}
+	read(tx, func(s *store) {
+		for _, r := range s.rows("members") {
+			m := r.(model.Member)
+			if in(filter.BoardIDs, m.BoardID) && in(filter.UserIDs, m.UserID) && in(roles, string(m.Role)) {
+				members = append(members, m)
+			}
+		}
+	})
+
+	from, to := pageRange(len(members), filter.Page)
+	return members[from:to], nil
+}
+
+// savedMember drops fields which are not saved in DB.
+func savedMember(member model.Member) model.Member {
+	member.UserName = ""
+	return member
+}
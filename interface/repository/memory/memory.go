@@ -0,0 +1,28 @@
+package memory
+
+// DBManager includes DB managers for all data model. They keep data in memory instead of DB.
+// Data is lost when the process exits.
+type DBManager struct {
+	TransactionManager   TransactionManager
+	ItemDBManager        ItemDBManager
+	ListDBManager        ListDBManager
+	BoardDBManager       BoardDBManager
+	UserDBManager        UserDBManager
+	TagDBManager         TagDBManager
+	MemberDBManager      MemberDBManager
+	ChecklistDBManager   ChecklistDBManager
+	CheckItemDBManager   CheckItemDBManager
+	CommentDBManager     CommentDBManager
+	ActivityDBManager    ActivityDBManager
+	WebhookDBManager     WebhookDBManager
+	DeliveryDBManager    DeliveryDBManager
+	AccessTokenDBManager AccessTokenDBManager
+	SessionDBManager     SessionDBManager
+}
+
+// NewDBManager generates new DB manager having no data.
+func NewDBManager() DBManager {
+	return DBManager{
+		TransactionManager: newTransactionManager(newStore()),
+	}
+}
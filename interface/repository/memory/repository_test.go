@@ -0,0 +1,24 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/x-color/vue-trello/interface/repository/repotest"
+)
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) (repotest.Repositories, func()) {
+		tm := newTransactionManager(newStore())
+		return repotest.Repositories{
+			Tx:       &tm,
+			Board:    &BoardDBManager{},
+			List:     &ListDBManager{},
+			Item:     &ItemDBManager{},
+			Tag:      &TagDBManager{},
+			User:     &UserDBManager{},
+			Member:   &MemberDBManager{},
+			Activity: &ActivityDBManager{},
+			Delivery: &DeliveryDBManager{},
+		}, func() {}
+	})
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// SessionDBManager is DB manager for Session in memory.
+type SessionDBManager struct{}
+
+// Create registers a Session.
+func (*SessionDBManager) Create(ctx context.Context, tx usecase.Transaction, session model.Session) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("session", session.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if _, ok := s.get("sessions", session.ID); ok {
+			return model.ServerError{
+				UserID: session.UserID,
+				Err:    nil,
+				ID:     session.ID,
+				Act:    "create session",
+			}
+		}
+		s.put(t, "sessions", session.ID, session)
+		return nil
+	})
+}
+
+// Delete removes a Session.
+func (*SessionDBManager) Delete(ctx context.Context, tx usecase.Transaction, session model.Session) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("session", session.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		s.remove(t, "sessions", session.ID)
+		return nil
+	})
+}
+
+// FindByID gets a Session had specific ID.
+func (*SessionDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.Session, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Session{}, err
+	}
+
+	if err := validatePrimaryKeys("session", id); err != nil {
+		return model.Session{}, err
+	}
+
+	var session model.Session
+	var ok bool
+	read(tx, func(s *store) {
+		var r interface{}
+		if r, ok = s.get("sessions", id); ok {
+			session = r.(model.Session)
+		}
+	})
+	if !ok {
+		return model.Session{}, notFound(id, "(No-ID)", "find session")
+	}
+	return session, nil
+}
+
+// Find gets Sessions matched with a filter.
+func (*SessionDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.SessionFilter) (model.Sessions, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Sessions{}, err
+	}
+
+	sessions := model.Sessions{}
+	read(tx, func(s *store) {
+		for _, r := range s.rows("sessions") {
+			session := r.(model.Session)
+			if in(filter.IDs, session.ID) && in(filter.UserIDs, session.UserID) {
+				sessions = append(sessions, session)
+			}
+		}
+	})
+
+	from, to := pageRange(len(sessions), filter.Page)
+	return sessions[from:to], nil
+}
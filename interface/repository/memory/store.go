@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// store keeps rows of all tables in memory.
+// Rows have sequence numbers to be listed in order of creation like rows in DB.
+type store struct {
+	mu     sync.RWMutex
+	seq    uint64
+	tables map[string]map[string]row
+}
+
+type row struct {
+	seq   uint64
+	value interface{}
+}
+
+func newStore() *store {
+	return &store{
+		tables: map[string]map[string]row{},
+	}
+}
+
+// table returns rows of a table. It is nil if the table has no rows yet, so readers sharing a lock
+// never change tables.
+func (s *store) table(name string) map[string]row {
+	return s.tables[name]
+}
+
+// get returns a row had key in a table.
+func (s *store) get(table, key string) (interface{}, bool) {
+	r, ok := s.table(table)[key]
+	return r.value, ok
+}
+
+// put saves a row. It records how to undo the change if tx is a transaction.
+func (s *store) put(tx *Transaction, table, key string, value interface{}) {
+	t := s.table(table)
+	if t == nil {
+		t = map[string]row{}
+		s.tables[table] = t
+	}
+	old, ok := t[key]
+	if ok {
+		t[key] = row{seq: old.seq, value: value}
+	} else {
+		s.seq++
+		t[key] = row{seq: s.seq, value: value}
+	}
+
+	tx.record(func() {
+		if ok {
+			t[key] = old
+		} else {
+			delete(t, key)
+		}
+	})
+}
+
+// remove deletes a row. It records how to undo the change if tx is a transaction.
+func (s *store) remove(tx *Transaction, table, key string) {
+	t := s.table(table)
+	old, ok := t[key]
+	if !ok {
+		return
+	}
+	delete(t, key)
+
+	tx.record(func() {
+		t[key] = old
+	})
+}
+
+// rows returns all rows of a table in order of creation.
+func (s *store) rows(table string) []interface{} {
+	rows := []row{}
+	for _, r := range s.table(table) {
+		rows = append(rows, r)
+	}
+	sort.Slice(rows, func(a, b int) bool {
+		return rows[a].seq < rows[b].seq
+	})
+
+	values := []interface{}{}
+	for _, r := range rows {
+		values = append(values, r.value)
+	}
+	return values
+}
+
+// seqOf returns a sequence number of a row. It is 0 if the row does not exist.
+func (s *store) seqOf(table, key string) uint64 {
+	return s.table(table)[key].seq
+}
+
+// read runs f with a lock for reading unless tx is a transaction, which already has a lock.
+func read(tx usecase.Transaction, f func(s *store)) {
+	t := tx.DB().(*Transaction)
+	if !t.locked() {
+		t.s.mu.RLock()
+		defer t.s.mu.RUnlock()
+	}
+	f(t.s)
+}
+
+// write runs f with a lock for writing unless tx is a transaction, which already has a lock.
+func write(tx usecase.Transaction, f func(s *store, t *Transaction) error) error {
+	t := tx.DB().(*Transaction)
+	if !t.locked() {
+		t.s.mu.Lock()
+		defer t.s.mu.Unlock()
+	}
+	return f(t.s, t)
+}
+
+// apply returns a copy of value which fields are changed by updates. Keys of updates are names of fields.
+// Unknown keys are ignored like in DB managers.
+func apply(value interface{}, updates map[string]interface{}) interface{} {
+	p := reflect.New(reflect.TypeOf(value))
+	p.Elem().Set(reflect.ValueOf(value))
+	for k, u := range updates {
+		f := p.Elem().FieldByName(k)
+		if !f.IsValid() || !f.CanSet() {
+			continue
+		}
+		uv := reflect.ValueOf(u)
+		if !uv.IsValid() || uv.Kind() != f.Kind() || !uv.Type().ConvertibleTo(f.Type()) {
+			continue
+		}
+		f.Set(uv.Convert(f.Type()))
+	}
+	return p.Elem().Interface()
+}
+
+// checkContext returns TimeoutError if a context is done like DB managers for DB.
+func checkContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return model.TimeoutError{
+			UserID: "(No-ID)",
+			Err:    err,
+			ID:     "(No-ID)",
+			Act:    "access data",
+		}
+	}
+	return nil
+}
+
+func validatePrimaryKeys(targetName string, keys ...string) error {
+	for _, k := range keys {
+		if k == "" {
+			return model.NotFoundError{
+				UserID: "(No-ID)",
+				Err:    nil,
+				ID:     "(No-ID)",
+				Act:    "validate " + targetName + "'s primary key",
+			}
+		}
+	}
+	return nil
+}
+
+func notFound(id, userID, act string) error {
+	return model.NotFoundError{
+		UserID: userID,
+		Err:    nil,
+		ID:     id,
+		Act:    act,
+	}
+}
+
+// checkVersion returns PreconditionFailedError if version is not zero and it is not a saved version.
+// saved is zero if a content does not exist.
+func checkVersion(version, saved int, id, userID, act string) error {
+	if version == 0 || version == saved {
+		return nil
+	}
+	return model.PreconditionFailedError{
+		UserID: userID,
+		Err:    nil,
+		ID:     id,
+		Act:    act,
+	}
+}
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// TagDBManager is DB manager for Tag in memory.
+type TagDBManager struct{}
+
+// Create registers a Tag.
+func (*TagDBManager) Create(ctx context.Context, tx usecase.Transaction, tag model.Tag) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("tag", tag.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if _, ok := s.get("tags", tag.ID); ok {
+			return model.ServerError{
+				UserID: tag.UserID,
+				Err:    nil,
+				ID:     tag.ID,
+				Act:    "create tag",
+			}
+		}
+		s.put(t, "tags", tag.ID, tag)
+		return nil
+	})
+}
+
+// Update updates specific fields of a Tag.
+func (*TagDBManager) Update(ctx context.Context, tx usecase.Transaction, tag model.Tag, updates map[string]interface{}) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("tag", tag.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if old, ok := s.get("tags", tag.ID); ok {
+			s.put(t, "tags", tag.ID, apply(old, updates))
+		}
+		return nil
+	})
+}
+
+// Delete removes a Tag.
+func (*TagDBManager) Delete(ctx context.Context, tx usecase.Transaction, tag model.Tag) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("tag", tag.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		s.remove(t, "tags", tag.ID)
+		return nil
+	})
+}
+
+// FindByID gets a Tag had specific ID.
+func (*TagDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.Tag, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Tag{}, err
+	}
+
+	if err := validatePrimaryKeys("tag", id); err != nil {
+		return model.Tag{}, err
+	}
+
+	var tag model.Tag
+	var ok bool
+	read(tx, func(s *store) {
+		var r interface{}
+		if r, ok = s.get("tags", id); ok {
+			tag = r.(model.Tag)
+		}
+	})
+	if !ok {
+		return model.Tag{}, notFound(id, "(No-ID)", "find tag")
+	}
+	return tag, nil
+}
+
+// Find gets Tags matched with a filter.
+func (*TagDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.TagFilter) (model.Tags, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Tags{}, err
+	}
+
+	tags := model.Tags{}
+	var err error
+	read(tx, func(s *store) {
+		for _, r := range s.rows("tags") {
+			t := r.(model.Tag)
+			if in(filter.IDs, t.ID) && in(filter.UserIDs, t.UserID) && in(filter.BoardIDs, t.BoardID) && contains(t.Name, filter.NameContains) {
+				tags = append(tags, t)
+			}
+		}
+
+		err = sortRows("tags", tags, filter.Sort, []usecase.SortKey{usecase.SortByName, usecase.SortByCreatedAt}, func(i int, key usecase.SortKey) interface{} {
+			switch key {
+			case usecase.SortByName:
+				return tags[i].Name
+			case usecase.SortByCreatedAt:
+				return s.seqOf("tags", tags[i].ID)
+			}
+			return nil
+		}, func(i int) string {
+			return tags[i].ID
+		})
+	})
+	if err != nil {
+		return model.Tags{}, err
+	}
+
+	from, to := pageRange(len(tags), filter.Page)
+	return tags[from:to], nil
+}
@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// AccessTokenDBManager is DB manager for AccessToken in memory.
+type AccessTokenDBManager struct{}
+
+// Create registers a AccessToken.
+func (*AccessTokenDBManager) Create(ctx context.Context, tx usecase.Transaction, token model.AccessToken) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("access token", token.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if _, ok := s.get("access_tokens", token.ID); ok {
+			return model.ServerError{
+				UserID: token.UserID,
+				Err:    nil,
+				ID:     token.ID,
+				Act:    "create access token",
+			}
+		}
+		token.Token = ""
+		s.put(t, "access_tokens", token.ID, token)
+		return nil
+	})
+}
+
+// Delete removes a AccessToken.
+func (*AccessTokenDBManager) Delete(ctx context.Context, tx usecase.Transaction, token model.AccessToken) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("access token", token.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		s.remove(t, "access_tokens", token.ID)
+		return nil
+	})
+}
+
+// FindByID gets a AccessToken had specific ID.
+func (*AccessTokenDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.AccessToken, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.AccessToken{}, err
+	}
+
+	if err := validatePrimaryKeys("access token", id); err != nil {
+		return model.AccessToken{}, err
+	}
+
+	var token model.AccessToken
+	var ok bool
+	read(tx, func(s *store) {
+		var r interface{}
+		if r, ok = s.get("access_tokens", id); ok {
+			token = r.(model.AccessToken)
+		}
+	})
+	if !ok {
+		return model.AccessToken{}, notFound(id, "(No-ID)", "find access token")
+	}
+	return token, nil
+}
+
+// FindByHash gets a AccessToken had specific hash.
+func (*AccessTokenDBManager) FindByHash(ctx context.Context, tx usecase.Transaction, hash string) (model.AccessToken, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.AccessToken{}, err
+	}
+
+	if err := validatePrimaryKeys("access token", hash); err != nil {
+		return model.AccessToken{}, err
+	}
+
+	var token model.AccessToken
+	ok := false
+	read(tx, func(s *store) {
+		for _, r := range s.rows("access_tokens") {
+			if a := r.(model.AccessToken); a.Hash == hash {
+				token, ok = a, true
+				return
+			}
+		}
+	})
+	if !ok {
+		return model.AccessToken{}, notFound("(No-ID)", "(No-ID)", "find access token by hash")
+	}
+	return token, nil
+}
+
+// Find gets AccessTokens matched with a filter.
+func (*AccessTokenDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.AccessTokenFilter) (model.AccessTokens, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.AccessTokens{}, err
+	}
+
+	tokens := model.AccessTokens{}
+	read(tx, func(s *store) {
+		for _, r := range s.rows("access_tokens") {
+			a := r.(model.AccessToken)
+			if in(filter.IDs, a.ID) && in(filter.UserIDs, a.UserID) {
+				tokens = append(tokens, a)
+			}
+		}
+	})
+
+	from, to := pageRange(len(tokens), filter.Page)
+	return tokens[from:to], nil
+}
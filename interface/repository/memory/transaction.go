@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// TransactionManager is transaction manager for data in memory.
+// A transaction locks all data until it is committed or rolled back, so transactions are serialized.
+type TransactionManager struct {
+	s *store
+}
+
+// BeginTransaction starts a transaction if on is true.
+// Otherwise, each operation is done atomically and can not be rolled back.
+// Data is not changed after a context is done because DB managers check it before each operation.
+func (m *TransactionManager) BeginTransaction(ctx context.Context, on bool) usecase.Transaction {
+	if on {
+		m.s.mu.Lock()
+	}
+	return &Transaction{
+		s:  m.s,
+		on: on,
+	}
+}
+
+// RunInTx runs f in a transaction. The transaction is committed if f returns nil,
+// and rolled back if f returns an error or panics.
+func (m *TransactionManager) RunInTx(ctx context.Context, f func(tx usecase.Transaction) error) error {
+	tx := m.BeginTransaction(ctx, true)
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func newTransactionManager(s *store) TransactionManager {
+	return TransactionManager{
+		s: s,
+	}
+}
+
+// Transaction is a transaction for data in memory. It keeps functions to undo changes in it.
+type Transaction struct {
+	s    *store
+	on   bool
+	done bool
+	undo []func()
+}
+
+// DB returns the Transaction itself. DB managers in this package use it to access data.
+func (tx *Transaction) DB() interface{} {
+	return interface{}(tx)
+}
+
+// Commit keeps changes in the transaction and unlocks data.
+func (tx *Transaction) Commit() error {
+	if !tx.locked() {
+		return nil
+	}
+	tx.undo = nil
+	tx.done = true
+	tx.s.mu.Unlock()
+	return nil
+}
+
+// Rollback undoes changes in the transaction in reverse order and unlocks data.
+func (tx *Transaction) Rollback() error {
+	if !tx.locked() {
+		return nil
+	}
+	for j := len(tx.undo) - 1; j >= 0; j-- {
+		tx.undo[j]()
+	}
+	tx.undo = nil
+	tx.done = true
+	tx.s.mu.Unlock()
+	return nil
+}
+
+func (tx *Transaction) locked() bool {
+	return tx.on && !tx.done
+}
+
+func (tx *Transaction) record(undo func()) {
+	if tx.locked() {
+		tx.undo = append(tx.undo, undo)
+	}
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// UserDBManager is DB manager for User in memory.
+type UserDBManager struct{}
+
+// Create registers a User.
+func (*UserDBManager) Create(ctx context.Context, tx usecase.Transaction, user model.User) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("user", user.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if _, ok := s.get("users", user.ID); ok {
+			return model.ServerError{
+				UserID: user.ID,
+				Err:    nil,
+				ID:     user.ID,
+				Act:    "create user",
+			}
+		}
+		s.put(t, "users", user.ID, user)
+		return nil
+	})
+}
+
+// Update updates a User.
+func (*UserDBManager) Update(ctx context.Context, tx usecase.Transaction, user model.User, updates map[string]interface{}) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("user", user.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if old, ok := s.get("users", user.ID); ok {
+			s.put(t, "users", user.ID, apply(old, updates))
+		}
+		return nil
+	})
+}
+
+// Delete removes a User.
+func (*UserDBManager) Delete(ctx context.Context, tx usecase.Transaction, user model.User) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("user", user.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		s.remove(t, "users", user.ID)
+		return nil
+	})
+}
+
+// Find gets a User matched with a filter. The oldest User is got if some Users match it.
+func (*UserDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.UserFilter) (model.User, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.User{}, err
+	}
+
+	var user model.User
+	var ok bool
+	read(tx, func(s *store) {
+		for _, r := range s.rows("users") {
+			u := r.(model.User)
+			if in(filter.IDs, u.ID) && in(filter.Names, u.Name) {
+				user, ok = u, true
+				return
+			}
+		}
+	})
+	if !ok {
+		id := "(No-ID)"
+		if len(filter.IDs) == 1 {
+			id = filter.IDs[0]
+		}
+		return model.User{}, notFound(id, id, "find user")
+	}
+	return user, nil
+}
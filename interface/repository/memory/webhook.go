@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// WebhookDBManager is DB manager for Webhook in memory.
+type WebhookDBManager struct{}
+
+// Create registers a Webhook.
+func (*WebhookDBManager) Create(ctx context.Context, tx usecase.Transaction, webhook model.Webhook) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("webhook", webhook.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		if _, ok := s.get("webhooks", webhook.ID); ok {
+			return model.ServerError{
+				UserID: webhook.UserID,
+				Err:    nil,
+				ID:     webhook.ID,
+				Act:    "create webhook",
+			}
+		}
+		webhook.Events = append([]string{}, webhook.Events...)
+		s.put(t, "webhooks", webhook.ID, webhook)
+		return nil
+	})
+}
+
+// Delete removes a Webhook.
+func (*WebhookDBManager) Delete(ctx context.Context, tx usecase.Transaction, webhook model.Webhook) error {
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("webhook", webhook.ID); err != nil {
+		return err
+	}
+
+	return write(tx, func(s *store, t *Transaction) error {
+		s.remove(t, "webhooks", webhook.ID)
+		return nil
+	})
+}
+
+// FindByID gets a Webhook had specific ID.
+func (*WebhookDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.Webhook, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Webhook{}, err
+	}
+
+	if err := validatePrimaryKeys("webhook", id); err != nil {
+		return model.Webhook{}, err
+	}
+
+	var webhook model.Webhook
+	var ok bool
+	read(tx, func(s *store) {
+		var r interface{}
+		if r, ok = s.get("webhooks", id); ok {
+			webhook = r.(model.Webhook)
+		}
+	})
+	if !ok {
+		return model.Webhook{}, notFound(id, "(No-ID)", "find webhook")
+	}
+	return webhook, nil
+}
+
+// Find gets Webhooks matched with a filter.
+func (*WebhookDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.WebhookFilter) (model.Webhooks, error) {
+	if err := checkContext(ctx); err != nil {
+		return model.Webhooks{}, err
+	}
+
+	webhooks := model.Webhooks{}
+	read(tx, func(s *store) {
+		for _, r := range s.rows("webhooks") {
+			w := r.(model.Webhook)
+			if in(filter.IDs, w.ID) && in(filter.BoardIDs, w.BoardID) {
+				webhooks = append(webhooks, w)
+			}
+		}
+	})
+
+	from, to := pageRange(len(webhooks), filter.Page)
+	return webhooks[from:to], nil
+}
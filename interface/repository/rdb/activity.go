@@ -0,0 +1,248 @@
+package rdb
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Activity is Activity data model for DB.
+// Seq keeps the order of activities. It is used as a cursor of pages, so readers which have got an Activity
+// must never see a newly committed Activity with smaller Seq. SQLite keeps it because its writes are serialized.
+// PostgreSQL can commit transactions in another order than they get Seq, so Create holds an advisory lock of
+// the Board until the transaction ends. Activities in a Board are committed in order of Seq, and readers of
+// several Boards take shared locks of them to wait for transactions recording Activities.
+type Activity struct {
+	Seq       uint64 `gorm:"primary_key;AUTO_INCREMENT"`
+	ID        string `gorm:"unique_index"`
+	BoardID   string `gorm:"index"`
+	UserID    string
+	Action    string
+	Target    string
+	TargetID  string
+	Before    string
+	After     string
+	CreatedAt time.Time
+}
+
+func (a *Activity) convertFrom(activity model.Activity) {
+	a.ID = activity.ID
+	a.BoardID = activity.BoardID
+	a.UserID = activity.UserID
+	a.Action = string(activity.Action)
+	a.Target = string(activity.Target)
+	a.TargetID = activity.TargetID
+	a.Before = activity.Before
+	a.After = activity.After
+	a.CreatedAt = activity.CreatedAt
+}
+
+func (a *Activity) convertTo() model.Activity {
+	activity := model.Activity{
+		ID:        a.ID,
+		BoardID:   a.BoardID,
+		UserID:    a.UserID,
+		Action:    model.Action(a.Action),
+		Target:    model.Target(a.Target),
+		TargetID:  a.TargetID,
+		Before:    a.Before,
+		After:     a.After,
+		CreatedAt: a.CreatedAt,
+	}
+	return activity
+}
+
+// activityLockKey is the first key of PostgreSQL advisory locks of Boards. The second key is a hash of a Board ID.
+const activityLockKey = 7204613
+
+// Activities is a slice of Activity data model.
+type Activities []Activity
+
+// ActivityDBManager is DB manager for Activity.
+type ActivityDBManager struct{}
+
+// Create registers a Activity to DB.
+func (*ActivityDBManager) Create(ctx context.Context, tx usecase.Transaction, activity model.Activity) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("activity", activity.ID); err != nil {
+		return err
+	}
+
+	a := Activity{}
+	a.convertFrom(activity)
+
+	if db.Dialect().GetName() != "postgres" {
+		if err := db.Create(&a).Error; err != nil {
+			return convertError(ctx, err, a.ID, a.UserID, "create activity")
+		}
+		return nil
+	}
+
+	return inOwnTx(ctx, tx, db, func(db *gorm.DB) error {
+		if err := lockBoards(db, false, []string{a.BoardID}); err != nil {
+			return convertError(ctx, err, a.ID, a.UserID, "lock activities")
+		}
+		if err := db.Create(&a).Error; err != nil {
+			return convertError(ctx, err, a.ID, a.UserID, "create activity")
+		}
+		return nil
+	})
+}
+
+// FindPage gets Activities in a Board from newest to oldest.
+// If cursor is not empty, it gets Activities older than the Activity had cursor as ID.
+func (*ActivityDBManager) FindPage(ctx context.Context, tx usecase.Transaction, boardID, cursor string, limit int) (model.Activities, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Activities{}, err
+	}
+
+	q := db.Where(&Activity{BoardID: boardID})
+	if cursor != "" {
+		c := Activity{}
+		if err := db.Where(&Activity{ID: cursor, BoardID: boardID}).First(&c).Error; err != nil {
+			return model.Activities{}, convertError(ctx, err, cursor, "(No-ID)", "find cursor of activities")
+		}
+		q = q.Where("seq < ?", c.Seq)
+	}
+
+	r := Activities{}
+	if err := q.Order("seq desc").Limit(limit).Find(&r).Error; err != nil {
+		return model.Activities{}, convertError(ctx, err, boardID, "(No-ID)", "find page of activities")
+	}
+
+	activities := model.Activities{}
+	for _, ra := range r {
+		activities = append(activities, ra.convertTo())
+	}
+
+	return activities, nil
+}
+
+// FindSince gets Activities in a Board recorded after the Activity had cursor as ID from oldest to newest.
+func (*ActivityDBManager) FindSince(ctx context.Context, tx usecase.Transaction, boardID, cursor string, limit int) (model.Activities, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Activities{}, err
+	}
+
+	if err := validatePrimaryKeys("activity", cursor); err != nil {
+		return model.Activities{}, err
+	}
+
+	c := Activity{}
+	if err := db.Where(&Activity{ID: cursor, BoardID: boardID}).First(&c).Error; err != nil {
+		return model.Activities{}, convertError(ctx, err, cursor, "(No-ID)", "find cursor of activities")
+	}
+
+	r := Activities{}
+	err = db.Where(&Activity{BoardID: boardID}).Where("seq > ?", c.Seq).Order("seq").Limit(limit).Find(&r).Error
+	if err != nil {
+		return model.Activities{}, convertError(ctx, err, boardID, "(No-ID)", "find activities since cursor")
+	}
+
+	activities := model.Activities{}
+	for _, ra := range r {
+		activities = append(activities, ra.convertTo())
+	}
+
+	return activities, nil
+}
+
+// Find gets Activities in order of recording.
+func (*ActivityDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.ActivityFilter) (model.Activities, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Activities{}, err
+	}
+
+	r := Activities{}
+	find := func(db *gorm.DB) error {
+		q := whereIn(db, "id", filter.IDs)
+		q = whereIn(q, "board_id", filter.BoardIDs)
+		if filter.After != "" {
+			c := Activity{}
+			if err := db.Where(&Activity{ID: filter.After}).First(&c).Error; err != nil {
+				return convertError(ctx, err, filter.After, "(No-ID)", "find cursor of activities")
+			}
+			q = q.Where("seq > ?", c.Seq)
+		}
+		order := "seq"
+		if filter.Desc {
+			order = "seq desc"
+		}
+
+		if err := paginate(q.Order(order), filter.Page).Find(&r).Error; err != nil {
+			return convertError(ctx, err, idForError(filter.BoardIDs), "(No-ID)", "find activities")
+		}
+		return nil
+	}
+
+	if db.Dialect().GetName() == "postgres" && len(filter.BoardIDs) > 0 {
+		// Activities being recorded in the Boards are committed before reading, so that a cursor got
+		// from the Boards is never followed by an Activity committed later with smaller Seq.
+		err = inOwnTx(ctx, tx, db, func(db *gorm.DB) error {
+			if err := lockBoards(db, true, filter.BoardIDs); err != nil {
+				return convertError(ctx, err, idForError(filter.BoardIDs), "(No-ID)", "lock activities")
+			}
+			return find(db)
+		})
+	} else {
+		err = find(db)
+	}
+	if err != nil {
+		return model.Activities{}, err
+	}
+
+	activities := model.Activities{}
+	for _, ra := range r {
+		activities = append(activities, ra.convertTo())
+	}
+
+	return activities, nil
+}
+
+// lockBoards holds PostgreSQL advisory locks of Boards until the transaction of db ends. Recording an Activity
+// holds an exclusive lock of its Board, and reading Activities of Boards holds shared locks. Locks are taken
+// in order of Board IDs, so transactions locking several Boards do not deadlock.
+func lockBoards(db *gorm.DB, shared bool, boardIDs []string) error {
+	lock := "pg_advisory_xact_lock"
+	if shared {
+		lock = "pg_advisory_xact_lock_shared"
+	}
+
+	ids := append([]string{}, boardIDs...)
+	sort.Strings(ids)
+	for _, id := range ids {
+		if err := db.Exec("SELECT "+lock+"(?, hashtext(?))", activityLockKey, id).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inOwnTx runs f in the transaction of tx. If tx has no transaction, f runs in a new transaction,
+// so that locks taken by f are held until its changes are committed.
+func inOwnTx(ctx context.Context, tx usecase.Transaction, db *gorm.DB, f func(db *gorm.DB) error) error {
+	if t, ok := tx.(*Transaction); !ok || t.on {
+		return f(db)
+	}
+
+	db = db.BeginTx(ctx, nil)
+	defer db.RollbackUnlessCommitted()
+	if err := f(db); err != nil {
+		return err
+	}
+	if err := db.Commit().Error; err != nil {
+		return convertError(ctx, err, "(No-ID)", "(No-ID)", "commit transaction")
+	}
+	return nil
+}
@@ -1,9 +1,9 @@
 package rdb
 
 import (
+	"context"
 	"time"
 
-	"github.com/jinzhu/gorm"
 	"github.com/x-color/vue-trello/model"
 	"github.com/x-color/vue-trello/usecase"
 )
@@ -11,12 +11,12 @@ import (
 // Board is Board data model for DB.
 type Board struct {
 	ID        string `gorm:"primary_key"`
-	UserID    string `gorm:"primary_key"`
+	UserID    string
 	Title     string
 	Text      *string
 	Color     string
-	Before    *string
-	After     *string
+	Rank      string
+	Version   int
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
@@ -24,6 +24,8 @@ type Board struct {
 
 func (b *Board) convertFrom(board model.Board) {
 	b.ID = board.ID
+	b.Rank = board.Rank
+	b.Version = board.Version
 	b.UserID = board.UserID
 	b.Title = board.Title
 	b.Color = string(board.Color)
@@ -33,26 +35,16 @@ func (b *Board) convertFrom(board model.Board) {
 	} else {
 		b.Text = &board.Text
 	}
-
-	if board.Before == "" {
-		b.Before = nil
-	} else {
-		b.Before = &board.Before
-	}
-
-	if board.After == "" {
-		b.After = nil
-	} else {
-		b.After = &board.After
-	}
 }
 
 func (b *Board) convertTo() model.Board {
 	board := model.Board{
-		ID:     b.ID,
-		UserID: b.UserID,
-		Title:  b.Title,
-		Color:  model.Color(b.Color),
+		ID:      b.ID,
+		Rank:    b.Rank,
+		Version: b.Version,
+		UserID:  b.UserID,
+		Title:   b.Title,
+		Color:   model.Color(b.Color),
 	}
 
 	if b.Text == nil {
@@ -61,18 +53,6 @@ func (b *Board) convertTo() model.Board {
 		board.Text = *b.Text
 	}
 
-	if b.After == nil {
-		board.After = ""
-	} else {
-		board.After = *b.After
-	}
-
-	if b.Before == nil {
-		board.Before = ""
-	} else {
-		board.Before = *b.Before
-	}
-
 	return board
 }
 
@@ -82,94 +62,102 @@ type Boards []Board
 // BoardDBManager is DB manager for Board.
 type BoardDBManager struct{}
 
-func newBoardDBManager(db *gorm.DB) BoardDBManager {
-	db.AutoMigrate(&Board{})
-	return BoardDBManager{}
-}
-
 // Create registers a Board to DB.
-func (*BoardDBManager) Create(tx usecase.Transaction, board model.Board) error {
-	if err := validatePrimaryKeys("board", board.ID, board.UserID); err != nil {
+func (*BoardDBManager) Create(ctx context.Context, tx usecase.Transaction, board model.Board) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("board", board.ID); err != nil {
 		return err
 	}
 
 	b := Board{}
 	b.convertFrom(board)
 
-	if err := tx.DB().(*gorm.DB).Create(&b).Error; err != nil {
-		return model.ServerError{
-			UserID: board.UserID,
-			Err:    err,
-			ID:     board.ID,
-			Act:    "create board",
-		}
+	if err := db.Create(&b).Error; err != nil {
+		return convertError(ctx, err, board.ID, board.UserID, "create board")
 	}
 
 	return nil
 }
 
-// Update updates all fields of specific Board in DB.
-func (*BoardDBManager) Update(tx usecase.Transaction, board model.Board, updates map[string]interface{}) error {
-	if err := validatePrimaryKeys("board", board.ID, board.UserID); err != nil {
+// Update updates all fields of specific Board in DB and bumps its version.
+// If board.Version is not zero, the Board is updated only if it still has the version.
+func (*BoardDBManager) Update(ctx context.Context, tx usecase.Transaction, board model.Board, updates map[string]interface{}) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("board", board.ID); err != nil {
 		return err
 	}
 
 	b := Board{}
 	b.convertFrom(board)
-	err := tx.DB().(*gorm.DB).Model(&b).Updates(queryForBoard(updates)).Error
+	return updateVersioned(ctx, db, &b, board.Version, queryForBoard(updates), b.ID, b.UserID, "update board")
+}
 
+// Delete removes a Board from DB.
+// If board.Version is not zero, the Board is removed only if it still has the version.
+func (*BoardDBManager) Delete(ctx context.Context, tx usecase.Transaction, board model.Board) error {
+	db, err := dbOf(ctx, tx)
 	if err != nil {
-		return convertError(err, b.ID, b.UserID, "update board")
+		return err
 	}
-	return nil
-}
 
-// Delete removes a Board from DB.
-func (*BoardDBManager) Delete(tx usecase.Transaction, board model.Board) error {
-	if err := validatePrimaryKeys("board", board.ID, board.UserID); err != nil {
+	if err := validatePrimaryKeys("board", board.ID); err != nil {
 		return err
 	}
 
 	b := Board{}
 	b.convertFrom(board)
 
-	if err := tx.DB().(*gorm.DB).Delete(&b).Error; err != nil {
-		return convertError(err, b.ID, b.UserID, "delete board")
-	}
-	return nil
+	return deleteVersioned(ctx, db, &b, board.Version, b.ID, b.UserID, "delete board")
 }
 
 // FindByID gets a Board had specific ID from DB.
-func (*BoardDBManager) FindByID(tx usecase.Transaction, id, userID string) (model.Board, error) {
-	if err := validatePrimaryKeys("board", id, userID); err != nil {
+func (*BoardDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.Board, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Board{}, err
+	}
+
+	if err := validatePrimaryKeys("board", id); err != nil {
 		return model.Board{}, err
 	}
 
 	r := Board{}
-	if err := tx.DB().(*gorm.DB).Where(&Board{ID: id, UserID: userID}).First(&r).Error; err != nil {
-		return model.Board{}, convertError(err, id, userID, "find board")
+	if err := db.Where(&Board{ID: id}).First(&r).Error; err != nil {
+		return model.Board{}, convertError(ctx, err, id, "(No-ID)", "find board")
 	}
 	return r.convertTo(), nil
 }
 
-// Find gets Boards.
-func (*BoardDBManager) Find(tx usecase.Transaction, conditions map[string]interface{}) (model.Boards, error) {
+// Find gets Boards matched with a filter.
+func (*BoardDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.BoardFilter) (model.Boards, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Boards{}, err
+	}
+
+	db = whereIn(db, "id", filter.IDs)
+	db = whereIn(db, "user_id", filter.UserIDs)
+	db = whereContains(db, "title", filter.TitleContains)
+	db, err = orderBy(db, "boards", filter.Sort, map[usecase.SortKey]string{
+		usecase.SortByRank:      "rank",
+		usecase.SortByTitle:     "title",
+		usecase.SortByCreatedAt: "created_at",
+	})
+	if err != nil {
+		return model.Boards{}, err
+	}
+
 	r := Boards{}
-	if err := tx.DB().(*gorm.DB).Where(queryForBoard(conditions)).Find(&r).Error; err != nil {
-		userID := "(No-ID)"
-		if v, ok := conditions["user_id"]; ok {
-			userID = v.(string)
-		}
-		id := "(No-ID)"
-		if v, ok := conditions["id"]; ok {
-			id = v.(string)
-		}
-		return model.Boards{}, model.ServerError{
-			UserID: userID,
-			Err:    err,
-			ID:     id,
-			Act:    "find items",
-		}
+	if err := paginate(db, filter.Page).Find(&r).Error; err != nil {
+		return model.Boards{}, convertError(ctx, err, idForError(filter.IDs), idForError(filter.UserIDs), "find boards")
 	}
 
 	boards := model.Boards{}
@@ -192,28 +180,17 @@ func queryForBoard(data map[string]interface{}) map[string]interface{} {
 		query["title"] = v
 	}
 	if v, ok := data["Text"]; ok {
-		if v.(string) == "" {
+		if v == "" {
 			query["text"] = nil
 		} else {
 			query["text"] = v
 		}
 	}
 	if v, ok := data["Color"]; ok {
-		query["color"] = v.(string)
-	}
-	if v, ok := data["Before"]; ok {
-		if v.(string) == "" {
-			query["before"] = nil
-		} else {
-			query["before"] = v
-		}
+		query["color"] = v
 	}
-	if v, ok := data["After"]; ok {
-		if v.(string) == "" {
-			query["after"] = nil
-		} else {
-			query["after"] = v
-		}
+	if v, ok := data["Rank"]; ok {
+		query["rank"] = v
 	}
 	return query
 }
@@ -0,0 +1,190 @@
+package rdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// CheckItem is CheckItem data model for DB.
+type CheckItem struct {
+	ID          string `gorm:"primary_key"`
+	ChecklistID string
+	ItemID      string
+	UserID      string
+	Title       string
+	Checked     bool
+	Rank        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time
+}
+
+func (c *CheckItem) convertFrom(checkItem model.CheckItem) {
+	c.ID = checkItem.ID
+	c.Rank = checkItem.Rank
+	c.ChecklistID = checkItem.ChecklistID
+	c.ItemID = checkItem.ItemID
+	c.UserID = checkItem.UserID
+	c.Title = checkItem.Title
+	c.Checked = checkItem.Checked
+}
+
+func (c *CheckItem) convertTo() model.CheckItem {
+	checkItem := model.CheckItem{
+		ID:          c.ID,
+		Rank:        c.Rank,
+		ChecklistID: c.ChecklistID,
+		ItemID:      c.ItemID,
+		UserID:      c.UserID,
+		Title:       c.Title,
+		Checked:     c.Checked,
+	}
+
+	return checkItem
+}
+
+// CheckItems is a slice of CheckItem data model.
+type CheckItems []CheckItem
+
+// CheckItemDBManager is DB manager for CheckItem.
+type CheckItemDBManager struct{}
+
+// Create registers a CheckItem to DB.
+func (*CheckItemDBManager) Create(ctx context.Context, tx usecase.Transaction, checkItem model.CheckItem) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("check item", checkItem.ID); err != nil {
+		return err
+	}
+
+	c := CheckItem{}
+	c.convertFrom(checkItem)
+
+	if err := db.Create(&c).Error; err != nil {
+		return convertError(ctx, err, c.ID, c.UserID, "create check item")
+	}
+
+	return nil
+}
+
+// Update updates all fields of specific CheckItem in DB.
+func (*CheckItemDBManager) Update(ctx context.Context, tx usecase.Transaction, checkItem model.CheckItem, updates map[string]interface{}) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("check item", checkItem.ID); err != nil {
+		return err
+	}
+
+	c := CheckItem{}
+	c.convertFrom(checkItem)
+	err = db.Model(&c).Updates(queryForCheckItem(updates)).Error
+	if err != nil {
+		return convertError(ctx, err, c.ID, c.UserID, "update check item")
+	}
+	return nil
+}
+
+// Delete removes a CheckItem from DB.
+func (*CheckItemDBManager) Delete(ctx context.Context, tx usecase.Transaction, checkItem model.CheckItem) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("check item", checkItem.ID); err != nil {
+		return err
+	}
+
+	c := CheckItem{}
+	c.convertFrom(checkItem)
+
+	if err := db.Delete(&c).Error; err != nil {
+		return convertError(ctx, err, c.ID, c.UserID, "delete check item")
+	}
+	return nil
+}
+
+// FindByID gets a CheckItem had specific ID from DB.
+func (*CheckItemDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.CheckItem, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.CheckItem{}, err
+	}
+
+	if err := validatePrimaryKeys("check item", id); err != nil {
+		return model.CheckItem{}, err
+	}
+
+	r := CheckItem{}
+	if err := db.Where(&CheckItem{ID: id}).First(&r).Error; err != nil {
+		return model.CheckItem{}, convertError(ctx, err, id, "(No-ID)", "find check item")
+	}
+	return r.convertTo(), nil
+}
+
+// Find gets CheckItems matched with a filter.
+func (*CheckItemDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.CheckItemFilter) (model.CheckItems, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.CheckItems{}, err
+	}
+
+	db = whereIn(db, "id", filter.IDs)
+	db = whereIn(db, "checklist_id", filter.ChecklistIDs)
+	db = whereIn(db, "item_id", filter.ItemIDs)
+	db, err = orderBy(db, "check_items", filter.Sort, map[usecase.SortKey]string{
+		usecase.SortByRank:      "rank",
+		usecase.SortByTitle:     "title",
+		usecase.SortByCreatedAt: "created_at",
+	})
+	if err != nil {
+		return model.CheckItems{}, err
+	}
+
+	r := CheckItems{}
+	if err := paginate(db, filter.Page).Find(&r).Error; err != nil {
+		return model.CheckItems{}, convertError(ctx, err, idForError(filter.ChecklistIDs), "(No-ID)", "find check items")
+	}
+
+	checkItems := model.CheckItems{}
+	for _, rc := range r {
+		checkItems = append(checkItems, rc.convertTo())
+	}
+
+	return checkItems, nil
+}
+
+func queryForCheckItem(data map[string]interface{}) map[string]interface{} {
+	query := make(map[string]interface{})
+	if v, ok := data["ID"]; ok {
+		query["id"] = v
+	}
+	if v, ok := data["ChecklistID"]; ok {
+		query["checklist_id"] = v
+	}
+	if v, ok := data["ItemID"]; ok {
+		query["item_id"] = v
+	}
+	if v, ok := data["UserID"]; ok {
+		query["user_id"] = v
+	}
+	if v, ok := data["Title"]; ok {
+		query["title"] = v
+	}
+	if v, ok := data["Checked"]; ok {
+		query["checked"] = v
+	}
+	if v, ok := data["Rank"]; ok {
+		query["rank"] = v
+	}
+	return query
+}
@@ -0,0 +1,178 @@
+package rdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Checklist is Checklist data model for DB.
+type Checklist struct {
+	ID        string `gorm:"primary_key"`
+	ItemID    string
+	UserID    string
+	Title     string
+	Rank      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (c *Checklist) convertFrom(checklist model.Checklist) {
+	c.ID = checklist.ID
+	c.Rank = checklist.Rank
+	c.ItemID = checklist.ItemID
+	c.UserID = checklist.UserID
+	c.Title = checklist.Title
+}
+
+func (c *Checklist) convertTo() model.Checklist {
+	checklist := model.Checklist{
+		ID:         c.ID,
+		Rank:       c.Rank,
+		ItemID:     c.ItemID,
+		UserID:     c.UserID,
+		Title:      c.Title,
+		CheckItems: model.CheckItems{},
+	}
+
+	return checklist
+}
+
+// Checklists is a slice of Checklist data model.
+type Checklists []Checklist
+
+// ChecklistDBManager is DB manager for Checklist.
+type ChecklistDBManager struct{}
+
+// Create registers a Checklist to DB.
+func (*ChecklistDBManager) Create(ctx context.Context, tx usecase.Transaction, checklist model.Checklist) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("checklist", checklist.ID); err != nil {
+		return err
+	}
+
+	c := Checklist{}
+	c.convertFrom(checklist)
+
+	if err := db.Create(&c).Error; err != nil {
+		return convertError(ctx, err, c.ID, c.UserID, "create checklist")
+	}
+
+	return nil
+}
+
+// Update updates all fields of specific Checklist in DB.
+func (*ChecklistDBManager) Update(ctx context.Context, tx usecase.Transaction, checklist model.Checklist, updates map[string]interface{}) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("checklist", checklist.ID); err != nil {
+		return err
+	}
+
+	c := Checklist{}
+	c.convertFrom(checklist)
+	err = db.Model(&c).Updates(queryForChecklist(updates)).Error
+	if err != nil {
+		return convertError(ctx, err, c.ID, c.UserID, "update checklist")
+	}
+	return nil
+}
+
+// Delete removes a Checklist from DB.
+func (*ChecklistDBManager) Delete(ctx context.Context, tx usecase.Transaction, checklist model.Checklist) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("checklist", checklist.ID); err != nil {
+		return err
+	}
+
+	c := Checklist{}
+	c.convertFrom(checklist)
+
+	if err := db.Delete(&c).Error; err != nil {
+		return convertError(ctx, err, c.ID, c.UserID, "delete checklist")
+	}
+	return nil
+}
+
+// FindByID gets a Checklist had specific ID from DB.
+func (*ChecklistDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.Checklist, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Checklist{}, err
+	}
+
+	if err := validatePrimaryKeys("checklist", id); err != nil {
+		return model.Checklist{}, err
+	}
+
+	r := Checklist{}
+	if err := db.Where(&Checklist{ID: id}).First(&r).Error; err != nil {
+		return model.Checklist{}, convertError(ctx, err, id, "(No-ID)", "find checklist")
+	}
+	return r.convertTo(), nil
+}
+
+// Find gets Checklists matched with a filter.
+func (*ChecklistDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.ChecklistFilter) (model.Checklists, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Checklists{}, err
+	}
+
+	db = whereIn(db, "id", filter.IDs)
+	db = whereIn(db, "item_id", filter.ItemIDs)
+	db, err = orderBy(db, "checklists", filter.Sort, map[usecase.SortKey]string{
+		usecase.SortByRank:      "rank",
+		usecase.SortByTitle:     "title",
+		usecase.SortByCreatedAt: "created_at",
+	})
+	if err != nil {
+		return model.Checklists{}, err
+	}
+
+	r := Checklists{}
+	if err := paginate(db, filter.Page).Find(&r).Error; err != nil {
+		return model.Checklists{}, convertError(ctx, err, idForError(filter.ItemIDs), "(No-ID)", "find checklists")
+	}
+
+	checklists := model.Checklists{}
+	for _, rc := range r {
+		checklists = append(checklists, rc.convertTo())
+	}
+
+	return checklists, nil
+}
+
+func queryForChecklist(data map[string]interface{}) map[string]interface{} {
+	query := make(map[string]interface{})
+	if v, ok := data["ID"]; ok {
+		query["id"] = v
+	}
+	if v, ok := data["ItemID"]; ok {
+		query["item_id"] = v
+	}
+	if v, ok := data["UserID"]; ok {
+		query["user_id"] = v
+	}
+	if v, ok := data["Title"]; ok {
+		query["title"] = v
+	}
+	if v, ok := data["Rank"]; ok {
+		query["rank"] = v
+	}
+	return query
+}
@@ -0,0 +1,173 @@
+package rdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Comment is Comment data model for DB.
+type Comment struct {
+	ID        string `gorm:"primary_key"`
+	ItemID    string
+	UserID    string
+	Text      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (c *Comment) convertFrom(comment model.Comment) {
+	c.ID = comment.ID
+	c.ItemID = comment.ItemID
+	c.UserID = comment.UserID
+	c.Text = comment.Text
+	c.CreatedAt = comment.CreatedAt
+	c.UpdatedAt = comment.UpdatedAt
+}
+
+func (c *Comment) convertTo() model.Comment {
+	comment := model.Comment{
+		ID:        c.ID,
+		ItemID:    c.ItemID,
+		UserID:    c.UserID,
+		Text:      c.Text,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+	return comment
+}
+
+// Comments is a slice of Comment data model.
+type Comments []Comment
+
+// CommentDBManager is DB manager for Comment.
+type CommentDBManager struct{}
+
+// Create registers a Comment to DB.
+func (*CommentDBManager) Create(ctx context.Context, tx usecase.Transaction, comment model.Comment) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("comment", comment.ID); err != nil {
+		return err
+	}
+
+	c := Comment{}
+	c.convertFrom(comment)
+
+	if err := db.Create(&c).Error; err != nil {
+		return convertError(ctx, err, c.ID, c.UserID, "create comment")
+	}
+	return nil
+}
+
+// Update updates all fields of specific Comment in DB.
+func (*CommentDBManager) Update(ctx context.Context, tx usecase.Transaction, comment model.Comment, updates map[string]interface{}) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("comment", comment.ID); err != nil {
+		return err
+	}
+
+	c := Comment{}
+	c.convertFrom(comment)
+	err = db.Model(&c).Updates(queryForComment(updates)).Error
+	if err != nil {
+		return convertError(ctx, err, c.ID, c.UserID, "update comment")
+	}
+	return nil
+}
+
+// Delete removes a Comment from DB.
+func (*CommentDBManager) Delete(ctx context.Context, tx usecase.Transaction, comment model.Comment) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("comment", comment.ID); err != nil {
+		return err
+	}
+
+	c := Comment{}
+	c.convertFrom(comment)
+
+	if err := db.Delete(&c).Error; err != nil {
+		return convertError(ctx, err, c.ID, c.UserID, "delete comment")
+	}
+	return nil
+}
+
+// FindByID gets a Comment had specific ID from DB.
+func (*CommentDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.Comment, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	if err := validatePrimaryKeys("comment", id); err != nil {
+		return model.Comment{}, err
+	}
+
+	r := Comment{}
+	if err := db.Where(&Comment{ID: id}).First(&r).Error; err != nil {
+		return model.Comment{}, convertError(ctx, err, id, "(No-ID)", "find comment")
+	}
+	return r.convertTo(), nil
+}
+
+// Find gets Comments matched with a filter.
+func (*CommentDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.CommentFilter) (model.Comments, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Comments{}, err
+	}
+
+	db = whereIn(db, "id", filter.IDs)
+	db = whereIn(db, "item_id", filter.ItemIDs)
+	db = whereIn(db, "user_id", filter.UserIDs)
+	db = whereContains(db, "text", filter.TextContains)
+	db, err = orderBy(db, "comments", filter.Sort, map[usecase.SortKey]string{
+		usecase.SortByCreatedAt: "created_at",
+	})
+	if err != nil {
+		return model.Comments{}, err
+	}
+
+	r := Comments{}
+	if err := paginate(db, filter.Page).Find(&r).Error; err != nil {
+		return model.Comments{}, convertError(ctx, err, idForError(filter.ItemIDs), "(No-ID)", "find comments")
+	}
+
+	comments := model.Comments{}
+	for _, rc := range r {
+		comments = append(comments, rc.convertTo())
+	}
+
+	return comments, nil
+}
+
+func queryForComment(data map[string]interface{}) map[string]interface{} {
+	query := make(map[string]interface{})
+	if v, ok := data["ID"]; ok {
+		query["id"] = v
+	}
+	if v, ok := data["ItemID"]; ok {
+		query["item_id"] = v
+	}
+	if v, ok := data["UserID"]; ok {
+		query["user_id"] = v
+	}
+	if v, ok := data["Text"]; ok {
+		query["text"] = v
+	}
+	return query
+}
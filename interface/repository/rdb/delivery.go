@@ -0,0 +1,214 @@
+package rdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Delivery is Delivery data model for DB.
+type Delivery struct {
+	ID             string `gorm:"primary_key"`
+	WebhookID      string `gorm:"index"`
+	ActivityID     string
+	Event          string
+	Status         string `gorm:"index:idx_deliveries_status_next_attempt_at"`
+	Attempts       int
+	ResponseStatus int
+	Error          string
+	NextAttemptAt  time.Time `gorm:"index:idx_deliveries_status_next_attempt_at"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (d *Delivery) convertFrom(delivery model.Delivery) {
+	d.ID = delivery.ID
+	d.WebhookID = delivery.WebhookID
+	d.ActivityID = delivery.ActivityID
+	d.Event = delivery.Event
+	d.Status = string(delivery.Status)
+	d.Attempts = delivery.Attempts
+	d.ResponseStatus = delivery.ResponseStatus
+	d.Error = delivery.Error
+	d.NextAttemptAt = delivery.NextAttemptAt
+	d.CreatedAt = delivery.CreatedAt
+	d.UpdatedAt = delivery.UpdatedAt
+}
+
+func (d *Delivery) convertTo() model.Delivery {
+	delivery := model.Delivery{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		ActivityID:     d.ActivityID,
+		Event:          d.Event,
+		Status:         model.DeliveryStatus(d.Status),
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		Error:          d.Error,
+		NextAttemptAt:  d.NextAttemptAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+	return delivery
+}
+
+// Deliveries is a slice of Delivery data model.
+type Deliveries []Delivery
+
+// DeliveryDBManager is DB manager for Delivery.
+type DeliveryDBManager struct{}
+
+// Create registers a Delivery to DB.
+func (*DeliveryDBManager) Create(ctx context.Context, tx usecase.Transaction, delivery model.Delivery) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("delivery", delivery.ID); err != nil {
+		return err
+	}
+
+	d := Delivery{}
+	d.convertFrom(delivery)
+
+	if err := db.Create(&d).Error; err != nil {
+		return convertError(ctx, err, d.ID, "(No-ID)", "create delivery")
+	}
+	return nil
+}
+
+// Update updates specific fields of a Delivery in DB.
+func (*DeliveryDBManager) Update(ctx context.Context, tx usecase.Transaction, delivery model.Delivery, updates map[string]interface{}) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("delivery", delivery.ID); err != nil {
+		return err
+	}
+
+	d := Delivery{}
+	d.convertFrom(delivery)
+	err = db.Model(&d).Updates(queryForDelivery(updates)).Error
+	if err != nil {
+		return convertError(ctx, err, d.ID, "(No-ID)", "update delivery")
+	}
+	return nil
+}
+
+// Claim takes a pending Delivery for an attempt if it still has Attempts of delivery.
+func (*DeliveryDBManager) Claim(ctx context.Context, tx usecase.Transaction, delivery model.Delivery, until time.Time) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("delivery", delivery.ID); err != nil {
+		return err
+	}
+
+	r := db.Model(&Delivery{ID: delivery.ID}).
+		Where("status = ? AND attempts = ?", string(model.PENDING), delivery.Attempts).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": until,
+		})
+	if r.Error != nil {
+		return convertError(ctx, r.Error, delivery.ID, "(No-ID)", "claim delivery")
+	}
+	if r.RowsAffected == 0 {
+		return model.PreconditionFailedError{
+			UserID: "(No-ID)",
+			Err:    nil,
+			ID:     delivery.ID,
+			Act:    "claim delivery",
+		}
+	}
+	return nil
+}
+
+// Delete removes a Delivery from DB.
+func (*DeliveryDBManager) Delete(ctx context.Context, tx usecase.Transaction, delivery model.Delivery) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("delivery", delivery.ID); err != nil {
+		return err
+	}
+
+	d := Delivery{}
+	d.convertFrom(delivery)
+
+	if err := db.Delete(&d).Error; err != nil {
+		return convertError(ctx, err, d.ID, "(No-ID)", "delete delivery")
+	}
+	return nil
+}
+
+// Find gets Deliveries matched with a filter.
+func (*DeliveryDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.DeliveryFilter) (model.Deliveries, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Deliveries{}, err
+	}
+
+	statuses := []string(nil)
+	if filter.Statuses != nil {
+		statuses = []string{}
+		for _, s := range filter.Statuses {
+			statuses = append(statuses, string(s))
+		}
+	}
+
+	db = whereIn(db, "id", filter.IDs)
+	db = whereIn(db, "webhook_id", filter.WebhookIDs)
+	db = whereIn(db, "status", statuses)
+	if !filter.DueUntil.IsZero() {
+		db = db.Where("next_attempt_at <= ?", filter.DueUntil)
+	}
+	db, err = orderBy(db, "deliveries", filter.Sort, map[usecase.SortKey]string{
+		usecase.SortByCreatedAt: "created_at",
+	})
+	if err != nil {
+		return model.Deliveries{}, err
+	}
+
+	r := Deliveries{}
+	if err := paginate(db, filter.Page).Find(&r).Error; err != nil {
+		return model.Deliveries{}, convertError(ctx, err, idForError(filter.WebhookIDs), "(No-ID)", "find deliveries")
+	}
+
+	deliveries := model.Deliveries{}
+	for _, rd := range r {
+		deliveries = append(deliveries, rd.convertTo())
+	}
+
+	return deliveries, nil
+}
+
+func queryForDelivery(data map[string]interface{}) map[string]interface{} {
+	query := make(map[string]interface{})
+	if v, ok := data["Status"]; ok {
+		query["status"] = v
+	}
+	if v, ok := data["Attempts"]; ok {
+		query["attempts"] = v
+	}
+	if v, ok := data["ResponseStatus"]; ok {
+		query["response_status"] = v
+	}
+	if v, ok := data["Error"]; ok {
+		query["error"] = v
+	}
+	if v, ok := data["NextAttemptAt"]; ok {
+		query["next_attempt_at"] = v
+	}
+	return query
+}
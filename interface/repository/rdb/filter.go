@@ -0,0 +1,80 @@
+package rdb
+
+import (
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// whereIn adds a condition that a column has one of values.
+// It adds no condition if values is nil, and matches no row if values is empty.
+func whereIn(db *gorm.DB, column string, values []string) *gorm.DB {
+	if values == nil {
+		return db
+	}
+	if len(values) == 0 {
+		return db.Where("1 = 0")
+	}
+	return db.Where(column+" IN (?)", values)
+}
+
+// whereContains adds a condition that a column includes text case-insensitively.
+func whereContains(db *gorm.DB, column, text string) *gorm.DB {
+	if text == "" {
+		return db
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(text))
+	return db.Where("LOWER("+column+`) LIKE ? ESCAPE '\'`, "%"+escaped+"%")
+}
+
+// orderBy sorts rows by a column for a sort key and then by ID.
+// Columns has columns for keys which a table supports. Strings are compared by bytes in all DBs
+// because ranks depend on the order.
+func orderBy(db *gorm.DB, table string, sort usecase.Sort, columns map[usecase.SortKey]string) (*gorm.DB, error) {
+	if sort.Key == "" {
+		return db, nil
+	}
+
+	column, ok := columns[sort.Key]
+	if !ok {
+		return db, model.InvalidContentError{
+			UserID: "(No-ID)",
+			Err:    nil,
+			ID:     "(No-ID)",
+			Act:    "validate sort key(" + string(sort.Key) + ") of " + table,
+		}
+	}
+
+	collate := ""
+	if db.Dialect().GetName() == "postgres" {
+		collate = ` COLLATE "C"`
+	}
+	if sort.Key == usecase.SortByRank || sort.Key == usecase.SortByTitle || sort.Key == usecase.SortByName {
+		column += collate
+	}
+	if sort.Desc {
+		column += " DESC"
+	}
+	return db.Order(column).Order(table + ".id" + collate), nil
+}
+
+// paginate limits rows to a page.
+func paginate(db *gorm.DB, page usecase.Page) *gorm.DB {
+	if page.Offset > 0 {
+		db = db.Offset(page.Offset)
+	}
+	if page.Limit > 0 {
+		db = db.Limit(page.Limit)
+	}
+	return db
+}
+
+// idForError returns an ID for an error message if a filter selects one ID.
+func idForError(ids []string) string {
+	if len(ids) == 1 {
+		return ids[0]
+	}
+	return "(No-ID)"
+}
@@ -1,10 +1,9 @@
 package rdb
 
 import (
-	"strings"
+	"context"
 	"time"
 
-	"github.com/jinzhu/gorm"
 	"github.com/x-color/vue-trello/model"
 	"github.com/x-color/vue-trello/usecase"
 )
@@ -12,13 +11,15 @@ import (
 // Item is Item data model for DB.
 type Item struct {
 	ID        string `gorm:"primary_key"`
-	UserID    string `gorm:"primary_key"`
+	UserID    string
 	ListID    string
 	Title     string
 	Text      *string
-	Tags      *string
-	Before    *string
-	After     *string
+	StartDate *time.Time
+	DueDate   *time.Time
+	Completed bool
+	Rank      string
+	Version   int
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
@@ -26,71 +27,46 @@ type Item struct {
 
 func (i *Item) convertFrom(item model.Item) {
 	i.ID = item.ID
+	i.Rank = item.Rank
+	i.Version = item.Version
 	i.UserID = item.UserID
 	i.ListID = item.ListID
 	i.Title = item.Title
-
-	tags := []string{}
-	for _, t := range item.Tags {
-		tags = append(tags, t.ID)
-	}
-	ts := strings.Join(tags, ",")
-	if ts == "" {
-		i.Tags = nil
-	} else {
-		i.Tags = &ts
-	}
+	i.StartDate = convertTime(item.StartDate)
+	i.DueDate = convertTime(item.DueDate)
+	i.Completed = item.Completed
 
 	if item.Text == "" {
 		i.Text = nil
 	} else {
 		i.Text = &item.Text
 	}
-
-	if item.Before == "" {
-		i.Before = nil
-	} else {
-		i.Before = &item.Before
-	}
-
-	if item.After == "" {
-		i.After = nil
-	} else {
-		i.After = &item.After
-	}
 }
 
 func (i *Item) convertTo() model.Item {
 	item := model.Item{
-		ID:     i.ID,
-		UserID: i.UserID,
-		ListID: i.ListID,
-		Title:  i.Title,
-		Tags:   model.Tags{},
+		ID:        i.ID,
+		Rank:      i.Rank,
+		Version:   i.Version,
+		UserID:    i.UserID,
+		ListID:    i.ListID,
+		Title:     i.Title,
+		Tags:      model.Tags{},
+		Completed: i.Completed,
 	}
 
-	if i.Text == nil {
-		item.Text = ""
-	} else {
-		item.Text = *i.Text
+	if i.StartDate != nil {
+		item.StartDate = *i.StartDate
 	}
 
-	if i.Tags != nil {
-		for _, tagID := range strings.Split(*i.Tags, ",") {
-			item.Tags = append(item.Tags, model.Tag{ID: tagID})
-		}
+	if i.DueDate != nil {
+		item.DueDate = *i.DueDate
 	}
 
-	if i.After == nil {
-		item.After = ""
-	} else {
-		item.After = *i.After
-	}
-
-	if i.Before == nil {
-		item.Before = ""
+	if i.Text == nil {
+		item.Text = ""
 	} else {
-		item.Before = *i.Before
+		item.Text = *i.Text
 	}
 
 	return item
@@ -102,100 +78,145 @@ type Items []Item
 // ItemDBManager is DB manager for Item.
 type ItemDBManager struct{}
 
-func newItemDBManager(db *gorm.DB) ItemDBManager {
-	db.AutoMigrate(&Item{})
-	return ItemDBManager{}
-}
-
 // Create registers a Item to DB.
-func (*ItemDBManager) Create(tx usecase.Transaction, item model.Item) error {
-	if err := validatePrimaryKeys("item", item.ID, item.UserID); err != nil {
+func (*ItemDBManager) Create(ctx context.Context, tx usecase.Transaction, item model.Item) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("item", item.ID); err != nil {
 		return err
 	}
 
 	i := Item{}
 	i.convertFrom(item)
 
-	if err := tx.DB().(*gorm.DB).Create(&i).Error; err != nil {
-		return model.ServerError{
-			UserID: i.UserID,
-			Err:    err,
-			ID:     i.ID,
-			Act:    "create item",
-		}
+	if err := db.Create(&i).Error; err != nil {
+		return convertError(ctx, err, i.ID, i.UserID, "create item")
+	}
+
+	if err := setItemTags(db, i.ID, tagIDs(item.Tags)); err != nil {
+		return convertError(ctx, err, i.ID, i.UserID, "attach tags to item")
 	}
 
 	return nil
 }
 
-// Update updates all fields of specific Item in DB.
-func (*ItemDBManager) Update(tx usecase.Transaction, item model.Item, updates map[string]interface{}) error {
-	if err := validatePrimaryKeys("item", item.ID, item.UserID); err != nil {
+// Update updates all fields of specific Item in DB and bumps its version.
+// If item.Version is not zero, the Item is updated only if it still has the version.
+func (*ItemDBManager) Update(ctx context.Context, tx usecase.Transaction, item model.Item, updates map[string]interface{}) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("item", item.ID); err != nil {
 		return err
 	}
 
 	i := Item{}
 	i.convertFrom(item)
 
-	err := tx.DB().(*gorm.DB).Model(&i).Updates(queryForItem(updates)).Error
+	if err := updateVersioned(ctx, db, &i, item.Version, queryForItem(updates), i.ID, i.UserID, "update item"); err != nil {
+		return err
+	}
 
-	if err != nil {
-		return convertError(err, i.ID, i.UserID, "update item")
+	if v, ok := updates["Tags"]; ok {
+		tagIDs, ok := v.([]string)
+		if !ok {
+			return model.InvalidContentError{
+				UserID: i.UserID,
+				Err:    nil,
+				ID:     i.ID,
+				Act:    "validate tags of item",
+			}
+		}
+		if err := setItemTags(db, i.ID, tagIDs); err != nil {
+			return convertError(ctx, err, i.ID, i.UserID, "attach tags to item")
+		}
 	}
 	return nil
 }
 
 // Delete removes a Item from DB.
-func (*ItemDBManager) Delete(tx usecase.Transaction, item model.Item) error {
-	if err := validatePrimaryKeys("item", item.ID, item.UserID); err != nil {
+// If item.Version is not zero, the Item is removed only if it still has the version.
+func (*ItemDBManager) Delete(ctx context.Context, tx usecase.Transaction, item model.Item) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("item", item.ID); err != nil {
 		return err
 	}
 
 	i := Item{}
 	i.convertFrom(item)
 
-	if err := tx.DB().(*gorm.DB).Delete(&i).Error; err != nil {
-		return convertError(err, i.ID, i.UserID, "delete item")
-	}
-	return nil
+	return deleteVersioned(ctx, db, &i, item.Version, i.ID, i.UserID, "delete item")
 }
 
 // FindByID gets a Item had specific ID from DB.
-func (*ItemDBManager) FindByID(tx usecase.Transaction, id, userID string) (model.Item, error) {
-	if err := validatePrimaryKeys("item", id, userID); err != nil {
+func (*ItemDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.Item, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Item{}, err
+	}
+
+	if err := validatePrimaryKeys("item", id); err != nil {
 		return model.Item{}, err
 	}
 
 	r := Item{}
-	if err := tx.DB().(*gorm.DB).Where(&Item{ID: id, UserID: userID}).First(&r).Error; err != nil {
-		return model.Item{}, convertError(err, id, userID, "find item")
+	if err := db.Where(&Item{ID: id}).First(&r).Error; err != nil {
+		return model.Item{}, convertError(ctx, err, id, "(No-ID)", "find item")
 	}
-	return r.convertTo(), nil
+
+	items, err := loadItemTags(db, Items{r})
+	if err != nil {
+		return model.Item{}, convertError(ctx, err, id, "(No-ID)", "find tags of item")
+	}
+	return items[0], nil
 }
 
-// Find gets Items.
-func (*ItemDBManager) Find(tx usecase.Transaction, conditions map[string]interface{}) (model.Items, error) {
+// Find gets Items matched with a filter.
+func (*ItemDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.ItemFilter) (model.Items, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Items{}, err
+	}
+
+	q := whereIn(db, "id", filter.IDs)
+	q = whereIn(q, "list_id", filter.ListIDs)
+	if filter.TagIDs != nil {
+		q = q.Where("id IN (?)", whereIn(db.Table("item_tags").Select("item_id"), "tag_id", filter.TagIDs).SubQuery())
+	}
+	q = whereContains(q, "title", filter.TitleContains)
+	if filter.Completed != nil {
+		q = q.Where("completed = ?", *filter.Completed)
+	}
+	if !filter.DueUntil.IsZero() {
+		q = q.Where("due_date IS NOT NULL AND due_date <= ?", convertTime(filter.DueUntil))
+	}
+	q, err = orderBy(q, "items", filter.Sort, map[usecase.SortKey]string{
+		usecase.SortByRank:      "rank",
+		usecase.SortByTitle:     "title",
+		usecase.SortByDueDate:   "due_date",
+		usecase.SortByCreatedAt: "created_at",
+	})
+	if err != nil {
+		return model.Items{}, err
+	}
+
 	r := Items{}
-	if err := tx.DB().(*gorm.DB).Where(queryForItem(conditions)).Find(&r).Error; err != nil {
-		userID := "(No-ID)"
-		if v, ok := conditions["user_id"]; ok {
-			userID = v.(string)
-		}
-		id := "(No-ID)"
-		if v, ok := conditions["id"]; ok {
-			id = v.(string)
-		}
-		return model.Items{}, model.ServerError{
-			UserID: userID,
-			Err:    err,
-			ID:     id,
-			Act:    "find items",
-		}
+	if err := paginate(q, filter.Page).Find(&r).Error; err != nil {
+		return model.Items{}, convertError(ctx, err, idForError(filter.IDs), "(No-ID)", "find items")
 	}
 
-	items := model.Items{}
-	for _, ri := range r {
-		items = append(items, ri.convertTo())
+	items, err := loadItemTags(db, r)
+	if err != nil {
+		return model.Items{}, convertError(ctx, err, idForError(filter.IDs), "(No-ID)", "find tags of items")
 	}
 
 	return items, nil
@@ -216,33 +237,35 @@ func queryForItem(data map[string]interface{}) map[string]interface{} {
 		query["title"] = v
 	}
 	if v, ok := data["Text"]; ok {
-		if v.(string) == "" {
+		if v == "" {
 			query["text"] = nil
 		} else {
 			query["text"] = v
 		}
 	}
-	if v, ok := data["Tags"]; ok {
-		tags := v.([]string)
-		if len(tags) == 0 {
-			query["tags"] = nil
-		} else {
-			query["tags"] = strings.Join(tags, ",")
-		}
+	if v, ok := data["StartDate"]; ok {
+		query["start_date"] = nullableTime(v)
 	}
-	if v, ok := data["Before"]; ok {
-		if v.(string) == "" {
-			query["before"] = nil
-		} else {
-			query["before"] = v
-		}
+	if v, ok := data["DueDate"]; ok {
+		query["due_date"] = nullableTime(v)
 	}
-	if v, ok := data["After"]; ok {
-		if v.(string) == "" {
-			query["after"] = nil
-		} else {
-			query["after"] = v
-		}
+	if v, ok := data["Completed"]; ok {
+		query["completed"] = v
+	}
+	if v, ok := data["Rank"]; ok {
+		query["rank"] = v
 	}
 	return query
 }
+
+// nullableTime converts a time for DB. A zero time is stored as NULL.
+func nullableTime(v interface{}) interface{} {
+	t, ok := v.(time.Time)
+	if !ok {
+		return v
+	}
+	if t.IsZero() {
+		return nil
+	}
+	return convertTime(t)
+}
@@ -0,0 +1,141 @@
+package rdb
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/x-color/vue-trello/model"
+)
+
+// ItemTag is a relation between an Item and a Tag attached to it.
+// Position keeps order of Tags in an Item.
+type ItemTag struct {
+	ItemID   string
+	TagID    string
+	Position int
+}
+
+// createItemTagTable creates a join table of Items and Tags.
+// It is created by SQL because gorm can not add foreign keys to a table of SQLite.
+// The SQL is common to SQLite and PostgreSQL.
+func createItemTagTable(tx *gorm.DB) error {
+	err := tx.Exec(`CREATE TABLE IF NOT EXISTS item_tags (
+		item_id text NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+		tag_id text NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+		position integer NOT NULL DEFAULT 0,
+		PRIMARY KEY (item_id, tag_id)
+	)`).Error
+	if err != nil {
+		return err
+	}
+	return tx.Exec("CREATE INDEX IF NOT EXISTS idx_item_tags_tag_id ON item_tags(tag_id)").Error
+}
+
+// migrateItemTags moves Tags of old versions saved as a comma-joined column of items into item_tags.
+// Unknown Tags are dropped. The old column is cleared, so Tags are migrated only once.
+func migrateItemTags(tx *gorm.DB) error {
+	if !tx.Dialect().HasColumn("items", "tags") {
+		return nil
+	}
+
+	rows, err := tx.Raw("SELECT id, tags FROM items WHERE tags IS NOT NULL AND tags != ''").Rows()
+	if err != nil {
+		return err
+	}
+
+	tags := map[string][]string{}
+	for rows.Next() {
+		var id string
+		var ts sql.NullString
+		if err := rows.Scan(&id, &ts); err != nil {
+			rows.Close()
+			return err
+		}
+		tags[id] = strings.Split(ts.String, ",")
+	}
+	rows.Close()
+
+	for id, tagIDs := range tags {
+		for i, tagID := range tagIDs {
+			err := tx.Exec(`INSERT INTO item_tags (item_id, tag_id, position)
+				SELECT ?, ?, ? WHERE EXISTS (SELECT 1 FROM tags WHERE id = ?)
+				ON CONFLICT DO NOTHING`, id, tagID, i, tagID).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Exec("UPDATE items SET tags = NULL").Error
+}
+
+// setItemTags replaces Tags attached to an Item with Tags had tagIDs.
+func setItemTags(db *gorm.DB, itemID string, tagIDs []string) error {
+	if err := db.Where("item_id = ?", itemID).Delete(&ItemTag{}).Error; err != nil {
+		return err
+	}
+	for i, tagID := range tagIDs {
+		it := ItemTag{
+			ItemID:   itemID,
+			TagID:    tagID,
+			Position: i,
+		}
+		if err := db.Create(&it).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadItemTags converts Items for DB to Items with Tags attached to them.
+// Tags include all fields and deleted Tags are excluded.
+func loadItemTags(db *gorm.DB, r Items) (model.Items, error) {
+	items := model.Items{}
+	if len(r) == 0 {
+		return items, nil
+	}
+
+	ids := []string{}
+	for _, ri := range r {
+		ids = append(ids, ri.ID)
+	}
+
+	rows, err := db.Raw(`SELECT item_tags.item_id, tags.id, tags.user_id, tags.board_id, tags.name, tags.color
+		FROM item_tags JOIN tags ON tags.id = item_tags.tag_id
+		WHERE tags.deleted_at IS NULL AND item_tags.item_id IN (?)
+		ORDER BY item_tags.position`, ids).Rows()
+	if err != nil {
+		return model.Items{}, err
+	}
+	defer rows.Close()
+
+	tags := map[string]model.Tags{}
+	for rows.Next() {
+		var itemID string
+		t := Tag{}
+		if err := rows.Scan(&itemID, &t.ID, &t.UserID, &t.BoardID, &t.Name, &t.Color); err != nil {
+			return model.Items{}, err
+		}
+		tags[itemID] = append(tags[itemID], t.convertTo())
+	}
+	if err := rows.Err(); err != nil {
+		return model.Items{}, err
+	}
+
+	for _, ri := range r {
+		item := ri.convertTo()
+		if ts, ok := tags[ri.ID]; ok {
+			item.Tags = ts
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func tagIDs(tags model.Tags) []string {
+	ids := []string{}
+	for _, t := range tags {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
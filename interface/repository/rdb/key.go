@@ -0,0 +1,77 @@
+package rdb
+
+import (
+	"database/sql"
+
+	"github.com/jinzhu/gorm"
+)
+
+// uniqueIDs makes IDs in a table of old versions unique. Old versions used a pair of ID and user ID
+// as a primary key, so foreign keys can not refer to ID. If rows share an ID, a row which is not
+// deleted and is updated last is kept. Tables having ID as a primary key are not changed.
+func uniqueIDs(tx *gorm.DB, table string) error {
+	size, err := primaryKeySize(tx, table)
+	if err != nil || size <= 1 {
+		return err
+	}
+
+	rows, err := tx.Raw(`SELECT id, user_id, deleted_at IS NULL, updated_at FROM ` + table +
+		` WHERE id IN (SELECT id FROM ` + table + ` GROUP BY id HAVING COUNT(*) > 1)`).Rows()
+	if err != nil {
+		return err
+	}
+
+	type row struct {
+		userID    string
+		alive     bool
+		updatedAt sql.NullTime
+	}
+	kept := map[string]row{}
+	dropped := map[string][]string{}
+	for rows.Next() {
+		var id string
+		var r row
+		if err := rows.Scan(&id, &r.userID, &r.alive, &r.updatedAt); err != nil {
+			rows.Close()
+			return err
+		}
+		k, ok := kept[id]
+		switch {
+		case !ok:
+			kept[id] = r
+			continue
+		case r.alive != k.alive && r.alive,
+			r.alive == k.alive && r.updatedAt.Time.After(k.updatedAt.Time):
+			kept[id] = r
+			r = k
+		}
+		dropped[id] = append(dropped[id], r.userID)
+	}
+	rows.Close()
+
+	for id, userIDs := range dropped {
+		if err := tx.Exec("DELETE FROM "+table+" WHERE id = ? AND user_id IN (?)", id, userIDs).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS uix_" + table + "_id ON " + table + " (id)").Error
+}
+
+// primaryKeySize returns the number of columns in a primary key of a table.
+func primaryKeySize(tx *gorm.DB, table string) (int, error) {
+	var query string
+	if tx.Dialect().GetName() == "postgres" {
+		query = `SELECT COUNT(*) FROM information_schema.table_constraints c
+			JOIN information_schema.key_column_usage k
+			ON k.constraint_name = c.constraint_name AND k.table_schema = c.table_schema AND k.table_name = c.table_name
+			WHERE c.constraint_type = 'PRIMARY KEY' AND c.table_schema = current_schema() AND c.table_name = ?`
+	} else {
+		query = "SELECT COUNT(*) FROM pragma_table_info(?) WHERE pk > 0"
+	}
+
+	var size int
+	if err := tx.Raw(query, table).Row().Scan(&size); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
@@ -1,9 +1,9 @@
 package rdb
 
 import (
+	"context"
 	"time"
 
-	"github.com/jinzhu/gorm"
 	"github.com/x-color/vue-trello/model"
 	"github.com/x-color/vue-trello/usecase"
 )
@@ -11,11 +11,11 @@ import (
 // List is List data model for DB.
 type List struct {
 	ID        string `gorm:"primary_key"`
-	UserID    string `gorm:"primary_key"`
+	UserID    string
 	BoardID   string
 	Title     string
-	Before    *string
-	After     *string
+	Rank      string
+	Version   int
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
@@ -23,43 +23,23 @@ type List struct {
 
 func (l *List) convertFrom(list model.List) {
 	l.ID = list.ID
+	l.Rank = list.Rank
+	l.Version = list.Version
 	l.UserID = list.UserID
 	l.BoardID = list.BoardID
 	l.Title = list.Title
-
-	if list.Before == "" {
-		l.Before = nil
-	} else {
-		l.Before = &list.Before
-	}
-
-	if list.After == "" {
-		l.After = nil
-	} else {
-		l.After = &list.After
-	}
 }
 
 func (l *List) convertTo() model.List {
 	list := model.List{
 		ID:      l.ID,
+		Rank:    l.Rank,
+		Version: l.Version,
 		UserID:  l.UserID,
 		BoardID: l.BoardID,
 		Title:   l.Title,
 	}
 
-	if l.After == nil {
-		list.After = ""
-	} else {
-		list.After = *l.After
-	}
-
-	if l.Before == nil {
-		list.Before = ""
-	} else {
-		list.Before = *l.Before
-	}
-
 	return list
 }
 
@@ -69,95 +49,103 @@ type Lists []List
 // ListDBManager is DB manager for List.
 type ListDBManager struct{}
 
-func newListDBManager(db *gorm.DB) ListDBManager {
-	db.AutoMigrate(&List{})
-	return ListDBManager{}
-}
-
 // Create registers a List to DB.
-func (*ListDBManager) Create(tx usecase.Transaction, list model.List) error {
-	if err := validatePrimaryKeys("list", list.ID, list.UserID); err != nil {
+func (*ListDBManager) Create(ctx context.Context, tx usecase.Transaction, list model.List) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("list", list.ID); err != nil {
 		return err
 	}
 
 	l := List{}
 	l.convertFrom(list)
 
-	if err := tx.DB().(*gorm.DB).Create(&l).Error; err != nil {
+	if err := db.Create(&l).Error; err != nil {
 		tx.Rollback()
-		return model.ServerError{
-			UserID: l.UserID,
-			Err:    err,
-			ID:     l.ID,
-			Act:    "create list",
-		}
+		return convertError(ctx, err, l.ID, l.UserID, "create list")
 	}
 
 	return nil
 }
 
-// Update updates all fields of specific List in DB.
-func (*ListDBManager) Update(tx usecase.Transaction, list model.List, updates map[string]interface{}) error {
-	if err := validatePrimaryKeys("list", list.ID, list.UserID); err != nil {
+// Update updates all fields of specific List in DB and bumps its version.
+// If list.Version is not zero, the List is updated only if it still has the version.
+func (*ListDBManager) Update(ctx context.Context, tx usecase.Transaction, list model.List, updates map[string]interface{}) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("list", list.ID); err != nil {
 		return err
 	}
 
 	l := List{}
 	l.convertFrom(list)
-	err := tx.DB().(*gorm.DB).Model(&l).Updates(queryForList(updates)).Error
-	if err != nil {
-		return convertError(err, l.ID, l.UserID, "update list")
-	}
-	return nil
+	return updateVersioned(ctx, db, &l, list.Version, queryForList(updates), l.ID, l.UserID, "update list")
 }
 
 // Delete removes a List from DB.
-func (*ListDBManager) Delete(tx usecase.Transaction, list model.List) error {
-	if err := validatePrimaryKeys("list", list.ID, list.UserID); err != nil {
+// If list.Version is not zero, the List is removed only if it still has the version.
+func (*ListDBManager) Delete(ctx context.Context, tx usecase.Transaction, list model.List) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("list", list.ID); err != nil {
 		return err
 	}
 
 	l := List{}
 	l.convertFrom(list)
 
-	if err := tx.DB().(*gorm.DB).Delete(&l).Error; err != nil {
-		return convertError(err, l.ID, l.UserID, "delete list")
-	}
-
-	return nil
+	return deleteVersioned(ctx, db, &l, list.Version, l.ID, l.UserID, "delete list")
 }
 
 // FindByID gets a List had specific ID from DB.
-func (*ListDBManager) FindByID(tx usecase.Transaction, id, userID string) (model.List, error) {
-	if err := validatePrimaryKeys("list", id, userID); err != nil {
+func (*ListDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.List, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.List{}, err
+	}
+
+	if err := validatePrimaryKeys("list", id); err != nil {
 		return model.List{}, err
 	}
 
 	r := List{}
-	if err := tx.DB().(*gorm.DB).Where(&List{ID: id, UserID: userID}).First(&r).Error; err != nil {
-		return model.List{}, convertError(err, id, userID, "find list")
+	if err := db.Where(&List{ID: id}).First(&r).Error; err != nil {
+		return model.List{}, convertError(ctx, err, id, "(No-ID)", "find list")
 	}
 	return r.convertTo(), nil
 }
 
-// Find gets Lists.
-func (*ListDBManager) Find(tx usecase.Transaction, conditions map[string]interface{}) (model.Lists, error) {
+// Find gets Lists matched with a filter.
+func (*ListDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.ListFilter) (model.Lists, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Lists{}, err
+	}
+
+	db = whereIn(db, "id", filter.IDs)
+	db = whereIn(db, "board_id", filter.BoardIDs)
+	db = whereContains(db, "title", filter.TitleContains)
+	db, err = orderBy(db, "lists", filter.Sort, map[usecase.SortKey]string{
+		usecase.SortByRank:      "rank",
+		usecase.SortByTitle:     "title",
+		usecase.SortByCreatedAt: "created_at",
+	})
+	if err != nil {
+		return model.Lists{}, err
+	}
+
 	r := Lists{}
-	if err := tx.DB().(*gorm.DB).Where(queryForList(conditions)).Find(&r).Error; err != nil {
-		userID := "(No-ID)"
-		if v, ok := conditions["user_id"]; ok {
-			userID = v.(string)
-		}
-		id := "(No-ID)"
-		if v, ok := conditions["id"]; ok {
-			id = v.(string)
-		}
-		return model.Lists{}, model.ServerError{
-			UserID: userID,
-			Err:    err,
-			ID:     id,
-			Act:    "find lists",
-		}
+	if err := paginate(db, filter.Page).Find(&r).Error; err != nil {
+		return model.Lists{}, convertError(ctx, err, idForError(filter.IDs), "(No-ID)", "find lists")
 	}
 
 	lists := model.Lists{}
@@ -182,19 +170,8 @@ func queryForList(data map[string]interface{}) map[string]interface{} {
 	if v, ok := data["Title"]; ok {
 		query["title"] = v
 	}
-	if v, ok := data["Before"]; ok {
-		if v.(string) == "" {
-			query["before"] = nil
-		} else {
-			query["before"] = v
-		}
-	}
-	if v, ok := data["After"]; ok {
-		if v.(string) == "" {
-			query["after"] = nil
-		} else {
-			query["after"] = v
-		}
+	if v, ok := data["Rank"]; ok {
+		query["rank"] = v
 	}
 	return query
 }
@@ -0,0 +1,162 @@
+package rdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Member is Member data model for DB.
+// It does not have DeletedAt. A removed member is deleted from DB so that the user can be invited again.
+type Member struct {
+	BoardID   string `gorm:"primary_key"`
+	UserID    string `gorm:"primary_key"`
+	Role      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (m *Member) convertFrom(member model.Member) {
+	m.BoardID = member.BoardID
+	m.UserID = member.UserID
+	m.Role = string(member.Role)
+}
+
+func (m *Member) convertTo() model.Member {
+	member := model.Member{
+		BoardID: m.BoardID,
+		UserID:  m.UserID,
+		Role:    model.Role(m.Role),
+	}
+	return member
+}
+
+// Members is a slice of Member data model.
+type Members []Member
+
+// MemberDBManager is DB manager for Member.
+type MemberDBManager struct{}
+
+// Create registers a Member to DB.
+func (*MemberDBManager) Create(ctx context.Context, tx usecase.Transaction, member model.Member) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("member", member.BoardID, member.UserID); err != nil {
+		return err
+	}
+
+	m := Member{}
+	m.convertFrom(member)
+
+	if err := db.Create(&m).Error; err != nil {
+		return convertError(ctx, err, m.BoardID, m.UserID, "create member")
+	}
+	return nil
+}
+
+// Update updates all fields of specific Member in DB.
+func (*MemberDBManager) Update(ctx context.Context, tx usecase.Transaction, member model.Member, updates map[string]interface{}) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("member", member.BoardID, member.UserID); err != nil {
+		return err
+	}
+
+	m := Member{}
+	m.convertFrom(member)
+	err = db.Model(&m).Updates(queryForMember(updates)).Error
+	if err != nil {
+		return convertError(ctx, err, m.BoardID, m.UserID, "update member")
+	}
+	return nil
+}
+
+// Delete removes a Member from DB.
+func (*MemberDBManager) Delete(ctx context.Context, tx usecase.Transaction, member model.Member) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("member", member.BoardID, member.UserID); err != nil {
+		return err
+	}
+
+	m := Member{}
+	m.convertFrom(member)
+
+	if err := db.Delete(&m).Error; err != nil {
+		return convertError(ctx, err, m.BoardID, m.UserID, "delete member")
+	}
+	return nil
+}
+
+// FindByID gets a Member of specific Board from DB.
+func (*MemberDBManager) FindByID(ctx context.Context, tx usecase.Transaction, boardID, userID string) (model.Member, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Member{}, err
+	}
+
+	if err := validatePrimaryKeys("member", boardID, userID); err != nil {
+		return model.Member{}, err
+	}
+
+	r := Member{}
+	if err := db.Where(&Member{BoardID: boardID, UserID: userID}).First(&r).Error; err != nil {
+		return model.Member{}, convertError(ctx, err, boardID, userID, "find member")
+	}
+	return r.convertTo(), nil
+}
+
+// Find gets Members matched with a filter.
+func (*MemberDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.MemberFilter) (model.Members, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Members{}, err
+	}
+
+	db = whereIn(db, "board_id", filter.BoardIDs)
+	db = whereIn(db, "user_id", filter.UserIDs)
+	if filter.Roles != nil {
+		roles := []string{}
+		for _, role := range filter.Roles {
+			roles = append(roles, string(role))
+		}
+		db = whereIn(db, "role", roles)
+	}
+
+	r := Members{}
+	if err := paginate(db.Order("created_at").Order("user_id"), filter.Page).Find(&r).Error; err != nil {
+		return model.Members{}, convertError(ctx, err, idForError(filter.BoardIDs), idForError(filter.UserIDs), "find members")
+	}
+
+	members := model.Members{}
+	for _, rm := range r {
+		members = append(members, rm.convertTo())
+	}
+
+	return members, nil
+}
+
+func queryForMember(data map[string]interface{}) map[string]interface{} {
+	query := make(map[string]interface{})
+	if v, ok := data["BoardID"]; ok {
+		query["board_id"] = v
+	}
+	if v, ok := data["UserID"]; ok {
+		query["user_id"] = v
+	}
+	if v, ok := data["Role"]; ok {
+		query["role"] = v
+	}
+	return query
+}
@@ -0,0 +1,202 @@
+package rdb
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// migration changes a schema of DB from version-1 to version (up) and back (down).
+// Migrations are written in migrations.go and must not be changed after they are released.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *gorm.DB) error
+	down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration is a record of an applied migration.
+type SchemaMigration struct {
+	Version   int `gorm:"primary_key;auto_increment:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+// MigrationStatus is a state of a migration. AppliedAt is zero if the migration is not applied.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time
+}
+
+// Migrator applies versioned migrations to DB. A version of DB is the latest applied migration.
+type Migrator struct {
+	db *gorm.DB
+}
+
+// NewMigrator connects to DB selected by environment variables and returns a Migrator for it.
+func NewMigrator() (Migrator, error) {
+	db, err := openDB(os.Getenv("DB_DRIVER"))
+	if err != nil {
+		return Migrator{}, err
+	}
+	return newMigrator(db)
+}
+
+func newMigrator(db *gorm.DB) (Migrator, error) {
+	if err := db.AutoMigrate(&SchemaMigration{}).Error; err != nil {
+		return Migrator{}, fmt.Errorf("failed to create schema table: %w", err)
+	}
+	return Migrator{db: db}, nil
+}
+
+// LatestVersion returns a version of the latest migration known by this server.
+func LatestVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// Version returns a current version of DB. It is 0 if no migration is applied.
+func (m *Migrator) Version() (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+	if len(applied) == 0 {
+		return 0, nil
+	}
+	return applied[len(applied)-1].Version, nil
+}
+
+// Status returns states of all migrations in order of version.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	if err := m.checkVersion(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	appliedAt := map[int]time.Time{}
+	for _, a := range applied {
+		appliedAt[a.Version] = a.AppliedAt
+	}
+
+	statuses := []MigrationStatus{}
+	for _, mg := range migrations {
+		statuses = append(statuses, MigrationStatus{
+			Version:   mg.version,
+			Name:      mg.name,
+			AppliedAt: appliedAt[mg.version],
+		})
+	}
+	return statuses, nil
+}
+
+// Up applies all pending migrations in order and returns applied ones.
+// Each migration is applied in a transaction. It stops at the first failed migration.
+func (m *Migrator) Up() ([]MigrationStatus, error) {
+	version, err := m.Version()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkVersion(); err != nil {
+		return nil, err
+	}
+
+	done := []MigrationStatus{}
+	for _, mg := range migrations {
+		if mg.version <= version {
+			continue
+		}
+
+		record := SchemaMigration{
+			Version:   mg.version,
+			Name:      mg.name,
+			AppliedAt: time.Now().UTC(),
+		}
+		err := m.run(mg, mg.up, func(tx *gorm.DB) error {
+			return tx.Create(&record).Error
+		})
+		if err != nil {
+			return done, err
+		}
+		done = append(done, MigrationStatus(record))
+	}
+	return done, nil
+}
+
+// Down reverts the latest applied migrations by steps in reverse order and returns reverted ones.
+func (m *Migrator) Down(steps int) ([]MigrationStatus, error) {
+	if err := m.checkVersion(); err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	done := []MigrationStatus{}
+	for j := len(applied) - 1; j >= 0 && len(done) < steps; j-- {
+		record := applied[j]
+		mg, ok := findMigration(record.Version)
+		if !ok {
+			return done, fmt.Errorf("unknown migration version %d", record.Version)
+		}
+
+		err := m.run(mg, mg.down, func(tx *gorm.DB) error {
+			return tx.Delete(&record).Error
+		})
+		if err != nil {
+			return done, err
+		}
+		done = append(done, MigrationStatus(record))
+	}
+	return done, nil
+}
+
+// checkVersion returns an error if DB has a newer schema than this server knows.
+func (m *Migrator) checkVersion() error {
+	version, err := m.Version()
+	if err != nil {
+		return err
+	}
+	if version > LatestVersion() {
+		return fmt.Errorf("schema version %d of DB is newer than version %d supported by this server", version, LatestVersion())
+	}
+	return nil
+}
+
+func (m *Migrator) applied() ([]SchemaMigration, error) {
+	applied := []SchemaMigration{}
+	if err := m.db.Order("version").Find(&applied).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema table: %w", err)
+	}
+	return applied, nil
+}
+
+// run changes a schema and records it in a transaction.
+func (m *Migrator) run(mg migration, change, record func(tx *gorm.DB) error) error {
+	tx := m.db.Begin()
+	if err := change(tx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to migrate version %d (%s): %w", mg.version, mg.name, err)
+	}
+	if err := record(tx); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to record version %d (%s): %w", mg.version, mg.name, err)
+	}
+	return tx.Commit().Error
+}
+
+func findMigration(version int) (migration, bool) {
+	for _, mg := range migrations {
+		if mg.version == version {
+			return mg, true
+		}
+	}
+	return migration{}, false
+}
@@ -0,0 +1,184 @@
+package rdb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Tables of the first version, which had no versioned migrations. Boards, Lists and Items had
+// a pair of ID and user ID as a primary key, were ordered by Before and After, and Items kept
+// IDs of Tags joined by commas.
+
+type boardV0 struct {
+	ID        string `gorm:"primary_key"`
+	UserID    string `gorm:"primary_key"`
+	Title     string
+	Text      *string
+	Color     string
+	Before    *string
+	After     *string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (boardV0) TableName() string { return "boards" }
+
+type listV0 struct {
+	ID        string `gorm:"primary_key"`
+	UserID    string `gorm:"primary_key"`
+	BoardID   string
+	Title     string
+	Before    *string
+	After     *string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (listV0) TableName() string { return "lists" }
+
+type itemV0 struct {
+	ID        string `gorm:"primary_key"`
+	UserID    string `gorm:"primary_key"`
+	ListID    string
+	Title     string
+	Text      *string
+	Tags      *string
+	Before    *string
+	After     *string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (itemV0) TableName() string { return "items" }
+
+type tagV0 struct {
+	ID        string `gorm:"primary_key"`
+	Name      string
+	Color     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (tagV0) TableName() string { return "tags" }
+
+func TestMigrateFirstVersion(t *testing.T) {
+	db, cleanup := openTestDB(t)
+	defer cleanup()
+
+	if err := db.AutoMigrate(&userV1{}, &boardV0{}, &listV0{}, &itemV0{}, &tagV0{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	str := func(s string) *string { return &s }
+	deleted := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, r := range []interface{}{
+		&userV1{ID: "u1", Name: "alice", Password: "pass"},
+		&tagV0{ID: "0", Name: "t1", Color: "red"},
+		&tagV0{ID: "1", Name: "t2", Color: "yellow"},
+		&tagV0{ID: "2", Name: "t3", Color: "green"},
+		&boardV0{ID: "b1", UserID: "u1", Title: "board", Color: "red"},
+		// Lists are in order of l2 and l1
+		&listV0{ID: "l1", UserID: "u1", BoardID: "b1", Title: "second", Before: str("l2")},
+		&listV0{ID: "l2", UserID: "u1", BoardID: "b1", Title: "first", After: str("l1")},
+		// Items are in order of i3, i1 and i2. Tag '9' does not exist
+		&itemV0{ID: "i1", UserID: "u1", ListID: "l2", Title: "i1", Tags: str("2,9,0"), Before: str("i3"), After: str("i2")},
+		&itemV0{ID: "i2", UserID: "u1", ListID: "l2", Title: "i2", Before: str("i1")},
+		&itemV0{ID: "i3", UserID: "u1", ListID: "l2", Title: "i3", Tags: str("1"), After: str("i1")},
+		// A deleted row shares an ID with an Item
+		&itemV0{ID: "i2", UserID: "u2", ListID: "l2", Title: "deleted", DeletedAt: &deleted},
+	} {
+		if err := db.Create(r).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, err := newMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if version, err := m.Version(); err != nil || version != LatestVersion() {
+		t.Fatalf("Version() = %d, %v, want %d", version, err, LatestVersion())
+	}
+
+	ctx := context.Background()
+	tm := newTransactionManager(db)
+	tx := tm.BeginTransaction(ctx, false)
+
+	lists, err := (&ListDBManager{}).Find(ctx, tx, usecase.ListFilter{Sort: usecase.Sort{Key: usecase.SortByRank}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := listIDs(lists); !reflect.DeepEqual(got, []string{"l2", "l1"}) {
+		t.Errorf("lists = %v, want [l2 l1]", got)
+	}
+
+	items, err := (&ItemDBManager{}).Find(ctx, tx, usecase.ItemFilter{Sort: usecase.Sort{Key: usecase.SortByRank}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	tags := map[string][]string{}
+	for _, it := range items {
+		got = append(got, it.Title)
+		for _, tag := range it.Tags {
+			tags[it.ID] = append(tags[it.ID], tag.ID)
+		}
+	}
+	if !reflect.DeepEqual(got, []string{"i3", "i1", "i2"}) {
+		t.Errorf("items = %v, want [i3 i1 i2]", got)
+	}
+	wantTags := map[string][]string{"i1": {"2", "0"}, "i3": {"1"}}
+	if !reflect.DeepEqual(tags, wantTags) {
+		t.Errorf("tags of items = %v, want %v", tags, wantTags)
+	}
+
+	board, err := (&BoardDBManager{}).FindByID(ctx, tx, "b1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if board.Version != 1 || board.Rank == "" {
+		t.Errorf("board = %+v, want version 1 and a rank", board)
+	}
+
+	owner, err := (&MemberDBManager{}).FindByID(ctx, tx, "b1", "u1")
+	if err != nil || owner.Role != model.OWNER {
+		t.Errorf("member of board = %+v, %v, want owner", owner, err)
+	}
+
+	defaults, err := (&TagDBManager{}).Find(ctx, tx, usecase.TagFilter{UserIDs: []string{""}, BoardIDs: []string{""}})
+	if err != nil || len(defaults) != 3 {
+		t.Errorf("default tags = %v, %v, want 3 tags", defaults, err)
+	}
+
+	// Converted Items can be changed by this version
+	item := items[0]
+	if err := (&ItemDBManager{}).Update(ctx, tx, item, map[string]interface{}{"Tags": []string{"0"}}); err != nil {
+		t.Errorf("Update() error = %v", err)
+	}
+	item, err = (&ItemDBManager{}).FindByID(ctx, tx, item.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&ItemDBManager{}).Delete(ctx, tx, item); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+}
+
+func listIDs(lists model.Lists) []string {
+	ids := []string{}
+	for _, l := range lists {
+		ids = append(ids, l.ID)
+	}
+	return ids
+}
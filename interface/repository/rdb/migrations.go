@@ -0,0 +1,399 @@
+package rdb
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/x-color/vue-trello/model"
+)
+
+// migrations is a history of schema in order of version.
+// Add a new migration to change schema. Tables are defined by structs of the version,
+// because data models for DB change in later versions.
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "initial_schema",
+		up:      upInitialSchema,
+		down:    downInitialSchema,
+	},
+	{
+		version: 2,
+		name:    "add_versions",
+		up:      upVersions,
+		down:    downVersions,
+	},
+	{
+		version: 3,
+		name:    "add_webhooks",
+		up:      upWebhooks,
+		down:    downWebhooks,
+	},
+	{
+		version: 4,
+		name:    "add_access_tokens",
+		up:      upAccessTokens,
+		down:    downAccessTokens,
+	},
+	{
+		version: 5,
+		name:    "add_sessions",
+		up:      upSessions,
+		down:    downSessions,
+	},
+}
+
+// Tables of version 1.
+
+type userV1 struct {
+	ID        string `gorm:"primary_key"`
+	Name      string
+	Password  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (userV1) TableName() string { return "users" }
+
+type boardV1 struct {
+	ID        string `gorm:"primary_key"`
+	UserID    string
+	Title     string
+	Text      *string
+	Color     string
+	Rank      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (boardV1) TableName() string { return "boards" }
+
+type listV1 struct {
+	ID        string `gorm:"primary_key"`
+	UserID    string
+	BoardID   string
+	Title     string
+	Rank      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (listV1) TableName() string { return "lists" }
+
+type itemV1 struct {
+	ID        string `gorm:"primary_key"`
+	UserID    string
+	ListID    string
+	Title     string
+	Text      *string
+	StartDate *time.Time
+	DueDate   *time.Time
+	Completed bool
+	Rank      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (itemV1) TableName() string { return "items" }
+
+type tagV1 struct {
+	ID        string `gorm:"primary_key"`
+	UserID    string `gorm:"index;not null;default:''"`
+	BoardID   string `gorm:"index;not null;default:''"`
+	Name      string
+	Color     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (tagV1) TableName() string { return "tags" }
+
+type memberV1 struct {
+	BoardID   string `gorm:"primary_key"`
+	UserID    string `gorm:"primary_key"`
+	Role      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (memberV1) TableName() string { return "members" }
+
+type checklistV1 struct {
+	ID        string `gorm:"primary_key"`
+	ItemID    string
+	UserID    string
+	Title     string
+	Rank      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (checklistV1) TableName() string { return "checklists" }
+
+type checkItemV1 struct {
+	ID          string `gorm:"primary_key"`
+	ChecklistID string
+	ItemID      string
+	UserID      string
+	Title       string
+	Checked     bool
+	Rank        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time
+}
+
+func (checkItemV1) TableName() string { return "check_items" }
+
+type commentV1 struct {
+	ID        string `gorm:"primary_key"`
+	ItemID    string
+	UserID    string
+	Text      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (commentV1) TableName() string { return "comments" }
+
+type activityV1 struct {
+	Seq       uint64 `gorm:"primary_key;AUTO_INCREMENT"`
+	ID        string `gorm:"unique_index"`
+	BoardID   string `gorm:"index"`
+	UserID    string
+	Action    string
+	Target    string
+	TargetID  string
+	Before    string
+	After     string
+	CreatedAt time.Time
+}
+
+func (activityV1) TableName() string { return "activities" }
+
+// upInitialSchema creates tables. DB created before versioned migrations also has version 0,
+// so its tables are completed and its data of old formats is converted.
+// Tables of old versions are converted first, so new tables and constraints can refer to them.
+func upInitialSchema(tx *gorm.DB) error {
+	if err := convertOldTables(tx); err != nil {
+		return err
+	}
+
+	err := tx.AutoMigrate(
+		&memberV1{},
+		&checklistV1{},
+		&checkItemV1{},
+		&commentV1{},
+		&activityV1{},
+	).Error
+	if err != nil {
+		return err
+	}
+
+	ranked := []struct {
+		table  string
+		parent string
+	}{
+		{"checklists", "item_id"},
+		{"check_items", "checklist_id"},
+	}
+	for _, r := range ranked {
+		if err := migrateRanks(tx, r.table, r.parent); err != nil {
+			return err
+		}
+	}
+
+	// Boards created before members were introduced have no owner. Register their creators as owners.
+	err = tx.Exec(`INSERT INTO members (board_id, user_id, role, created_at, updated_at)
+		SELECT id, user_id, ?, created_at, updated_at FROM boards
+		WHERE deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM members WHERE members.board_id = boards.id)`,
+		string(model.OWNER),
+	).Error
+	if err != nil {
+		return err
+	}
+
+	if err := createItemTagTable(tx); err != nil {
+		return err
+	}
+	return migrateItemTags(tx)
+}
+
+// convertOldTables creates or completes tables which the first version had, and converts their data.
+// Their rows are ranked along Before and After columns, and their IDs are made unique.
+func convertOldTables(tx *gorm.DB) error {
+	err := tx.AutoMigrate(
+		&userV1{},
+		&boardV1{},
+		&listV1{},
+		&itemV1{},
+		&tagV1{},
+	).Error
+	if err != nil {
+		return err
+	}
+
+	ranked := []struct {
+		table  string
+		parent string
+	}{
+		{"boards", "user_id"},
+		{"lists", "board_id"},
+		{"items", "list_id"},
+	}
+	for _, r := range ranked {
+		if err := migrateRanks(tx, r.table, r.parent); err != nil {
+			return err
+		}
+		if err := uniqueIDs(tx, r.table); err != nil {
+			return err
+		}
+	}
+
+	// Tags of old versions are default Tags. Their new columns are NULL.
+	if err := tx.Table("tags").Where("user_id IS NULL").UpdateColumn("user_id", "").Error; err != nil {
+		return err
+	}
+	return tx.Table("tags").Where("board_id IS NULL").UpdateColumn("board_id", "").Error
+}
+
+func downInitialSchema(tx *gorm.DB) error {
+	return tx.DropTableIfExists(
+		"item_tags",
+		&activityV1{},
+		&commentV1{},
+		&checkItemV1{},
+		&checklistV1{},
+		&memberV1{},
+		&tagV1{},
+		&itemV1{},
+		&listV1{},
+		&boardV1{},
+		&userV1{},
+	).Error
+}
+
+// Tables of version 2. Versions of Boards, Lists and Items are added for optimistic concurrency control.
+
+type boardV2 struct {
+	boardV1
+	Version int `gorm:"not null;default:1"`
+}
+
+type listV2 struct {
+	listV1
+	Version int `gorm:"not null;default:1"`
+}
+
+type itemV2 struct {
+	itemV1
+	Version int `gorm:"not null;default:1"`
+}
+
+// upVersions adds version columns. Existing rows start at version 1.
+func upVersions(tx *gorm.DB) error {
+	return tx.AutoMigrate(&boardV2{}, &listV2{}, &itemV2{}).Error
+}
+
+// downVersions drops version columns. SQLite used by this server can not drop a column,
+// so the columns are left in SQLite. They are ignored by older servers and reused by upVersions.
+func downVersions(tx *gorm.DB) error {
+	if tx.Dialect().GetName() == "sqlite3" {
+		return nil
+	}
+	for _, v := range []interface{}{&boardV2{}, &listV2{}, &itemV2{}} {
+		if err := tx.Model(v).DropColumn("version").Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Tables of version 3. Webhooks and their deliveries are added.
+
+type webhookV3 struct {
+	ID        string `gorm:"primary_key"`
+	BoardID   string `gorm:"index"`
+	UserID    string
+	URL       string
+	Secret    string
+	Events    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (webhookV3) TableName() string { return "webhooks" }
+
+type deliveryV3 struct {
+	ID             string `gorm:"primary_key"`
+	WebhookID      string `gorm:"index"`
+	ActivityID     string
+	Event          string
+	Status         string `gorm:"index:idx_deliveries_status_next_attempt_at"`
+	Attempts       int
+	ResponseStatus int
+	Error          string
+	NextAttemptAt  time.Time `gorm:"index:idx_deliveries_status_next_attempt_at"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (deliveryV3) TableName() string { return "deliveries" }
+
+func upWebhooks(tx *gorm.DB) error {
+	return tx.AutoMigrate(&webhookV3{}, &deliveryV3{}).Error
+}
+
+func downWebhooks(tx *gorm.DB) error {
+	return tx.DropTableIfExists(&deliveryV3{}, &webhookV3{}).Error
+}
+
+// Tables of version 4. Personal access tokens are added.
+
+type accessTokenV4 struct {
+	ID        string `gorm:"primary_key"`
+	UserID    string `gorm:"index"`
+	Name      string
+	Hash      string `gorm:"unique_index"`
+	ReadOnly  bool
+	BoardID   string
+	ExpiresAt *time.Time
+	CreatedAt time.Time
+}
+
+func (accessTokenV4) TableName() string { return "access_tokens" }
+
+func upAccessTokens(tx *gorm.DB) error {
+	return tx.AutoMigrate(&accessTokenV4{}).Error
+}
+
+func downAccessTokens(tx *gorm.DB) error {
+	return tx.DropTableIfExists(&accessTokenV4{}).Error
+}
+
+// Tables of version 5. Sessions of signed in users are added.
+
+type sessionV5 struct {
+	ID        string `gorm:"primary_key"`
+	UserID    string `gorm:"index"`
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+func (sessionV5) TableName() string { return "sessions" }
+
+func upSessions(tx *gorm.DB) error {
+	return tx.AutoMigrate(&sessionV5{}).Error
+}
+
+func downSessions(tx *gorm.DB) error {
+	return tx.DropTableIfExists(&sessionV5{}).Error
+}
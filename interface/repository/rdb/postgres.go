@@ -0,0 +1,6 @@
+package rdb
+
+import (
+	// PostgreSQL driver
+	_ "github.com/jinzhu/gorm/dialects/postgres"
+)
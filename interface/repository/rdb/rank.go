@@ -0,0 +1,80 @@
+package rdb
+
+import (
+	"database/sql"
+	"sort"
+
+	"github.com/jinzhu/gorm"
+	"github.com/x-color/vue-trello/model"
+)
+
+// migrateRanks gives ranks to rows ordered by Before and After columns of old versions.
+// Rows are ranked along a chain from its head in each parent. Rows out of the chain
+// (e.g. a broken chain) follow them in order of ID, so no row is dropped.
+func migrateRanks(tx *gorm.DB, table, parentColumn string) error {
+	if !tx.Dialect().HasColumn(table, "before") || !tx.Dialect().HasColumn(table, "after") {
+		return nil
+	}
+
+	rows, err := tx.Raw(`SELECT id, ` + parentColumn + `, "before", "after" FROM ` + table +
+		` WHERE deleted_at IS NULL AND (rank IS NULL OR rank = '')`).Rows()
+	if err != nil {
+		return err
+	}
+
+	children := map[string][]string{}
+	before := map[string]string{}
+	after := map[string]string{}
+	for rows.Next() {
+		var id, parent string
+		var b, a sql.NullString
+		if err := rows.Scan(&id, &parent, &b, &a); err != nil {
+			rows.Close()
+			return err
+		}
+		children[parent] = append(children[parent], id)
+		before[id] = b.String
+		after[id] = a.String
+	}
+	rows.Close()
+
+	for _, ids := range children {
+		sorted := sortByChain(ids, before, after)
+		ranks := model.Ranks(len(sorted))
+		for i, id := range sorted {
+			if err := tx.Table(table).Where("id = ?", id).UpdateColumn("rank", ranks[i]).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sortByChain returns IDs ordered along a chain. IDs out of the chain follow them in order of ID.
+func sortByChain(ids []string, before, after map[string]string) []string {
+	sort.Strings(ids)
+
+	inParent := map[string]bool{}
+	for _, id := range ids {
+		inParent[id] = true
+	}
+
+	sorted := []string{}
+	visited := map[string]bool{}
+	for _, id := range ids {
+		if before[id] != "" {
+			continue
+		}
+		// Follow a chain from its head
+		for next := id; inParent[next] && !visited[next]; next = after[next] {
+			visited[next] = true
+			sorted = append(sorted, next)
+		}
+	}
+	for _, id := range ids {
+		if !visited[id] {
+			sorted = append(sorted, id)
+		}
+	}
+	return sorted
+}
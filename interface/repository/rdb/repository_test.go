@@ -0,0 +1,108 @@
+package rdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/x-color/vue-trello/interface/repository/repotest"
+)
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, newTestRepositories)
+}
+
+// newTestRepositories returns repositories on migrated DB returned by openTestDB.
+func newTestRepositories(t *testing.T) (repotest.Repositories, func()) {
+	db, cleanup := openTestDB(t)
+
+	m, err := newMigrator(db)
+	if err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+	if _, err := m.Up(); err != nil {
+		cleanup()
+		t.Fatal(err)
+	}
+
+	tm := newTransactionManager(db)
+	return repotest.Repositories{
+		Tx:       &tm,
+		Board:    &BoardDBManager{},
+		List:     &ListDBManager{},
+		Item:     &ItemDBManager{},
+		Tag:      &TagDBManager{},
+		User:     &UserDBManager{},
+		Member:   &MemberDBManager{},
+		Activity: &ActivityDBManager{},
+		Delivery: &DeliveryDBManager{},
+	}, cleanup
+}
+
+// openTestDB opens DB having no tables, which is selected by DB_DRIVER like the server.
+// SQLite DB is a temporary file. All tables in PostgreSQL DB at DB_URL are dropped,
+// so it must be a DB only for tests. Tests are skipped if DB_URL is not set.
+func openTestDB(t *testing.T) (*gorm.DB, func()) {
+	var db *gorm.DB
+	var cleanup func()
+	switch driver := os.Getenv("DB_DRIVER"); driver {
+	case "", "sqlite3":
+		dir, err := ioutil.TempDir("", "vue-trello")
+		if err != nil {
+			t.Fatal(err)
+		}
+		db, err = gorm.Open("sqlite3", dataSourceName(filepath.Join(dir, "test.db")))
+		if err != nil {
+			os.RemoveAll(dir)
+			t.Fatal(err)
+		}
+		cleanup = func() {
+			db.Close()
+			os.RemoveAll(dir)
+		}
+	case "postgres":
+		if os.Getenv("DB_URL") == "" {
+			t.Skip("DB_URL is not set")
+		}
+		var err error
+		db, err = openDB(driver)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cleanup = func() {
+			db.Close()
+		}
+
+		rows, err := db.Raw("SELECT tablename FROM pg_tables WHERE schemaname = current_schema()").Rows()
+		if err != nil {
+			cleanup()
+			t.Fatal(err)
+		}
+		tables := []string{}
+		for rows.Next() {
+			var table string
+			if err := rows.Scan(&table); err != nil {
+				rows.Close()
+				cleanup()
+				t.Fatal(err)
+			}
+			tables = append(tables, table)
+		}
+		rows.Close()
+		for _, table := range tables {
+			if err := db.Exec(`DROP TABLE IF EXISTS "` + table + `" CASCADE`).Error; err != nil {
+				cleanup()
+				t.Fatal(err)
+			}
+		}
+	default:
+		t.Fatalf("unknown database driver: %s", driver)
+	}
+
+	// Errors expected by tests are not logged
+	db.LogMode(false)
+	return db, cleanup
+}
@@ -0,0 +1,121 @@
+package rdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Session is Session data model for DB.
+type Session struct {
+	ID        string `gorm:"primary_key"`
+	UserID    string `gorm:"index"`
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+func (s *Session) convertFrom(session model.Session) {
+	s.ID = session.ID
+	s.UserID = session.UserID
+	s.ExpiresAt = session.ExpiresAt
+	s.CreatedAt = session.CreatedAt
+}
+
+func (s *Session) convertTo() model.Session {
+	session := model.Session{
+		ID:        s.ID,
+		UserID:    s.UserID,
+		ExpiresAt: s.ExpiresAt,
+		CreatedAt: s.CreatedAt,
+	}
+	return session
+}
+
+// Sessions is a slice of Session data model.
+type Sessions []Session
+
+// SessionDBManager is DB manager for Session.
+type SessionDBManager struct{}
+
+// Create registers a Session to DB.
+func (*SessionDBManager) Create(ctx context.Context, tx usecase.Transaction, session model.Session) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("session", session.ID); err != nil {
+		return err
+	}
+
+	s := Session{}
+	s.convertFrom(session)
+
+	if err := db.Create(&s).Error; err != nil {
+		return convertError(ctx, err, s.ID, s.UserID, "create session")
+	}
+	return nil
+}
+
+// Delete removes a Session from DB.
+func (*SessionDBManager) Delete(ctx context.Context, tx usecase.Transaction, session model.Session) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("session", session.ID); err != nil {
+		return err
+	}
+
+	s := Session{}
+	s.convertFrom(session)
+
+	if err := db.Delete(&s).Error; err != nil {
+		return convertError(ctx, err, s.ID, s.UserID, "delete session")
+	}
+	return nil
+}
+
+// FindByID gets a Session had specific ID from DB.
+func (*SessionDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.Session, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Session{}, err
+	}
+
+	if err := validatePrimaryKeys("session", id); err != nil {
+		return model.Session{}, err
+	}
+
+	r := Session{}
+	if err := db.Where(&Session{ID: id}).First(&r).Error; err != nil {
+		return model.Session{}, convertError(ctx, err, id, "(No-ID)", "find session")
+	}
+	return r.convertTo(), nil
+}
+
+// Find gets Sessions matched with a filter.
+func (*SessionDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.SessionFilter) (model.Sessions, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Sessions{}, err
+	}
+
+	db = whereIn(db, "id", filter.IDs)
+	db = whereIn(db, "user_id", filter.UserIDs)
+
+	r := Sessions{}
+	if err := paginate(db.Order("created_at").Order("id"), filter.Page).Find(&r).Error; err != nil {
+		return model.Sessions{}, convertError(ctx, err, idForError(filter.UserIDs), "(No-ID)", "find sessions")
+	}
+
+	sessions := model.Sessions{}
+	for _, rs := range r {
+		sessions = append(sessions, rs.convertTo())
+	}
+
+	return sessions, nil
+}
@@ -1,8 +1,12 @@
 package rdb
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/jinzhu/gorm"
 	"github.com/x-color/vue-trello/model"
@@ -13,32 +17,106 @@ import (
 
 // DBManager includes DB managers for all data model.
 type DBManager struct {
-	TransactionManager TransactionManager
-	ItemDBManager      ItemDBManager
-	ListDBManager      ListDBManager
-	BoardDBManager     BoardDBManager
-	UserDBManager      UserDBManager
-	TagDBManager       TagDBManager
+	TransactionManager   TransactionManager
+	ItemDBManager        ItemDBManager
+	ListDBManager        ListDBManager
+	BoardDBManager       BoardDBManager
+	UserDBManager        UserDBManager
+	TagDBManager         TagDBManager
+	MemberDBManager      MemberDBManager
+	ChecklistDBManager   ChecklistDBManager
+	CheckItemDBManager   CheckItemDBManager
+	CommentDBManager     CommentDBManager
+	ActivityDBManager    ActivityDBManager
+	WebhookDBManager     WebhookDBManager
+	DeliveryDBManager    DeliveryDBManager
+	AccessTokenDBManager AccessTokenDBManager
+	SessionDBManager     SessionDBManager
 }
 
-// NewDBManager generates new DB manager.
+// NewDBManager generates new DB manager. Pending migrations are applied to DB.
+// It fails if DB has a newer schema than this server knows.
 func NewDBManager() (DBManager, error) {
-	db, err := gorm.Open("sqlite3", os.Getenv("DB_PATH"))
+	db, err := openDB(os.Getenv("DB_DRIVER"))
 	if err != nil {
-		return DBManager{}, errors.New("failed to connect database")
+		return DBManager{}, err
+	}
+
+	m, err := newMigrator(db)
+	if err != nil {
+		return DBManager{}, err
+	}
+	if _, err := m.Up(); err != nil {
+		return DBManager{}, err
+	}
+
+	dbm := DBManager{
+		TransactionManager: newTransactionManager(db),
+	}
+	return dbm, nil
+}
+
+// OpenDBManager generates new DB manager without changing DB. It is used by tools which must not
+// migrate DB, and fails if a schema of DB is not the latest version.
+func OpenDBManager() (DBManager, error) {
+	db, err := openDB(os.Getenv("DB_DRIVER"))
+	if err != nil {
+		return DBManager{}, err
+	}
+
+	version := 0
+	if db.HasTable(&SchemaMigration{}) {
+		m := Migrator{db: db}
+		if version, err = m.Version(); err != nil {
+			db.Close()
+			return DBManager{}, err
+		}
+	}
+	switch {
+	case version < LatestVersion():
+		db.Close()
+		return DBManager{}, fmt.Errorf("schema version %d of DB is older than version %d of this server. Run 'migrate up' first", version, LatestVersion())
+	case version > LatestVersion():
+		db.Close()
+		return DBManager{}, fmt.Errorf("schema version %d of DB is newer than version %d supported by this server", version, LatestVersion())
 	}
 
 	dbm := DBManager{
 		TransactionManager: newTransactionManager(db),
-		ItemDBManager:      newItemDBManager(db),
-		ListDBManager:      newListDBManager(db),
-		BoardDBManager:     newBoardDBManager(db),
-		UserDBManager:      newUserDBManager(db),
-		TagDBManager:       newTagDBManager(db),
 	}
 	return dbm, nil
 }
 
+// openDB connects to DB selected by driver. SQLite is used if driver is empty.
+// SQLite DB is a file at DB_PATH, and PostgreSQL DB is specified by a connection string in DB_URL.
+func openDB(driver string) (*gorm.DB, error) {
+	var db *gorm.DB
+	var err error
+	switch driver {
+	case "", "sqlite3":
+		db, err = gorm.Open("sqlite3", dataSourceName(os.Getenv("DB_PATH")))
+	case "postgres":
+		db, err = gorm.Open("postgres", os.Getenv("DB_URL"))
+	default:
+		return nil, errors.New("unknown database driver: " + driver)
+	}
+	if err != nil {
+		return nil, errors.New("failed to connect database")
+	}
+	return db, nil
+}
+
+// dataSourceName returns a DSN enabling foreign key constraints, which SQLite disables by default.
+func dataSourceName(path string) string {
+	if path == "" || strings.Contains(path, "_foreign_keys=") || strings.Contains(path, "_fk=") {
+		return path
+	}
+	if strings.Contains(path, "?") {
+		return path + "&_foreign_keys=1"
+	}
+	return path + "?_foreign_keys=1"
+}
+
 func convertData(data interface{}) interface{} {
 	if data == nil {
 		return gorm.Expr("NULL")
@@ -46,6 +124,16 @@ func convertData(data interface{}) interface{} {
 	return data
 }
 
+// convertTime returns nil if t is zero. SQLite compares times as strings,
+// so times are saved in UTC without fractional seconds to keep them comparable.
+func convertTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	u := t.UTC().Truncate(time.Second)
+	return &u
+}
+
 func validatePrimaryKeys(targetName string, keys ...string) error {
 	for _, k := range keys {
 		if k == "" {
@@ -60,7 +148,17 @@ func validatePrimaryKeys(targetName string, keys ...string) error {
 	return nil
 }
 
-func convertError(err error, id, userID, act string) error {
+// convertError converts an error of DB. It returns TimeoutError if a context is done,
+// because a transaction is rolled back and its queries fail when its context is done.
+func convertError(ctx context.Context, err error, id, userID, act string) error {
+	if ctx.Err() != nil {
+		return model.TimeoutError{
+			UserID: userID,
+			Err:    ctx.Err(),
+			ID:     id,
+			Act:    act,
+		}
+	}
 	if gorm.IsRecordNotFoundError(err) {
 		return model.NotFoundError{
 			UserID: userID,
@@ -1,9 +1,9 @@
 package rdb
 
 import (
+	"context"
 	"time"
 
-	"github.com/jinzhu/gorm"
 	"github.com/x-color/vue-trello/model"
 	"github.com/x-color/vue-trello/usecase"
 )
@@ -11,6 +11,8 @@ import (
 // Tag is Tag data model for DB.
 type Tag struct {
 	ID        string `gorm:"primary_key"`
+	UserID    string `gorm:"index;not null;default:''"`
+	BoardID   string `gorm:"index;not null;default:''"`
 	Name      string
 	Color     string
 	CreatedAt time.Time
@@ -20,15 +22,19 @@ type Tag struct {
 
 func (t *Tag) convertFrom(tag model.Tag) {
 	t.ID = tag.ID
+	t.UserID = tag.UserID
+	t.BoardID = tag.BoardID
 	t.Name = tag.Name
 	t.Color = string(tag.Color)
 }
 
 func (t *Tag) convertTo() model.Tag {
 	tag := model.Tag{
-		ID:    t.ID,
-		Name:  t.Name,
-		Color: model.Color(t.Color),
+		ID:      t.ID,
+		UserID:  t.UserID,
+		BoardID: t.BoardID,
+		Name:    t.Name,
+		Color:   model.Color(t.Color),
 	}
 	return tag
 }
@@ -39,41 +45,106 @@ type Tags []Tag
 // TagDBManager is DB manager for Tag.
 type TagDBManager struct{}
 
-func newTagDBManager(db *gorm.DB) TagDBManager {
-	db.AutoMigrate(&Tag{})
-	return TagDBManager{}
+// Create registers a Tag to DB.
+func (*TagDBManager) Create(ctx context.Context, tx usecase.Transaction, tag model.Tag) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("tag", tag.ID); err != nil {
+		return err
+	}
+
+	t := Tag{}
+	t.convertFrom(tag)
+
+	if err := db.Create(&t).Error; err != nil {
+		return convertError(ctx, err, t.ID, "(No-ID)", "create tag")
+	}
+	return nil
 }
 
-// Create registers a Tag to DB.
-func (*TagDBManager) Create(tx usecase.Transaction, tag model.Tag) error {
+// Update updates specific fields of a Tag in DB.
+func (*TagDBManager) Update(ctx context.Context, tx usecase.Transaction, tag model.Tag, updates map[string]interface{}) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
 	if err := validatePrimaryKeys("tag", tag.ID); err != nil {
 		return err
 	}
 
 	t := Tag{}
 	t.convertFrom(tag)
+	err = db.Model(&t).Updates(queryForTag(updates)).Error
+	if err != nil {
+		return convertError(ctx, err, t.ID, t.UserID, "update tag")
+	}
+	return nil
+}
 
-	if err := tx.DB().(*gorm.DB).Create(&t).Error; err != nil {
-		return model.ServerError{
-			UserID: "(No-ID)",
-			Err:    err,
-			ID:     t.ID,
-			Act:    "create tag",
-		}
+// Delete removes a Tag from DB.
+func (*TagDBManager) Delete(ctx context.Context, tx usecase.Transaction, tag model.Tag) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("tag", tag.ID); err != nil {
+		return err
+	}
+
+	t := Tag{}
+	t.convertFrom(tag)
+
+	if err := db.Delete(&t).Error; err != nil {
+		return convertError(ctx, err, t.ID, t.UserID, "delete tag")
 	}
 	return nil
 }
 
-// Find get Tags.
-func (*TagDBManager) Find(tx usecase.Transaction, conditions map[string]interface{}) (model.Tags, error) {
+// FindByID gets a Tag had specific ID from DB.
+func (*TagDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.Tag, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	if err := validatePrimaryKeys("tag", id); err != nil {
+		return model.Tag{}, err
+	}
+
+	r := Tag{}
+	if err := db.Where(&Tag{ID: id}).First(&r).Error; err != nil {
+		return model.Tag{}, convertError(ctx, err, id, "(No-ID)", "find tag")
+	}
+	return r.convertTo(), nil
+}
+
+// Find gets Tags matched with a filter.
+func (*TagDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.TagFilter) (model.Tags, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Tags{}, err
+	}
+
+	db = whereIn(db, "id", filter.IDs)
+	db = whereIn(db, "user_id", filter.UserIDs)
+	db = whereIn(db, "board_id", filter.BoardIDs)
+	db = whereContains(db, "name", filter.NameContains)
+	db, err = orderBy(db, "tags", filter.Sort, map[usecase.SortKey]string{
+		usecase.SortByName:      "name",
+		usecase.SortByCreatedAt: "created_at",
+	})
+	if err != nil {
+		return model.Tags{}, err
+	}
+
 	r := Tags{}
-	if err := tx.DB().(*gorm.DB).Where(queryForTag(conditions)).Find(&r).Error; err != nil {
-		return model.Tags{}, model.ServerError{
-			UserID: "(No-ID)",
-			Err:    err,
-			ID:     "(No-ID)",
-			Act:    "find all tags",
-		}
+	if err := paginate(db, filter.Page).Find(&r).Error; err != nil {
+		return model.Tags{}, convertError(ctx, err, idForError(filter.IDs), idForError(filter.UserIDs), "find tags")
 	}
 
 	tags := model.Tags{}
@@ -89,6 +160,12 @@ func queryForTag(data map[string]interface{}) map[string]interface{} {
 	if v, ok := data["ID"]; ok {
 		query["id"] = v
 	}
+	if v, ok := data["UserID"]; ok {
+		query["user_id"] = v
+	}
+	if v, ok := data["BoardID"]; ok {
+		query["board_id"] = v
+	}
 	if v, ok := data["Name"]; ok {
 		query["name"] = v
 	}
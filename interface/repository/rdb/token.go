@@ -0,0 +1,158 @@
+package rdb
+
+import (
+	"context"
+	"time"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// AccessToken is AccessToken data model for DB.
+// ExpiresAt is null if it never expires.
+type AccessToken struct {
+	ID        string `gorm:"primary_key"`
+	UserID    string `gorm:"index"`
+	Name      string
+	Hash      string `gorm:"unique_index"`
+	ReadOnly  bool
+	BoardID   string
+	ExpiresAt *time.Time
+	CreatedAt time.Time
+}
+
+func (a *AccessToken) convertFrom(token model.AccessToken) {
+	a.ID = token.ID
+	a.UserID = token.UserID
+	a.Name = token.Name
+	a.Hash = token.Hash
+	a.ReadOnly = token.ReadOnly
+	a.BoardID = token.BoardID
+	a.ExpiresAt = nil
+	if !token.ExpiresAt.IsZero() {
+		t := token.ExpiresAt
+		a.ExpiresAt = &t
+	}
+	a.CreatedAt = token.CreatedAt
+}
+
+func (a *AccessToken) convertTo() model.AccessToken {
+	token := model.AccessToken{
+		ID:        a.ID,
+		UserID:    a.UserID,
+		Name:      a.Name,
+		Hash:      a.Hash,
+		ReadOnly:  a.ReadOnly,
+		BoardID:   a.BoardID,
+		CreatedAt: a.CreatedAt,
+	}
+	if a.ExpiresAt != nil {
+		token.ExpiresAt = *a.ExpiresAt
+	}
+	return token
+}
+
+// AccessTokens is a slice of AccessToken data model.
+type AccessTokens []AccessToken
+
+// AccessTokenDBManager is DB manager for AccessToken.
+type AccessTokenDBManager struct{}
+
+// Create registers a AccessToken to DB.
+func (*AccessTokenDBManager) Create(ctx context.Context, tx usecase.Transaction, token model.AccessToken) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("access token", token.ID); err != nil {
+		return err
+	}
+
+	a := AccessToken{}
+	a.convertFrom(token)
+
+	if err := db.Create(&a).Error; err != nil {
+		return convertError(ctx, err, a.ID, a.UserID, "create access token")
+	}
+	return nil
+}
+
+// Delete removes a AccessToken from DB.
+func (*AccessTokenDBManager) Delete(ctx context.Context, tx usecase.Transaction, token model.AccessToken) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("access token", token.ID); err != nil {
+		return err
+	}
+
+	a := AccessToken{}
+	a.convertFrom(token)
+
+	if err := db.Delete(&a).Error; err != nil {
+		return convertError(ctx, err, a.ID, a.UserID, "delete access token")
+	}
+	return nil
+}
+
+// FindByID gets a AccessToken had specific ID from DB.
+func (*AccessTokenDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.AccessToken, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.AccessToken{}, err
+	}
+
+	if err := validatePrimaryKeys("access token", id); err != nil {
+		return model.AccessToken{}, err
+	}
+
+	r := AccessToken{}
+	if err := db.Where(&AccessToken{ID: id}).First(&r).Error; err != nil {
+		return model.AccessToken{}, convertError(ctx, err, id, "(No-ID)", "find access token")
+	}
+	return r.convertTo(), nil
+}
+
+// FindByHash gets a AccessToken had specific hash from DB.
+func (*AccessTokenDBManager) FindByHash(ctx context.Context, tx usecase.Transaction, hash string) (model.AccessToken, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.AccessToken{}, err
+	}
+
+	if err := validatePrimaryKeys("access token", hash); err != nil {
+		return model.AccessToken{}, err
+	}
+
+	r := AccessToken{}
+	if err := db.Where(&AccessToken{Hash: hash}).First(&r).Error; err != nil {
+		return model.AccessToken{}, convertError(ctx, err, "(No-ID)", "(No-ID)", "find access token by hash")
+	}
+	return r.convertTo(), nil
+}
+
+// Find gets AccessTokens matched with a filter.
+func (*AccessTokenDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.AccessTokenFilter) (model.AccessTokens, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.AccessTokens{}, err
+	}
+
+	db = whereIn(db, "id", filter.IDs)
+	db = whereIn(db, "user_id", filter.UserIDs)
+
+	r := AccessTokens{}
+	if err := paginate(db.Order("created_at").Order("id"), filter.Page).Find(&r).Error; err != nil {
+		return model.AccessTokens{}, convertError(ctx, err, idForError(filter.UserIDs), "(No-ID)", "find access tokens")
+	}
+
+	tokens := model.AccessTokens{}
+	for _, ra := range r {
+		tokens = append(tokens, ra.convertTo())
+	}
+
+	return tokens, nil
+}
@@ -1,7 +1,10 @@
 package rdb
 
 import (
+	"context"
+
 	"github.com/jinzhu/gorm"
+	"github.com/x-color/vue-trello/model"
 	"github.com/x-color/vue-trello/usecase"
 )
 
@@ -10,10 +13,12 @@ type TransactionManager struct {
 	db *gorm.DB
 }
 
-func (m *TransactionManager) BeginTransaction(on bool) usecase.Transaction {
+// BeginTransaction starts a transaction if on is true. The transaction is rolled back
+// when a context is done.
+func (m *TransactionManager) BeginTransaction(ctx context.Context, on bool) usecase.Transaction {
 	if on {
 		return &Transaction{
-			db: m.db.Begin(),
+			db: m.db.BeginTx(ctx, nil),
 			on: true,
 		}
 	}
@@ -24,6 +29,27 @@ func (m *TransactionManager) BeginTransaction(on bool) usecase.Transaction {
 	}
 }
 
+// RunInTx runs f in a transaction. The transaction is committed if f returns nil,
+// and rolled back if f returns an error or panics.
+func (m *TransactionManager) RunInTx(ctx context.Context, f func(tx usecase.Transaction) error) error {
+	tx := m.BeginTransaction(ctx, true)
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return convertError(ctx, err, "(No-ID)", "(No-ID)", "commit transaction")
+	}
+	return nil
+}
+
 func newTransactionManager(db *gorm.DB) TransactionManager {
 	return TransactionManager{
 		db: db,
@@ -39,14 +65,30 @@ func (tx *Transaction) DB() interface{} {
 	return interface{}(tx.db)
 }
 
-func (tx *Transaction) Commit() {
+func (tx *Transaction) Commit() error {
 	if tx.on {
-		tx.db.Commit()
+		return tx.db.Commit().Error
 	}
+	return nil
 }
 
-func (tx *Transaction) Rollback() {
+func (tx *Transaction) Rollback() error {
 	if tx.on {
-		tx.db.Rollback()
+		return tx.db.Rollback().Error
+	}
+	return nil
+}
+
+// dbOf returns DB of a transaction. It returns TimeoutError if a context is already done,
+// because queries out of transactions are not canceled by the context.
+func dbOf(ctx context.Context, tx usecase.Transaction) (*gorm.DB, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, model.TimeoutError{
+			UserID: "(No-ID)",
+			Err:    err,
+			ID:     "(No-ID)",
+			Act:    "access DB",
+		}
 	}
+	return tx.DB().(*gorm.DB), nil
 }
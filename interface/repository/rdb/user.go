@@ -1,9 +1,9 @@
 package rdb
 
 import (
+	"context"
 	"time"
 
-	"github.com/jinzhu/gorm"
 	"github.com/x-color/vue-trello/model"
 	"github.com/x-color/vue-trello/usecase"
 )
@@ -40,49 +40,85 @@ type Users []User
 // UserDBManager is DB manager for User.
 type UserDBManager struct{}
 
-func newUserDBManager(db *gorm.DB) UserDBManager {
-	db.AutoMigrate(&User{})
-	return UserDBManager{}
+// Create registers a User to DB.
+func (*UserDBManager) Create(ctx context.Context, tx usecase.Transaction, user model.User) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("user", user.ID); err != nil {
+		return err
+	}
+
+	u := User{}
+	u.convertFrom(user)
+
+	if err := db.Create(&u).Error; err != nil {
+		return convertError(ctx, err, u.ID, u.ID, "create user")
+	}
+	return nil
 }
 
-// Create registers a User to DB.
-func (*UserDBManager) Create(tx usecase.Transaction, user model.User) error {
+// Update updates a User in DB.
+func (*UserDBManager) Update(ctx context.Context, tx usecase.Transaction, user model.User, updates map[string]interface{}) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
 	if err := validatePrimaryKeys("user", user.ID); err != nil {
 		return err
 	}
 
 	u := User{}
 	u.convertFrom(user)
+	err = db.Model(&u).Updates(queryForUser(updates)).Error
+	if err != nil {
+		return convertError(ctx, err, u.ID, u.ID, "update user")
+	}
+	return nil
+}
 
-	if err := tx.DB().(*gorm.DB).Create(&u).Error; err != nil {
-		return model.ServerError{
-			UserID: u.ID,
-			Err:    err,
-			ID:     u.ID,
-			Act:    "create user",
-		}
+// Delete removes a User from DB.
+func (*UserDBManager) Delete(ctx context.Context, tx usecase.Transaction, user model.User) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("user", user.ID); err != nil {
+		return err
+	}
+
+	u := User{}
+	u.convertFrom(user)
+
+	if err := db.Delete(&u).Error; err != nil {
+		return convertError(ctx, err, u.ID, u.ID, "delete user")
 	}
 	return nil
 }
 
-// Find gets a User.
-func (*UserDBManager) Find(tx usecase.Transaction, conditions map[string]interface{}) (model.User, error) {
+// Find gets a User matched with a filter.
+func (*UserDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.UserFilter) (model.User, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	db = whereIn(db, "id", filter.IDs)
+	db = whereIn(db, "name", filter.Names)
+
 	r := User{}
-	if err := tx.DB().(*gorm.DB).Where(queryForUser(conditions)).First(&r).Error; err != nil {
-		userID := "(No-ID)"
-		if v, ok := conditions["user_id"]; ok {
-			userID = v.(string)
-		}
-		return model.User{}, convertError(err, userID, userID, "find user")
+	if err := db.First(&r).Error; err != nil {
+		return model.User{}, convertError(ctx, err, idForError(filter.IDs), idForError(filter.IDs), "find user")
 	}
 	return r.convertTo(), nil
 }
 
 func queryForUser(data map[string]interface{}) map[string]interface{} {
 	query := make(map[string]interface{})
-	if v, ok := data["ID"]; ok {
-		query["id"] = v
-	}
 	if v, ok := data["Name"]; ok {
 		query["name"] = v
 	}
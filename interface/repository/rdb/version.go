@@ -0,0 +1,53 @@
+package rdb
+
+import (
+	"context"
+
+	"github.com/jinzhu/gorm"
+	"github.com/x-color/vue-trello/model"
+)
+
+// updateVersioned updates a row of value with query and bumps its version.
+// If version is not zero, the row is updated only if it still has the version.
+func updateVersioned(ctx context.Context, db *gorm.DB, value interface{}, version int, query map[string]interface{}, id, userID, act string) error {
+	query["version"] = gorm.Expr("version + 1")
+	if version != 0 {
+		db = db.Where("version = ?", version)
+	}
+
+	r := db.Model(value).Updates(query)
+	if r.Error != nil {
+		return convertError(ctx, r.Error, id, userID, act)
+	}
+	if version != 0 && r.RowsAffected == 0 {
+		return model.PreconditionFailedError{
+			UserID: userID,
+			Err:    nil,
+			ID:     id,
+			Act:    act,
+		}
+	}
+	return nil
+}
+
+// deleteVersioned deletes a row of value. If version is not zero, the row is deleted
+// only if it still has the version.
+func deleteVersioned(ctx context.Context, db *gorm.DB, value interface{}, version int, id, userID, act string) error {
+	if version != 0 {
+		db = db.Where("version = ?", version)
+	}
+
+	r := db.Delete(value)
+	if r.Error != nil {
+		return convertError(ctx, r.Error, id, userID, act)
+	}
+	if version != 0 && r.RowsAffected == 0 {
+		return model.PreconditionFailedError{
+			UserID: userID,
+			Err:    nil,
+			ID:     id,
+			Act:    act,
+		}
+	}
+	return nil
+}
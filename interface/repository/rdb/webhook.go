@@ -0,0 +1,139 @@
+package rdb
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Webhook is Webhook data model for DB.
+// Events are joined by commas. An empty string means all events.
+type Webhook struct {
+	ID        string `gorm:"primary_key"`
+	BoardID   string `gorm:"index"`
+	UserID    string
+	URL       string
+	Secret    string
+	Events    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (w *Webhook) convertFrom(webhook model.Webhook) {
+	w.ID = webhook.ID
+	w.BoardID = webhook.BoardID
+	w.UserID = webhook.UserID
+	w.URL = webhook.URL
+	w.Secret = webhook.Secret
+	w.Events = strings.Join(webhook.Events, ",")
+	w.CreatedAt = webhook.CreatedAt
+}
+
+func (w *Webhook) convertTo() model.Webhook {
+	events := []string{}
+	if w.Events != "" {
+		events = strings.Split(w.Events, ",")
+	}
+
+	webhook := model.Webhook{
+		ID:        w.ID,
+		BoardID:   w.BoardID,
+		UserID:    w.UserID,
+		URL:       w.URL,
+		Secret:    w.Secret,
+		Events:    events,
+		CreatedAt: w.CreatedAt,
+	}
+	return webhook
+}
+
+// Webhooks is a slice of Webhook data model.
+type Webhooks []Webhook
+
+// WebhookDBManager is DB manager for Webhook.
+type WebhookDBManager struct{}
+
+// Create registers a Webhook to DB.
+func (*WebhookDBManager) Create(ctx context.Context, tx usecase.Transaction, webhook model.Webhook) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("webhook", webhook.ID); err != nil {
+		return err
+	}
+
+	w := Webhook{}
+	w.convertFrom(webhook)
+
+	if err := db.Create(&w).Error; err != nil {
+		return convertError(ctx, err, w.ID, w.UserID, "create webhook")
+	}
+	return nil
+}
+
+// Delete removes a Webhook from DB. It is not soft deleted, so that its secret does not remain.
+func (*WebhookDBManager) Delete(ctx context.Context, tx usecase.Transaction, webhook model.Webhook) error {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := validatePrimaryKeys("webhook", webhook.ID); err != nil {
+		return err
+	}
+
+	w := Webhook{}
+	w.convertFrom(webhook)
+
+	if err := db.Unscoped().Delete(&w).Error; err != nil {
+		return convertError(ctx, err, w.ID, w.UserID, "delete webhook")
+	}
+	return nil
+}
+
+// FindByID gets a Webhook had specific ID from DB.
+func (*WebhookDBManager) FindByID(ctx context.Context, tx usecase.Transaction, id string) (model.Webhook, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	if err := validatePrimaryKeys("webhook", id); err != nil {
+		return model.Webhook{}, err
+	}
+
+	r := Webhook{}
+	if err := db.Where(&Webhook{ID: id}).First(&r).Error; err != nil {
+		return model.Webhook{}, convertError(ctx, err, id, "(No-ID)", "find webhook")
+	}
+	return r.convertTo(), nil
+}
+
+// Find gets Webhooks matched with a filter.
+func (*WebhookDBManager) Find(ctx context.Context, tx usecase.Transaction, filter usecase.WebhookFilter) (model.Webhooks, error) {
+	db, err := dbOf(ctx, tx)
+	if err != nil {
+		return model.Webhooks{}, err
+	}
+
+	db = whereIn(db, "id", filter.IDs)
+	db = whereIn(db, "board_id", filter.BoardIDs)
+
+	r := Webhooks{}
+	if err := paginate(db.Order("created_at").Order("id"), filter.Page).Find(&r).Error; err != nil {
+		return model.Webhooks{}, convertError(ctx, err, idForError(filter.BoardIDs), "(No-ID)", "find webhooks")
+	}
+
+	webhooks := model.Webhooks{}
+	for _, rw := range r {
+		webhooks = append(webhooks, rw.convertTo())
+	}
+
+	return webhooks, nil
+}
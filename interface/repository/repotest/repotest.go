@@ -0,0 +1,453 @@
+// Package repotest provides a test suite of repositories.
+// Each implementation of repositories runs it in its tests, so all of them keep the same semantics.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// Repositories includes repositories tested by the suite.
+type Repositories struct {
+	Tx       usecase.TransactionRepository
+	Board    usecase.BoardRepository
+	List     usecase.ListRepository
+	Item     usecase.ItemRepository
+	Tag      usecase.TagRepository
+	User     usecase.UserRepository
+	Member   usecase.MemberRepository
+	Activity usecase.ActivityRepository
+	Delivery usecase.DeliveryRepository
+}
+
+// Run runs the suite. newRepos returns Repositories having no data and a function to clean them up.
+// It is called for each test.
+func Run(t *testing.T, newRepos func(t *testing.T) (Repositories, func())) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, r Repositories)
+	}{
+		{"Transaction", testTransaction},
+		{"Board", testBoard},
+		{"BoardFilter", testBoardFilter},
+		{"ItemTags", testItemTags},
+		{"ItemFilter", testItemFilter},
+		{"User", testUser},
+		{"Member", testMember},
+		{"Activity", testActivity},
+		{"DeliveryClaim", testDeliveryClaim},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, cleanup := newRepos(t)
+			defer cleanup()
+			tt.test(t, r)
+		})
+	}
+}
+
+var errRollback = errors.New("rollback")
+
+func testTransaction(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	// Changes are visible in the transaction, and discarded by rollback
+	err := r.Tx.RunInTx(ctx, func(tx usecase.Transaction) error {
+		if err := r.Board.Create(ctx, tx, newBoard("b1", "u1", "a")); err != nil {
+			return err
+		}
+		if _, err := r.Board.FindByID(ctx, tx, "b1"); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("RunInTx() error = %v, want %v", err, errRollback)
+	}
+	assertNotFound(t, r, "b1")
+
+	tx := r.Tx.BeginTransaction(ctx, true)
+	mustDo(t, r.Board.Create(ctx, tx, newBoard("b2", "u1", "b")))
+	mustDo(t, tx.Rollback())
+	assertNotFound(t, r, "b2")
+
+	// Changes are kept by commit
+	err = r.Tx.RunInTx(ctx, func(tx usecase.Transaction) error {
+		return r.Board.Create(ctx, tx, newBoard("b3", "u1", "c"))
+	})
+	mustDo(t, err)
+	if _, err := r.Board.FindByID(ctx, noTx(ctx, r), "b3"); err != nil {
+		t.Errorf("FindByID() error = %v after commit", err)
+	}
+}
+
+func testBoard(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	tx := noTx(ctx, r)
+
+	board := newBoard("b1", "u1", "title")
+	board.Text = "text"
+	mustDo(t, r.Board.Create(ctx, tx, board))
+
+	got, err := r.Board.FindByID(ctx, tx, "b1")
+	mustDo(t, err)
+	if !reflect.DeepEqual(got, board) {
+		t.Errorf("FindByID() = %+v, want %+v", got, board)
+	}
+
+	// Update bumps a version, and fails if the Board has another version
+	mustDo(t, r.Board.Update(ctx, tx, board, map[string]interface{}{"Title": "new", "Text": ""}))
+	got, err = r.Board.FindByID(ctx, tx, "b1")
+	mustDo(t, err)
+	if got.Title != "new" || got.Text != "" || got.Version != 2 {
+		t.Errorf("FindByID() = %+v after update, want new title, empty text and version 2", got)
+	}
+	err = r.Board.Update(ctx, tx, board, map[string]interface{}{"Title": "old"})
+	if !errors.Is(err, model.PreconditionFailedError{}) {
+		t.Errorf("Update() of old version error = %v, want PreconditionFailedError", err)
+	}
+	err = r.Board.Delete(ctx, tx, board)
+	if !errors.Is(err, model.PreconditionFailedError{}) {
+		t.Errorf("Delete() of old version error = %v, want PreconditionFailedError", err)
+	}
+
+	mustDo(t, r.Board.Delete(ctx, tx, got))
+	assertNotFound(t, r, "b1")
+}
+
+func testBoardFilter(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	tx := noTx(ctx, r)
+
+	for _, b := range []model.Board{
+		newBoard("b1", "u1", "Apple"),
+		newBoard("b2", "u1", "banana"),
+		newBoard("b3", "u2", "cherry pie"),
+		newBoard("b4", "u2", "apple pie"),
+	} {
+		b.Rank = b.ID
+		mustDo(t, r.Board.Create(ctx, tx, b))
+	}
+
+	tests := []struct {
+		name   string
+		filter usecase.BoardFilter
+		want   []string
+	}{
+		{"nil slice adds no condition", usecase.BoardFilter{Sort: byRank}, []string{"b1", "b2", "b3", "b4"}},
+		{"empty slice matches nothing", usecase.BoardFilter{UserIDs: []string{}}, []string{}},
+		{"slice", usecase.BoardFilter{UserIDs: []string{"u2", "u3"}, Sort: byRank}, []string{"b3", "b4"}},
+		{"text is case-insensitive", usecase.BoardFilter{TitleContains: "APPLE", Sort: byRank}, []string{"b1", "b4"}},
+		{"sort", usecase.BoardFilter{UserIDs: []string{"u2"}, Sort: usecase.Sort{Key: usecase.SortByTitle, Desc: true}}, []string{"b3", "b4"}},
+		{"page", usecase.BoardFilter{Sort: byRank, Page: usecase.Page{Offset: 1, Limit: 2}}, []string{"b2", "b3"}},
+	}
+	for _, tt := range tests {
+		boards, err := r.Board.Find(ctx, tx, tt.filter)
+		mustDo(t, err)
+		got := []string{}
+		for _, b := range boards {
+			got = append(got, b.ID)
+		}
+		if tt.filter.Sort.Key == "" {
+			sort.Strings(got)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Find() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func testItemTags(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	tx := noTx(ctx, r)
+
+	createList(t, r, "b1", "l1")
+	for _, id := range []string{"t1", "t2", "t3"} {
+		mustDo(t, r.Tag.Create(ctx, tx, model.Tag{ID: id, UserID: "u1", Name: id, Color: model.RED}))
+	}
+
+	// Tags are kept in order of an Item
+	item := newItem("i1", "l1")
+	item.Tags = model.Tags{{ID: "t2"}, {ID: "t1"}}
+	mustDo(t, r.Item.Create(ctx, tx, item))
+	mustDo(t, r.Item.Create(ctx, tx, newItem("i2", "l1")))
+	assertTags(t, r, "i1", []string{"t2", "t1"})
+
+	items, err := r.Item.Find(ctx, tx, usecase.ItemFilter{TagIDs: []string{"t1", "t3"}})
+	mustDo(t, err)
+	if len(items) != 1 || items[0].ID != "i1" {
+		t.Errorf("Find() by tags = %v, want only i1", items)
+	}
+
+	mustDo(t, r.Item.Update(ctx, tx, item, map[string]interface{}{"Tags": []string{"t3", "t2"}}))
+	assertTags(t, r, "i1", []string{"t3", "t2"})
+
+	// Tags which do not exist can not be attached
+	got, err := r.Item.FindByID(ctx, tx, "i1")
+	mustDo(t, err)
+	if err := r.Item.Update(ctx, tx, got, map[string]interface{}{"Tags": []string{"unknown"}}); err == nil {
+		t.Errorf("Update() with unknown tag error = nil, want an error")
+	}
+}
+
+func testItemFilter(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	tx := noTx(ctx, r)
+
+	createList(t, r, "b1", "l1")
+	createList(t, r, "b1", "l2")
+	day := time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC)
+	for _, it := range []struct {
+		id, list  string
+		due       time.Time
+		completed bool
+	}{
+		{"i1", "l1", day, false},
+		{"i2", "l1", day.AddDate(0, 0, 1), true},
+		{"i3", "l2", time.Time{}, false},
+	} {
+		item := newItem(it.id, it.list)
+		item.DueDate = it.due
+		item.Completed = it.completed
+		mustDo(t, r.Item.Create(ctx, tx, item))
+	}
+
+	got, err := r.Item.FindByID(ctx, tx, "i1")
+	mustDo(t, err)
+	if !got.DueDate.Equal(day) || !got.StartDate.IsZero() {
+		t.Errorf("FindByID() dates = %v, %v, want zero and %v", got.StartDate, got.DueDate, day)
+	}
+
+	completed := true
+	tests := []struct {
+		name   string
+		filter usecase.ItemFilter
+		want   []string
+	}{
+		{"lists", usecase.ItemFilter{ListIDs: []string{"l2"}}, []string{"i3"}},
+		{"completed", usecase.ItemFilter{Completed: &completed}, []string{"i2"}},
+		{"due date", usecase.ItemFilter{DueUntil: day}, []string{"i1"}},
+		{"sort by due date", usecase.ItemFilter{ListIDs: []string{"l1"}, Sort: usecase.Sort{Key: usecase.SortByDueDate, Desc: true}}, []string{"i2", "i1"}},
+	}
+	for _, tt := range tests {
+		items, err := r.Item.Find(ctx, tx, tt.filter)
+		mustDo(t, err)
+		got := []string{}
+		for _, it := range items {
+			got = append(got, it.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Find() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func testUser(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	tx := noTx(ctx, r)
+
+	mustDo(t, r.User.Create(ctx, tx, model.User{ID: "u1", Name: "alice", Password: "hash"}))
+
+	got, err := r.User.Find(ctx, tx, usecase.UserFilter{Names: []string{"alice"}})
+	mustDo(t, err)
+	if got.ID != "u1" || got.Password != "hash" {
+		t.Errorf("Find() = %+v, want u1", got)
+	}
+
+	_, err = r.User.Find(ctx, tx, usecase.UserFilter{Names: []string{"bob"}})
+	if !errors.Is(err, model.NotFoundError{}) {
+		t.Errorf("Find() of unknown user error = %v, want NotFoundError", err)
+	}
+}
+
+func testMember(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	tx := noTx(ctx, r)
+
+	mustDo(t, r.Board.Create(ctx, tx, newBoard("b1", "u1", "title")))
+	for _, m := range []model.Member{
+		{BoardID: "b1", UserID: "u1", Role: model.OWNER},
+		{BoardID: "b1", UserID: "u2", Role: model.VIEWER},
+		{BoardID: "b1", UserID: "u3", Role: model.EDITOR},
+	} {
+		mustDo(t, r.Member.Create(ctx, tx, m))
+	}
+
+	// Members are got in order of joining
+	members, err := r.Member.Find(ctx, tx, usecase.MemberFilter{BoardIDs: []string{"b1"}, Roles: model.Roles{model.VIEWER, model.EDITOR}})
+	mustDo(t, err)
+	if len(members) != 2 || members[0].UserID != "u2" || members[1].UserID != "u3" {
+		t.Errorf("Find() = %+v, want u2 and u3", members)
+	}
+
+	member := model.Member{BoardID: "b1", UserID: "u2", Role: model.VIEWER}
+	mustDo(t, r.Member.Update(ctx, tx, member, map[string]interface{}{"Role": model.EDITOR}))
+	got, err := r.Member.FindByID(ctx, tx, "b1", "u2")
+	mustDo(t, err)
+	if got.Role != model.EDITOR {
+		t.Errorf("FindByID() role = %v after update, want %v", got.Role, model.EDITOR)
+	}
+
+	mustDo(t, r.Member.Delete(ctx, tx, member))
+	if _, err := r.Member.FindByID(ctx, tx, "b1", "u2"); !errors.Is(err, model.NotFoundError{}) {
+		t.Errorf("FindByID() after delete error = %v, want NotFoundError", err)
+	}
+}
+
+func testActivity(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	tx := noTx(ctx, r)
+
+	ids := []string{"a3", "a1", "a2", "a5", "a4"}
+	for _, id := range ids {
+		mustDo(t, r.Activity.Create(ctx, tx, model.Activity{
+			ID:        id,
+			BoardID:   "b1",
+			UserID:    "u1",
+			Action:    model.CREATE,
+			Target:    model.BOARD,
+			TargetID:  "b1",
+			CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		}))
+	}
+	mustDo(t, r.Activity.Create(ctx, tx, model.Activity{ID: "x1", BoardID: "b2", UserID: "u1", Action: model.CREATE, Target: model.BOARD}))
+
+	// Activities are ordered by recording, not by ID or time
+	tests := []struct {
+		name string
+		find func() (model.Activities, error)
+		want []string
+	}{
+		{"find", func() (model.Activities, error) {
+			return r.Activity.Find(ctx, tx, usecase.ActivityFilter{BoardIDs: []string{"b1"}})
+		}, ids},
+		{"since cursor", func() (model.Activities, error) { return r.Activity.FindSince(ctx, tx, "b1", "a1", 2) }, []string{"a2", "a5"}},
+		{"page", func() (model.Activities, error) { return r.Activity.FindPage(ctx, tx, "b1", "", 2) }, []string{"a4", "a5"}},
+		{"page cursor", func() (model.Activities, error) { return r.Activity.FindPage(ctx, tx, "b1", "a2", 10) }, []string{"a1", "a3"}},
+		{"after", func() (model.Activities, error) {
+			return r.Activity.Find(ctx, tx, usecase.ActivityFilter{BoardIDs: []string{"b1"}, After: "a2"})
+		}, []string{"a5", "a4"}},
+	}
+	for _, tt := range tests {
+		activities, err := tt.find()
+		mustDo(t, err)
+		got := []string{}
+		for _, a := range activities {
+			got = append(got, a.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func testDeliveryClaim(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	tx := noTx(ctx, r)
+
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := model.Delivery{ID: "d1", WebhookID: "w1", ActivityID: "a1", Status: model.PENDING, NextAttemptAt: created, CreatedAt: created}
+	mustDo(t, r.Delivery.Create(ctx, tx, d))
+
+	// Only one of claims of the same attempt succeeds
+	until := created.Add(time.Hour)
+	mustDo(t, r.Delivery.Claim(ctx, tx, d, until))
+	if err := r.Delivery.Claim(ctx, tx, d, until); !errors.Is(err, model.PreconditionFailedError{}) {
+		t.Errorf("Claim() error = %v, want PreconditionFailedError", err)
+	}
+
+	// A claimed Delivery is not due until the lease ends
+	due, err := r.Delivery.Find(ctx, tx, usecase.DeliveryFilter{Statuses: []model.DeliveryStatus{model.PENDING}, DueUntil: created})
+	mustDo(t, err)
+	if len(due) != 0 {
+		t.Errorf("got %d due deliveries, want none", len(due))
+	}
+	got, err := r.Delivery.Find(ctx, tx, usecase.DeliveryFilter{IDs: []string{"d1"}})
+	mustDo(t, err)
+	if len(got) != 1 || got[0].Attempts != 1 || !got[0].NextAttemptAt.Equal(until) {
+		t.Fatalf("got %+v, want 1 attempt until %v", got, until)
+	}
+
+	// A Delivery which is not pending can not be claimed
+	mustDo(t, r.Delivery.Update(ctx, tx, got[0], map[string]interface{}{"Status": model.SUCCEEDED}))
+	if err := r.Delivery.Claim(ctx, tx, got[0], until); !errors.Is(err, model.PreconditionFailedError{}) {
+		t.Errorf("Claim() error = %v for succeeded delivery, want PreconditionFailedError", err)
+	}
+}
+
+var byRank = usecase.Sort{Key: usecase.SortByRank}
+
+func newBoard(id, userID, title string) model.Board {
+	return model.Board{
+		ID:      id,
+		UserID:  userID,
+		Title:   title,
+		Color:   model.RED,
+		Rank:    "V",
+		Version: 1,
+	}
+}
+
+func newItem(id, listID string) model.Item {
+	return model.Item{
+		ID:      id,
+		ListID:  listID,
+		UserID:  "u1",
+		Title:   id,
+		Tags:    model.Tags{},
+		Rank:    id,
+		Version: 1,
+	}
+}
+
+// createList creates a List and its Board if the Board does not exist.
+func createList(t *testing.T, r Repositories, boardID, listID string) {
+	t.Helper()
+	ctx := context.Background()
+	tx := noTx(ctx, r)
+
+	if _, err := r.Board.FindByID(ctx, tx, boardID); errors.Is(err, model.NotFoundError{}) {
+		mustDo(t, r.Board.Create(ctx, tx, newBoard(boardID, "u1", boardID)))
+	}
+	mustDo(t, r.List.Create(ctx, tx, model.List{ID: listID, BoardID: boardID, UserID: "u1", Title: listID, Rank: listID, Version: 1}))
+}
+
+func noTx(ctx context.Context, r Repositories) usecase.Transaction {
+	return r.Tx.BeginTransaction(ctx, false)
+}
+
+func assertNotFound(t *testing.T, r Repositories, boardID string) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := r.Board.FindByID(ctx, noTx(ctx, r), boardID); !errors.Is(err, model.NotFoundError{}) {
+		t.Errorf("FindByID(%q) error = %v, want NotFoundError", boardID, err)
+	}
+}
+
+func assertTags(t *testing.T, r Repositories, itemID string, want []string) {
+	t.Helper()
+	ctx := context.Background()
+	item, err := r.Item.FindByID(ctx, noTx(ctx, r), itemID)
+	mustDo(t, err)
+	got := []string{}
+	for _, tag := range item.Tags {
+		got = append(got, tag.ID)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindByID(%q) tags = %v, want %v", itemID, got, want)
+	}
+}
+
+func mustDo(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// Receiver is a stand-in of a webhook for testing. It verifies signatures of requests and writes
+// their events and payloads. It responds 500 to the first Fail valid requests to cause retries.
+type Receiver struct {
+	Secret string
+	Fail   int
+	Output io.Writer
+
+	mu       sync.Mutex
+	received int
+}
+
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !Verify(r.Secret, body, req.Header.Get(HeaderSignature)) {
+		fmt.Fprintf(r.Output, "invalid signature %s %s\n", req.Header.Get(HeaderEvent), req.Header.Get(HeaderDelivery))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	r.received++
+	if r.received <= r.Fail {
+		fmt.Fprintf(r.Output, "failed %s %s\n", req.Header.Get(HeaderEvent), req.Header.Get(HeaderDelivery))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprintf(r.Output, "received %s %s %s\n", req.Header.Get(HeaderEvent), req.Header.Get(HeaderDelivery), body)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,227 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/x-color/vue-trello/model"
+)
+
+// Headers of requests sent to webhooks.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// payload is JSON sent to webhooks. Activity has the same format as activities in API.
+type payload struct {
+	ID        string   `json:"id"`
+	Event     string   `json:"event"`
+	WebhookID string   `json:"webhook_id"`
+	BoardID   string   `json:"board_id"`
+	Activity  activity `json:"activity"`
+}
+
+type activity struct {
+	ID        string          `json:"id"`
+	UserID    string          `json:"user_id"`
+	UserName  string          `json:"name"`
+	Action    string          `json:"action"`
+	Target    string          `json:"target"`
+	TargetID  string          `json:"target_id"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func rawJSON(s string) json.RawMessage {
+	if s == "" {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(s)
+}
+
+// ErrForbiddenAddress is returned if a webhook is at an address which the server must not send requests to.
+var ErrForbiddenAddress = errors.New("address of webhook is not allowed")
+
+// forbiddenNetworks are loopback, private, link-local and unspecified networks. Webhooks in them could
+// reach the server itself or services in its network (e.g. metadata of cloud instances at 169.254.169.254).
+var forbiddenNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := []*net.IPNet{}
+	for _, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, n)
+	}
+	return networks
+}
+
+// Sender sends Activities to webhooks by HTTP POST.
+// It refuses to connect to forbidden networks unless they are in allowed networks.
+type Sender struct {
+	client  *http.Client
+	allowed []*net.IPNet
+}
+
+// NewSender returns a new Sender. allowed are networks which can be connected even if they are forbidden,
+// e.g. 127.0.0.1/32 for a receiver on the same host in tests.
+func NewSender(allowed []*net.IPNet) *Sender {
+	s := &Sender{
+		allowed: allowed,
+	}
+	// The address is checked by Control just before connecting, after the host is resolved.
+	// So a host which is resolved to another address at connecting (DNS rebinding) is also refused.
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: s.control,
+	}
+	s.client = &http.Client{
+		Transport: &http.Transport{
+			// Proxy is not used, because it would connect to webhooks instead of the Sender.
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		// Redirects are not followed, because they could lead to forbidden URLs.
+		// A response of a redirect is a failure of the delivery.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return s
+}
+
+// Validate checks that a host in rawurl is resolved only to permitted addresses.
+func (s *Sender) Validate(ctx context.Context, rawurl string) error {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return err
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return err
+	}
+	for _, addr := range addrs {
+		if !s.permits(addr.IP) {
+			return ErrForbiddenAddress
+		}
+	}
+	return nil
+}
+
+func (s *Sender) control(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !s.permits(ip) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+// permits checks that an address is in allowed networks or not in forbidden networks.
+func (s *Sender) permits(ip net.IP) bool {
+	for _, n := range s.allowed {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	if ip.IsUnspecified() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsMulticast() {
+		return false
+	}
+	for _, n := range forbiddenNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// Send posts an Activity as JSON signed with a secret of webhook and returns a status code of the response.
+func (s *Sender) Send(ctx context.Context, webhook model.Webhook, delivery model.Delivery, a model.Activity) (int, error) {
+	body, err := json.Marshal(payload{
+		ID:        delivery.ID,
+		Event:     delivery.Event,
+		WebhookID: webhook.ID,
+		BoardID:   webhook.BoardID,
+		Activity: activity{
+			ID:        a.ID,
+			UserID:    a.UserID,
+			UserName:  a.UserName,
+			Action:    string(a.Action),
+			Target:    string(a.Target),
+			TargetID:  a.TargetID,
+			Before:    rawJSON(a.Before),
+			After:     rawJSON(a.After),
+			CreatedAt: a.CreatedAt,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "vue-trello-webhook")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, body))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(res.Body, 64*1024))
+
+	return res.StatusCode, nil
+}
+
+// Sign returns a signature of body as "sha256=<hex of HMAC-SHA256>".
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature of body in constant time.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
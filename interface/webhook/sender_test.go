@@ -0,0 +1,149 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/x-color/vue-trello/model"
+)
+
+func TestPermits(t *testing.T) {
+	s := NewSender(mustParseCIDRs("127.0.0.1/32", "10.1.0.0/16"))
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		// Loopback
+		{"127.0.0.2", false},
+		{"::1", false},
+		// Link-local, e.g. metadata of cloud instances
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		// Private
+		{"10.0.0.1", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"100.64.0.1", false},
+		{"fc00::1", false},
+		// Unspecified and multicast
+		{"0.0.0.0", false},
+		{"::", false},
+		{"224.0.0.1", false},
+		// IPv4-mapped IPv6 addresses are checked as IPv4
+		{"::ffff:127.0.0.2", false},
+		{"::ffff:169.254.169.254", false},
+		// Allowed networks
+		{"127.0.0.1", true},
+		{"10.1.2.3", true},
+	}
+	for _, tt := range tests {
+		if got := s.permits(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("permits(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		url     string
+		allowed []*net.IPNet
+		wantErr bool
+	}{
+		{"http://127.0.0.1:8080/hook", nil, true},
+		{"http://[::1]/hook", nil, true},
+		{"http://169.254.169.254/latest/meta-data", nil, true},
+		{"http://192.168.0.10/hook", nil, true},
+		{"http://93.184.216.34/hook", nil, false},
+		{"http://127.0.0.1:8080/hook", mustParseCIDRs("127.0.0.1/32"), false},
+		{"http://[::1]/hook", mustParseCIDRs("127.0.0.1/32"), true},
+	}
+	for _, tt := range tests {
+		err := NewSender(tt.allowed).Validate(ctx, tt.url)
+		if tt.wantErr && !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("Validate(%s) with %v error = %v, want %v", tt.url, tt.allowed, err, ErrForbiddenAddress)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("Validate(%s) with %v error = %v, want nil", tt.url, tt.allowed, err)
+		}
+	}
+}
+
+// newTestServer starts a server counting requests at address.
+func newTestServer(t *testing.T, address string, handler http.HandlerFunc) (*httptest.Server, *int) {
+	t.Helper()
+	l, err := net.Listen("tcp", address)
+	if err != nil {
+		t.Skipf("can not listen on %s: %v", address, err)
+	}
+	count := 0
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		handler(w, r)
+	}))
+	srv.Listener.Close()
+	srv.Listener = l
+	srv.Start()
+	return srv, &count
+}
+
+func send(s *Sender, url string) (int, error) {
+	webhook := model.Webhook{ID: "w1", BoardID: "b1", URL: url, Secret: "secret"}
+	delivery := model.Delivery{ID: "d1", Event: "item.create"}
+	activity := model.Activity{ID: "a1", Action: model.CREATE, Target: model.ITEM}
+	return s.Send(context.Background(), webhook, delivery, activity)
+}
+
+func TestSendForbidden(t *testing.T) {
+	out := &bytes.Buffer{}
+	receiver := &Receiver{Secret: "secret", Output: out}
+	srv, count := newTestServer(t, "127.0.0.1:0", receiver.ServeHTTP)
+	defer srv.Close()
+
+	// A loopback address is refused when connecting
+	if _, err := send(NewSender(nil), srv.URL); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("Send() error = %v, want %v", err, ErrForbiddenAddress)
+	}
+	if *count != 0 {
+		t.Errorf("forbidden receiver got %d requests", *count)
+	}
+
+	// It is connected if it is allowed, and the request is signed
+	status, err := send(NewSender(mustParseCIDRs("127.0.0.1/32")), srv.URL)
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("Send() = %d, %v, want %d", status, err, http.StatusNoContent)
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte("received item.create d1 ")) {
+		t.Errorf("receiver wrote %q", out.String())
+	}
+}
+
+func TestSendRedirect(t *testing.T) {
+	// 127.0.0.2 is a loopback address which is not allowed
+	target, count := newTestServer(t, "127.0.0.2:0", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer target.Close()
+	srv, _ := newTestServer(t, "127.0.0.1:0", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	})
+	defer srv.Close()
+
+	// The redirect is not followed, and its response is returned as a failure
+	status, err := send(NewSender(mustParseCIDRs("127.0.0.1/32")), srv.URL)
+	if err != nil || status != http.StatusTemporaryRedirect {
+		t.Errorf("Send() = %d, %v, want %d", status, err, http.StatusTemporaryRedirect)
+	}
+	if *count != 0 {
+		t.Errorf("target of redirect got %d requests", *count)
+	}
+}
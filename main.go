@@ -1,30 +1,72 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"flag"
 	"fmt"
+	"net"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/x-color/vue-trello/interface/controller/api"
+	"github.com/x-color/vue-trello/interface/controller/api/handler"
 	"github.com/x-color/vue-trello/interface/presenter/logging"
-	"github.com/x-color/vue-trello/interface/repository/rdb"
+	"github.com/x-color/vue-trello/interface/pubsub"
+	"github.com/x-color/vue-trello/interface/webhook"
 	"github.com/x-color/vue-trello/model"
 	"github.com/x-color/vue-trello/usecase"
 )
 
 func main() {
-	dbm, err := rdb.NewDBManager()
+	if len(os.Args) > 1 && os.Args[1] == "fsck" {
+		os.Exit(runFsck(os.Args[2:], os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:], os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "webhook-receiver" {
+		os.Exit(runWebhookReceiver(os.Args[2:], os.Stdout))
+	}
+
+	demo := flag.Bool("demo", false, "keep data in memory instead of DB. Data is lost when the server stops")
+	flag.Parse()
+
+	timeout, err := requestTimeout()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	backoff, err := webhookBackoff()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	allowedNetworks, err := webhookAllowedNetworks()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	keys, err := jwtKeys()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	repos, err := newRepositories(*demo)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
 
-	// Add init tags.
-	tx := dbm.TransactionManager.BeginTransaction(false)
-	dbm.TagDBManager.Create(tx, model.Tag{ID: "0", Name: "t1", Color: model.RED})
-	dbm.TagDBManager.Create(tx, model.Tag{ID: "1", Name: "t2", Color: model.YELLOW})
-	dbm.TagDBManager.Create(tx, model.Tag{ID: "2", Name: "t3", Color: model.GREEN})
-	dbm.TagDBManager.Create(tx, model.Tag{ID: "3", Name: "t4", Color: model.BLUE})
+	// Add default tags shared by all users.
+	ctx := context.Background()
+	tx := repos.tx.BeginTransaction(ctx, false)
+	repos.tag.Create(ctx, tx, model.Tag{ID: "0", Name: "t1", Color: model.RED})
+	repos.tag.Create(ctx, tx, model.Tag{ID: "1", Name: "t2", Color: model.YELLOW})
+	repos.tag.Create(ctx, tx, model.Tag{ID: "2", Name: "t3", Color: model.GREEN})
+	repos.tag.Create(ctx, tx, model.Tag{ID: "3", Name: "t4", Color: model.BLUE})
 
 	location, err := time.LoadLocation("Asia/Tokyo")
 	if err != nil {
@@ -35,12 +77,23 @@ func main() {
 		fmt.Println(err)
 		return
 	}
+	if *demo {
+		logger.Info("Start in demo mode. Data is kept in memory")
+	}
+	bus := pubsub.NewBus()
+	repos.activity = usecase.NewDeliveringActivityRepository(repos.activity, repos.webhook, repos.delivery, &logger)
 
 	itemIntera, err := usecase.NewItemInteractor(
-		&dbm.TransactionManager,
-		&dbm.ItemDBManager,
-		&dbm.ListDBManager,
-		&dbm.TagDBManager,
+		repos.tx,
+		repos.item,
+		repos.list,
+		repos.tag,
+		repos.member,
+		repos.checklist,
+		repos.checkItem,
+		repos.comment,
+		repos.activity,
+		bus,
 		&logger,
 	)
 	if err != nil {
@@ -49,10 +102,17 @@ func main() {
 	}
 
 	listIntera, err := usecase.NewListInteractor(
-		&dbm.TransactionManager,
-		&dbm.ItemDBManager,
-		&dbm.ListDBManager,
-		&dbm.BoardDBManager,
+		repos.tx,
+		repos.item,
+		repos.list,
+		repos.board,
+		repos.member,
+		repos.checklist,
+		repos.checkItem,
+		repos.comment,
+		repos.activity,
+		repos.tag,
+		bus,
 		&logger,
 	)
 	if err != nil {
@@ -61,10 +121,19 @@ func main() {
 	}
 
 	boardIntera, err := usecase.NewBoardInteractor(
-		&dbm.TransactionManager,
-		&dbm.BoardDBManager,
-		&dbm.ListDBManager,
-		&dbm.ItemDBManager,
+		repos.tx,
+		repos.board,
+		repos.list,
+		repos.item,
+		repos.member,
+		repos.checklist,
+		repos.checkItem,
+		repos.comment,
+		repos.activity,
+		repos.tag,
+		repos.webhook,
+		repos.delivery,
+		bus,
 		&logger,
 	)
 	if err != nil {
@@ -73,8 +142,14 @@ func main() {
 	}
 
 	userIntera, err := usecase.NewUserInteractor(
-		&dbm.TransactionManager,
-		&dbm.UserDBManager,
+		repos.tx,
+		repos.user,
+		repos.session,
+		repos.token,
+		repos.member,
+		repos.tag,
+		&boardIntera,
+		bus,
 		&logger,
 	)
 	if err != nil {
@@ -83,8 +158,175 @@ func main() {
 	}
 
 	resourceIntera, err := usecase.NewResourceInteractor(
-		&dbm.TransactionManager,
-		&dbm.TagDBManager,
+		repos.tx,
+		repos.tag,
+		repos.member,
+		&logger,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	memberIntera, err := usecase.NewMemberInteractor(
+		repos.tx,
+		repos.member,
+		repos.user,
+		&logger,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	checklistIntera, err := usecase.NewChecklistInteractor(
+		repos.tx,
+		repos.checklist,
+		repos.checkItem,
+		repos.item,
+		repos.list,
+		repos.member,
+		repos.activity,
+		bus,
+		&logger,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	commentIntera, err := usecase.NewCommentInteractor(
+		repos.tx,
+		repos.comment,
+		repos.item,
+		repos.list,
+		repos.member,
+		repos.user,
+		&logger,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	activityIntera, err := usecase.NewActivityInteractor(
+		repos.tx,
+		repos.activity,
+		repos.member,
+		repos.user,
+		&logger,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	importIntera, err := usecase.NewImportInteractor(
+		repos.tx,
+		repos.board,
+		repos.list,
+		repos.item,
+		repos.tag,
+		repos.member,
+		repos.activity,
+		&logger,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	exportIntera, err := usecase.NewExportInteractor(
+		repos.tx,
+		repos.board,
+		repos.list,
+		repos.item,
+		repos.member,
+		&logger,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	tagIntera, err := usecase.NewTagInteractor(
+		repos.tx,
+		repos.tag,
+		repos.item,
+		repos.list,
+		repos.member,
+		repos.activity,
+		bus,
+		&logger,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	eventIntera, err := usecase.NewEventInteractor(
+		repos.tx,
+		repos.activity,
+		repos.member,
+		repos.user,
+		bus,
+		&logger,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	syncIntera, err := usecase.NewSyncInteractor(
+		repos.tx,
+		repos.activity,
+		repos.member,
+		repos.board,
+		repos.list,
+		repos.item,
+		repos.checkItem,
+		&boardIntera,
+		&listIntera,
+		&itemIntera,
+		&logger,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	webhookIntera, err := usecase.NewWebhookInteractor(
+		repos.tx,
+		repos.webhook,
+		repos.delivery,
+		repos.activity,
+		repos.member,
+		repos.user,
+		webhook.NewSender(allowedNetworks),
+		backoff,
+		&logger,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	bus.Handle(webhookIntera.Wake)
+	go webhookIntera.Run(context.Background())
+
+	sessionIntera, err := usecase.NewSessionInteractor(
+		repos.tx,
+		repos.session,
+		&logger,
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	tokenIntera, err := usecase.NewAccessTokenInteractor(
+		repos.tx,
+		repos.token,
+		repos.member,
 		&logger,
 	)
 	if err != nil {
@@ -98,12 +340,103 @@ func main() {
 		&boardIntera,
 		&userIntera,
 		&resourceIntera,
+		&memberIntera,
+		&checklistIntera,
+		&commentIntera,
+		&activityIntera,
+		&importIntera,
+		&exportIntera,
+		&tagIntera,
+		&eventIntera,
+		&syncIntera,
+		&webhookIntera,
+		&tokenIntera,
+		&sessionIntera,
 	)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
 
-	router := api.NewRouter(interaBox)
+	router := api.NewRouter(interaBox, keys, timeout)
 	router.Logger.Fatal(router.Start(":8080"))
 }
+
+// requestTimeout returns a deadline of each request set by REQUEST_TIMEOUT (e.g. "10s").
+// It is 30 seconds by default, and "0" disables it.
+func requestTimeout() (time.Duration, error) {
+	v := os.Getenv("REQUEST_TIMEOUT")
+	if v == "" {
+		return 30 * time.Second, nil
+	}
+	timeout, err := time.ParseDuration(v)
+	if err != nil || timeout < 0 {
+		return 0, fmt.Errorf("invalid REQUEST_TIMEOUT: %q", v)
+	}
+	return timeout, nil
+}
+
+// webhookBackoff returns a delay before the first retry of a webhook delivery set by WEBHOOK_BACKOFF (e.g. "10s").
+// It is 10 seconds by default.
+func webhookBackoff() (time.Duration, error) {
+	v := os.Getenv("WEBHOOK_BACKOFF")
+	if v == "" {
+		return 10 * time.Second, nil
+	}
+	backoff, err := time.ParseDuration(v)
+	if err != nil || backoff <= 0 {
+		return 0, fmt.Errorf("invalid WEBHOOK_BACKOFF: %q", v)
+	}
+	return backoff, nil
+}
+
+// webhookAllowedNetworks returns networks set by WEBHOOK_ALLOWED_NETWORKS as comma separated CIDRs
+// (e.g. "127.0.0.1/32,::1/128"). Webhooks can be sent to them even if they are private networks.
+// No networks are allowed by default.
+func webhookAllowedNetworks() ([]*net.IPNet, error) {
+	networks := []*net.IPNet{}
+	v := os.Getenv("WEBHOOK_ALLOWED_NETWORKS")
+	if v == "" {
+		return networks, nil
+	}
+	for _, c := range strings.Split(v, ",") {
+		_, n, err := net.ParseCIDR(strings.TrimSpace(c))
+		if err != nil {
+			return nil, fmt.Errorf("invalid WEBHOOK_ALLOWED_NETWORKS: %q", v)
+		}
+		networks = append(networks, n)
+	}
+	return networks, nil
+}
+
+// jwtKeys returns keys for JWT set by JWT_KEYS as comma separated "<kid>:<secret>" pairs
+// (e.g. "2025-02:new-secret,2025-01:old-secret"). JWT is signed by the first key and verified by any of them.
+// A random key is used if JWT_KEYS is not set, so users are signed out whenever the server restarts.
+func jwtKeys() (handler.Keys, error) {
+	v := os.Getenv("JWT_KEYS")
+	if v == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return handler.Keys{}, err
+		}
+		fmt.Println("JWT_KEYS is not set. Users are signed out when the server restarts")
+		return handler.NewKeys("random", map[string][]byte{"random": secret})
+	}
+
+	signingKID := ""
+	secrets := map[string][]byte{}
+	for _, pair := range strings.Split(v, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(kv) != 2 || kv[0] == "" || len(kv[1]) < 32 {
+			return handler.Keys{}, fmt.Errorf("invalid JWT_KEYS: each key must be \"<kid>:<secret>\" with a secret of 32 characters at least")
+		}
+		if _, ok := secrets[kv[0]]; ok {
+			return handler.Keys{}, fmt.Errorf("invalid JWT_KEYS: key %q is duplicated", kv[0])
+		}
+		if signingKID == "" {
+			signingKID = kv[0]
+		}
+		secrets[kv[0]] = []byte(kv[1])
+	}
+	return handler.NewKeys(signingKID, secrets)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/x-color/vue-trello/interface/repository/rdb"
+)
+
+// Exit codes of migrate.
+const (
+	migrateOK    = 0
+	migrateError = 1
+	migrateUsage = 2
+)
+
+const migrateUsageText = `usage: server migrate <command>
+
+commands:
+  up                apply all pending migrations
+  down [-steps n]   revert the latest n migrations (default 1)
+  status            print states of all migrations`
+
+// runMigrate changes a schema of DB by versioned migrations and prints changed versions.
+func runMigrate(args []string, output io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsageText)
+		return migrateUsage
+	}
+
+	var steps *int
+	switch args[0] {
+	case "up", "status":
+		if len(args) > 1 {
+			fmt.Fprintln(os.Stderr, migrateUsageText)
+			return migrateUsage
+		}
+	case "down":
+		flags := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps = flags.Int("steps", 1, "number of migrations to revert")
+		if err := flags.Parse(args[1:]); err != nil || flags.NArg() > 0 || *steps < 1 {
+			fmt.Fprintln(os.Stderr, migrateUsageText)
+			return migrateUsage
+		}
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsageText)
+		return migrateUsage
+	}
+
+	m, err := rdb.NewMigrator()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return migrateError
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := m.Up()
+		for _, s := range applied {
+			fmt.Fprintf(output, "applied %d %s\n", s.Version, s.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return migrateError
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(output, "no pending migrations")
+		}
+	case "down":
+		reverted, err := m.Down(*steps)
+		for _, s := range reverted {
+			fmt.Fprintf(output, "reverted %d %s\n", s.Version, s.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return migrateError
+		}
+		if len(reverted) == 0 {
+			fmt.Fprintln(output, "no applied migrations")
+		}
+	default:
+		statuses, err := m.Status()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return migrateError
+		}
+		printMigrationStatuses(output, statuses)
+	}
+
+	version, err := m.Version()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return migrateError
+	}
+	fmt.Fprintf(output, "schema version %d (latest %d)\n", version, rdb.LatestVersion())
+	return migrateOK
+}
+
+func printMigrationStatuses(output io.Writer, statuses []rdb.MigrationStatus) {
+	for _, s := range statuses {
+		state := "pending"
+		if !s.AppliedAt.IsZero() {
+			state = "applied at " + s.AppliedAt.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(output, "%4d %-24s %s\n", s.Version, s.Name, state)
+	}
+}
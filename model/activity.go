@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+// Action defines a kind of change recorded as Activity.
+type Action string
+
+// Action pattern
+const (
+	CREATE Action = "create"
+	UPDATE Action = "update"
+	MOVE   Action = "move"
+	DELETE Action = "delete"
+)
+
+// Actions defines a slice of Action.
+type Actions []Action
+
+// ACTIONS includes Action literals.
+var ACTIONS = Actions{
+	CREATE,
+	UPDATE,
+	MOVE,
+	DELETE,
+}
+
+// Target defines a kind of changed data recorded as Activity.
+type Target string
+
+// Target pattern
+const (
+	BOARD     Target = "board"
+	LIST      Target = "list"
+	ITEM      Target = "item"
+	CHECKLIST Target = "checklist"
+	CHECKITEM Target = "check_item"
+	TAG       Target = "tag"
+)
+
+// Targets defines a slice of Target.
+type Targets []Target
+
+// TARGETS includes Target literals.
+var TARGETS = Targets{
+	BOARD,
+	LIST,
+	ITEM,
+	CHECKLIST,
+	CHECKITEM,
+	TAG,
+}
+
+// Activity includes a record of change in a board.
+// Before and After are JSON snapshots of changed data. They are empty if data does not exist.
+type Activity struct {
+	ID      string
+	BoardID string
+	UserID  string
+	// UserName is not saved. It is filled when activities are listed.
+	UserName  string
+	Action    Action
+	Target    Target
+	TargetID  string
+	Before    string
+	After     string
+	CreatedAt time.Time
+}
+
+// Event returns a type of Activity as "<target>.<action>" (e.g. "item.move").
+func (a Activity) Event() string {
+	return string(a.Target) + "." + string(a.Action)
+}
+
+// Activities defines a slice of Activity
+type Activities []Activity
@@ -0,0 +1,29 @@
+package model
+
+// Problem defines a kind of broken order found by a check of ranks.
+type Problem string
+
+// Problem pattern
+const (
+	NORANK         Problem = "no rank"
+	INVALIDRANK    Problem = "invalid rank"
+	DUPLICATEDRANK Problem = "duplicated rank"
+	ORPHAN         Problem = "orphan"
+)
+
+// Anomaly includes data whose order is broken.
+// UserID is an owner of a Board including the data. ParentID is ID of a Board, List, Item
+// or Checklist including the data, or ID of an owner for a Board.
+type Anomaly struct {
+	UserID   string
+	BoardID  string
+	Target   Target
+	ID       string
+	ParentID string
+	Rank     string
+	Problem  Problem
+	Repaired bool
+}
+
+// Anomalies defines a slice of Anomaly
+type Anomalies []Anomaly
@@ -8,8 +8,11 @@ type Board struct {
 	Text   string
 	Color  Color
 	Lists  Lists
+	Rank   string
+	// Version starts at 1 and is bumped whenever the Board is updated.
+	Version int
+	// Before is not saved. It is ID of a Board put before the moved Board.
 	Before string
-	After  string
 }
 
 // Boards defines a slice of Board
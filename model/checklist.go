@@ -0,0 +1,32 @@
+package model
+
+// Checklist includes checklist data
+type Checklist struct {
+	ID         string
+	ItemID     string
+	UserID     string
+	Title      string
+	CheckItems CheckItems
+	Rank       string
+	// Before is not saved. It is ID of a Checklist put before the moved Checklist.
+	Before string
+}
+
+// Checklists defines a slice of Checklist
+type Checklists []Checklist
+
+// CheckItem includes check item data
+type CheckItem struct {
+	ID          string
+	ChecklistID string
+	ItemID      string
+	UserID      string
+	Title       string
+	Checked     bool
+	Rank        string
+	// Before is not saved. It is ID of a CheckItem put before the moved CheckItem.
+	Before string
+}
+
+// CheckItems defines a slice of CheckItem
+type CheckItems []CheckItem
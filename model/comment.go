@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Comment includes comment data
+type Comment struct {
+	ID     string
+	ItemID string
+	// UserID is an author of Comment.
+	UserID string
+	// UserName is not saved. It is filled when comments are listed.
+	UserName  string
+	Text      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Comments defines a slice of Comment
+type Comments []Comment
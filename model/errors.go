@@ -93,3 +93,74 @@ func (e ServerError) Is(target error) bool {
 	_, ok := target.(ServerError)
 	return ok
 }
+
+// ForbiddenError is occured if a user does not have permission for a content.
+type ForbiddenError struct {
+	UserID string
+	ID     string
+	Act    string
+	Err    error
+}
+
+func (e ForbiddenError) Error() string {
+	return fmt.Sprintf("%s ForbiddenError: %s. %s is not permitted", e.UserID, e.Act, e.ID)
+}
+
+// Unwrap returns a error wrapped by ForbiddenError.
+func (e ForbiddenError) Unwrap() error {
+	return e.Err
+}
+
+// Is checks target is ForbiddenError.
+func (e ForbiddenError) Is(target error) bool {
+	_, ok := target.(ForbiddenError)
+	return ok
+}
+
+// TimeoutError is occured if a request is canceled or its deadline is exceeded before it completes.
+type TimeoutError struct {
+	UserID string
+	ID     string
+	Act    string
+	Err    error
+}
+
+func (e TimeoutError) Error() string {
+	return fmt.Sprintf("%s TimeoutError: %s. %s is not completed in time. %s", e.UserID, e.Act, e.ID, e.Err)
+}
+
+// Unwrap returns a error wrapped by TimeoutError.
+func (e TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Is checks target is TimeoutError.
+func (e TimeoutError) Is(target error) bool {
+	_, ok := target.(TimeoutError)
+	return ok
+}
+
+// PreconditionFailedError is occured if a content has been changed from a version expected by a user.
+// Current is the current content. It is nil if the content was changed while it was updated.
+type PreconditionFailedError struct {
+	UserID  string
+	ID      string
+	Act     string
+	Err     error
+	Current interface{}
+}
+
+func (e PreconditionFailedError) Error() string {
+	return fmt.Sprintf("%s PreconditionFailedError: %s. %s has been changed", e.UserID, e.Act, e.ID)
+}
+
+// Unwrap returns a error wrapped by PreconditionFailedError.
+func (e PreconditionFailedError) Unwrap() error {
+	return e.Err
+}
+
+// Is checks target is PreconditionFailedError.
+func (e PreconditionFailedError) Is(target error) bool {
+	_, ok := target.(PreconditionFailedError)
+	return ok
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 // Item includes item data
 type Item struct {
 	ID     string
@@ -8,8 +10,18 @@ type Item struct {
 	Title  string
 	Text   string
 	Tags   Tags
+	// StartDate and DueDate are not set if they are zero.
+	StartDate time.Time
+	DueDate   time.Time
+	Completed bool
+	// CheckItemsDone and CheckItemsTotal are not saved. They are counted when a Board is got.
+	CheckItemsDone  int
+	CheckItemsTotal int
+	Rank            string
+	// Version is bumped whenever the Item is changed, including its Tags.
+	Version int
+	// Before is not saved. It is ID of a Item put before the moved Item.
 	Before string
-	After  string
 }
 
 // Items defines a slice of Item
@@ -7,8 +7,11 @@ type List struct {
 	UserID  string
 	Title   string
 	Items   Items
-	Before  string
-	After   string
+	Rank    string
+	// Version is bumped whenever the List is updated or moved.
+	Version int
+	// Before is not saved. It is ID of a List put before the moved List.
+	Before string
 }
 
 // Lists defines a slice of List
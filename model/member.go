@@ -0,0 +1,33 @@
+package model
+
+// Role defines a role type of board member.
+type Role string
+
+// Role pattern
+const (
+	OWNER  Role = "owner"
+	EDITOR Role = "editor"
+	VIEWER Role = "viewer"
+)
+
+// Roles defines a slice of Role.
+type Roles []Role
+
+// ROLES includes Role literals.
+var ROLES = Roles{
+	OWNER,
+	EDITOR,
+	VIEWER,
+}
+
+// Member includes board member data
+type Member struct {
+	BoardID string
+	UserID  string
+	// UserName is not saved. It is filled when members are listed.
+	UserName string
+	Role     Role
+}
+
+// Members defines a slice of Member
+type Members []Member
@@ -0,0 +1,95 @@
+package model
+
+import "strings"
+
+// RANKDIGITS includes digits of rank in ascending order of bytes.
+// Ranks are compared as strings, so any two ranks can be ordered without other data.
+const RANKDIGITS = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// RankBetween returns a rank between prev and next.
+// An empty prev means the lowest rank and an empty next means the highest rank.
+// prev must be less than next if next is not empty. Ranks never end with the lowest digit,
+// so a rank can always be put before any other rank.
+func RankBetween(prev, next string) string {
+	if next != "" {
+		// Keep a common prefix. A shorter prev is padded with the lowest digit.
+		n := 0
+		for n < len(next) && rankDigitAt(prev, n) == next[n] {
+			n++
+		}
+		if n > 0 {
+			return next[:n] + RankBetween(prevTail(prev, n), next[n:])
+		}
+	}
+
+	digitPrev := 0
+	if prev != "" {
+		digitPrev = strings.IndexByte(RANKDIGITS, prev[0])
+	}
+	digitNext := len(RANKDIGITS)
+	if next != "" {
+		digitNext = strings.IndexByte(RANKDIGITS, next[0])
+	}
+
+	if digitNext-digitPrev > 1 {
+		return string(RANKDIGITS[(digitPrev+digitNext+1)/2])
+	}
+	// Digits are consecutive, so a next digit is needed.
+	if len(next) > 1 {
+		return next[:1]
+	}
+	return string(RANKDIGITS[digitPrev]) + RankBetween(prevTail(prev, 1), "")
+}
+
+// Ranks returns n ranks in ascending order spaced evenly.
+func Ranks(n int) []string {
+	base := uint64(len(RANKDIGITS))
+
+	// Choose width of ranks to keep enough space between ranks.
+	width := 1
+	space := base
+	for space < uint64(n+1)*base && width < 10 {
+		width++
+		space *= base
+	}
+	step := space / uint64(n+1)
+
+	ranks := make([]string, n)
+	for i := range ranks {
+		v := uint64(i+1) * step
+		b := make([]byte, width)
+		for j := width - 1; j >= 0; j-- {
+			b[j] = RANKDIGITS[v%base]
+			v /= base
+		}
+		ranks[i] = strings.TrimRight(string(b), RANKDIGITS[:1])
+	}
+	return ranks
+}
+
+// ValidRank checks rank consists of RANKDIGITS and does not end with the lowest digit.
+func ValidRank(rank string) bool {
+	if rank == "" || rank[len(rank)-1] == RANKDIGITS[0] {
+		return false
+	}
+	for j := 0; j < len(rank); j++ {
+		if strings.IndexByte(RANKDIGITS, rank[j]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func rankDigitAt(rank string, i int) byte {
+	if i < len(rank) {
+		return rank[i]
+	}
+	return RANKDIGITS[0]
+}
+
+func prevTail(prev string, n int) string {
+	if n < len(prev) {
+		return prev[n:]
+	}
+	return ""
+}
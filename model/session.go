@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Session includes a sign-in of a user. A JWT is valid only while its Session exists,
+// so a user can sign out of the JWT by removing the Session.
+type Session struct {
+	ID        string
+	UserID    string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// Expired checks that Session is expired at t.
+func (s Session) Expired(t time.Time) bool {
+	return !t.Before(s.ExpiresAt)
+}
+
+// Sessions defines a slice of Session
+type Sessions []Session
@@ -0,0 +1,13 @@
+package model
+
+// Skip includes data skipped or changed by an import and its reason.
+// ID and Name are ones in imported data.
+type Skip struct {
+	Target Target
+	ID     string
+	Name   string
+	Reason string
+}
+
+// Skips defines a slice of Skip
+type Skips []Skip
@@ -1,10 +1,15 @@
 package model
 
 // Tag includes tags data
+// A Tag belongs to a Board if BoardID is not empty, otherwise it belongs to a User had UserID.
+// A Tag having neither of them is a default Tag shared by all users.
+// UserID of a Tag in a Board is ID of a user who created it.
 type Tag struct {
-	ID    string
-	Name  string
-	Color Color
+	ID      string
+	UserID  string
+	BoardID string
+	Name    string
+	Color   Color
 }
 
 // Tags defines a slice of Tag
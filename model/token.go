@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// AccessToken includes a personal access token to call API without signing in.
+// Only Hash of the token is saved, and the token itself is only returned when it is created.
+// A token can be restricted to reading (ReadOnly) and to a Board (BoardID).
+// It never expires if ExpiresAt is zero.
+type AccessToken struct {
+	ID        string
+	UserID    string
+	Name      string
+	Token     string
+	Hash      string
+	ReadOnly  bool
+	BoardID   string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// Expired checks that AccessToken is expired at t.
+func (a AccessToken) Expired(t time.Time) bool {
+	return !a.ExpiresAt.IsZero() && !t.Before(a.ExpiresAt)
+}
+
+// AccessTokens defines a slice of AccessToken
+type AccessTokens []AccessToken
@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// Webhook includes a registration to send Activities in a board to an URL.
+// Events are types of Activities sent (e.g. "item.move"). All Activities are sent if Events is empty.
+// Secret signs sent payloads. It is only returned when Webhook is created.
+type Webhook struct {
+	ID      string
+	BoardID string
+	// UserID is a user registering Webhook.
+	UserID    string
+	URL       string
+	Secret    string
+	Events    []string
+	CreatedAt time.Time
+}
+
+// Accepts checks that Activity is sent to Webhook.
+func (w Webhook) Accepts(activity Activity) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == activity.Event() {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhooks defines a slice of Webhook
+type Webhooks []Webhook
+
+// DeliveryStatus defines a state of Delivery.
+type DeliveryStatus string
+
+// DeliveryStatus pattern
+const (
+	PENDING   DeliveryStatus = "pending"
+	SUCCEEDED DeliveryStatus = "succeeded"
+	FAILED    DeliveryStatus = "failed"
+)
+
+// Delivery includes a record of sending an Activity to Webhook. A pending Delivery is attempted
+// at NextAttemptAt. ResponseStatus and Error are results of the last attempt, and ResponseStatus
+// is 0 if no response was got.
+type Delivery struct {
+	ID             string
+	WebhookID      string
+	ActivityID     string
+	Event          string
+	Status         DeliveryStatus
+	Attempts       int
+	ResponseStatus int
+	Error          string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Deliveries defines a slice of Delivery
+type Deliveries []Delivery
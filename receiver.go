@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/x-color/vue-trello/interface/webhook"
+)
+
+// runWebhookReceiver serves a stand-in of a webhook, which prints requests to output, until it fails.
+// It responds errors to the first requests if '-fail' flag is given, so that retries can be tested.
+func runWebhookReceiver(args []string, output io.Writer) int {
+	flags := flag.NewFlagSet("webhook-receiver", flag.ContinueOnError)
+	addr := flags.String("addr", "localhost:9000", "address to listen on")
+	secret := flags.String("secret", "", "secret of the webhook")
+	fail := flags.Int("fail", 0, "number of requests to fail")
+	if err := flags.Parse(args); err != nil || flags.NArg() > 0 || *secret == "" || *fail < 0 {
+		fmt.Fprintln(os.Stderr, "usage: server webhook-receiver -secret <secret> [-addr host:port] [-fail n]")
+		return 2
+	}
+
+	r := &webhook.Receiver{
+		Secret: *secret,
+		Fail:   *fail,
+		Output: output,
+	}
+	fmt.Fprintln(output, "listening on "+*addr)
+	if err := http.ListenAndServe(*addr, r); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
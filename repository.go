@@ -0,0 +1,73 @@
+package main
+
+import (
+	"github.com/x-color/vue-trello/interface/repository/memory"
+	"github.com/x-color/vue-trello/interface/repository/rdb"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// repositories includes repositories used by interactors.
+type repositories struct {
+	tx        usecase.TransactionRepository
+	item      usecase.ItemRepository
+	list      usecase.ListRepository
+	board     usecase.BoardRepository
+	user      usecase.UserRepository
+	tag       usecase.TagRepository
+	member    usecase.MemberRepository
+	checklist usecase.ChecklistRepository
+	checkItem usecase.CheckItemRepository
+	comment   usecase.CommentRepository
+	activity  usecase.ActivityRepository
+	webhook   usecase.WebhookRepository
+	delivery  usecase.DeliveryRepository
+	token     usecase.AccessTokenRepository
+	session   usecase.SessionRepository
+}
+
+// newRepositories returns repositories saving data to DB.
+// They keep data in memory instead if demo is true, so no DB is needed.
+func newRepositories(demo bool) (repositories, error) {
+	if demo {
+		dbm := memory.NewDBManager()
+		return repositories{
+			tx:        &dbm.TransactionManager,
+			item:      &dbm.ItemDBManager,
+			list:      &dbm.ListDBManager,
+			board:     &dbm.BoardDBManager,
+			user:      &dbm.UserDBManager,
+			tag:       &dbm.TagDBManager,
+			member:    &dbm.MemberDBManager,
+			checklist: &dbm.ChecklistDBManager,
+			checkItem: &dbm.CheckItemDBManager,
+			comment:   &dbm.CommentDBManager,
+			activity:  &dbm.ActivityDBManager,
+			webhook:   &dbm.WebhookDBManager,
+			delivery:  &dbm.DeliveryDBManager,
+			token:     &dbm.AccessTokenDBManager,
+			session:   &dbm.SessionDBManager,
+		}, nil
+	}
+
+	dbm, err := rdb.NewDBManager()
+	if err != nil {
+		return repositories{}, err
+	}
+	return repositories{
+		tx:        &dbm.TransactionManager,
+		item:      &dbm.ItemDBManager,
+		list:      &dbm.ListDBManager,
+		board:     &dbm.BoardDBManager,
+		user:      &dbm.UserDBManager,
+		tag:       &dbm.TagDBManager,
+		member:    &dbm.MemberDBManager,
+		checklist: &dbm.ChecklistDBManager,
+		checkItem: &dbm.CheckItemDBManager,
+		comment:   &dbm.CommentDBManager,
+		activity:  &dbm.ActivityDBManager,
+		webhook:   &dbm.WebhookDBManager,
+		delivery:  &dbm.DeliveryDBManager,
+		token:     &dbm.AccessTokenDBManager,
+		session:   &dbm.SessionDBManager,
+	}, nil
+}
@@ -0,0 +1,215 @@
+#!/bin/bash
+
+set -eu
+
+# csrf prints CSRF token issued at sign-in from a cookie file.
+csrf() { awk '$6 == "XSRF-TOKEN" { print $7 }' "$1"; }
+
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie.file
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie2.file
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testmember", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testmember", "password":"pass"}' -c /tmp/member_cookie.file
+
+# Own board of testuser, and a board of testmember shared with testuser
+curl -s -X POST localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "own", "color":"red"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+BID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/lists \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID1'", "title": "own_list"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+LID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/items \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"list_id":"'$LID1'", "title": "own_item"}' \
+-b /tmp/cookie.file
+
+curl -s -X POST localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/member_cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "shared", "color":"blue"}' \
+-b /tmp/member_cookie.file \
+| tee /tmp/tmp.file
+
+BID2=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/boards/$BID2/members \
+-H "X-XSRF-TOKEN:$(csrf /tmp/member_cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"name": "testuser", "role":"editor"}' \
+-b /tmp/member_cookie.file
+
+curl -s -X POST localhost:8080/api/lists \
+-H "X-XSRF-TOKEN:$(csrf /tmp/member_cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID2'", "title": "shared_list"}' \
+-b /tmp/member_cookie.file \
+| tee /tmp/tmp.file
+
+LID2=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/tags \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "mine", "color": "blue"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+TID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/items \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"list_id":"'$LID2'", "title": "tagged", "tags": ["0", "'$TID1'"]}' \
+-b /tmp/cookie.file \
+> /dev/null
+
+curl -s -X POST localhost:8080/api/tokens \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"name": "full"}' \
+-b /tmp/cookie.file \
+> /tmp/tmp.file
+
+FULL=$(cat /tmp/tmp.file | jq .token -r)
+
+echo
+echo "## Rename ##"
+echo
+
+# Invalid names: another user has it (409), empty (400)
+curl -s -X PATCH localhost:8080/api/user \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"name": "testmember"}' \
+-b /tmp/cookie.file
+
+curl -s -X PATCH localhost:8080/api/user \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"name": ""}' \
+-b /tmp/cookie.file
+
+# Tokens can not manage an account (403)
+curl -s -X PATCH localhost:8080/api/user \
+-H "Authorization: Bearer $FULL" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"name": "renamed"}'
+
+curl -s -X PATCH localhost:8080/api/user \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"name": "renamed"}' \
+-b /tmp/cookie.file
+
+curl -s -X GET localhost:8080/api/boards/$BID2/members \
+-H "X-XSRF-TOKEN:$(csrf /tmp/member_cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/member_cookie.file \
+| jq -c '[.members[].name]'
+
+echo
+echo "## Change password ##"
+echo
+
+# Wrong current password (403)
+curl -s -X PATCH localhost:8080/api/user/password \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"password": "wrong", "new_password": "newpass"}' \
+-b /tmp/cookie.file
+
+curl -s -X PATCH localhost:8080/api/user/password \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"password": "pass", "new_password": "newpass"}' \
+-b /tmp/cookie.file \
+-c /tmp/cookie.file \
+-w '%{http_code}\n'
+
+# Other sessions are revoked (401), but the session of the request is renewed
+curl -s -X GET localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie2.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie2.file
+
+curl -s -X GET localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file \
+| jq -c '[.boards[].title]'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"renamed", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"renamed", "password":"newpass"}' -c /tmp/cookie2.file
+
+echo
+echo "## Delete account ##"
+echo
+
+# Wrong password (403)
+curl -s -X DELETE localhost:8080/api/user \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"password": "pass"}' \
+-b /tmp/cookie.file
+
+curl -s -X DELETE localhost:8080/api/user \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"password": "newpass"}' \
+-b /tmp/cookie.file \
+-w '%{http_code}\n'
+
+# Sessions and tokens of the account can not be used (401)
+curl -s -X GET localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie2.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie2.file
+
+curl -s -X GET localhost:8080/api/boards \
+-H "Authorization: Bearer $FULL" \
+-H 'Content-Type:application/json; charset=UTF-8'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"renamed", "password":"newpass"}'
+
+# The account left the shared board, and its tags are detached from items there
+curl -s -X GET localhost:8080/api/boards/$BID2/members \
+-H "X-XSRF-TOKEN:$(csrf /tmp/member_cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/member_cookie.file \
+| jq -c '[.members[].name]'
+
+curl -s -X GET localhost:8080/api/boards/$BID2 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/member_cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/member_cookie.file \
+| jq -c '[.lists[].items[] | [.title, .tags]]'
+
+# The name can be used by a new account, which has no boards
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"renamed", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"renamed", "password":"pass"}' -c /tmp/cookie.file
+
+curl -s -X GET localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file \
+| jq -c '[.boards[].title]'
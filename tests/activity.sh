@@ -0,0 +1,80 @@
+#!/bin/bash
+
+set -eu
+
+# csrf prints CSRF token issued at sign-in from a cookie file.
+csrf() { awk '$6 == "XSRF-TOKEN" { print $7 }' "$1"; }
+
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie.file
+
+# Create
+
+curl -s -X POST localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "audited", "color":"red"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+BID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/lists \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID1'", "title": "first_list"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+LID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/items \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"list_id":"'$LID1'", "title": "first_item", "text": "", "tags": []}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+IID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+# Update and Delete
+
+curl -s -X PATCH localhost:8080/api/items/$IID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "renamed_item", "text": "", "tags": []}' \
+-b /tmp/cookie.file
+
+curl -s -X PATCH localhost:8080/api/boards/$BID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "renamed_board", "text": "", "color":"blue"}' \
+-b /tmp/cookie.file
+
+curl -s -X DELETE localhost:8080/api/items/$IID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+echo 
+echo "## Activity (page 1) ##"
+echo 
+
+curl -s "localhost:8080/api/boards/$BID1/activity?limit=3" \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+NEXT=$(cat /tmp/tmp.file | tail -1 | jq .next -r)
+
+echo 
+echo "## Activity (page 2) ##"
+echo 
+
+curl -s "localhost:8080/api/boards/$BID1/activity?limit=3&cursor=$NEXT" \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
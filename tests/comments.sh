@@ -0,0 +1,122 @@
+#!/bin/bash
+
+set -eu
+
+# csrf prints CSRF token issued at sign-in from a cookie file.
+csrf() { awk '$6 == "XSRF-TOKEN" { print $7 }' "$1"; }
+
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie.file
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testmember", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testmember", "password":"pass"}' -c /tmp/member_cookie.file
+
+# Create
+
+curl -s -X POST localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "commented", "color":"red"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+BID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/lists \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID1'", "title": "first_list"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+LID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/items \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"list_id":"'$LID1'", "title": "first_item", "text": "", "tags": []}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+IID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/boards/$BID1/members \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"name": "testmember", "role":"editor"}' \
+-b /tmp/cookie.file
+
+for i in 1 2 3; do
+  curl -s -X POST localhost:8080/api/items/$IID1/comments \
+  -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+  -H 'Content-Type:application/json; charset=UTF-8' \
+  -d '{"text": "comment '$i'"}' \
+  -b /tmp/cookie.file \
+  | tee /tmp/tmp.file
+done
+
+CID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+echo 
+echo "## Comments (page 1) ##"
+echo 
+
+curl -s "localhost:8080/api/items/$IID1/comments?limit=2" \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+echo 
+echo "## Comments (page 2) ##"
+echo 
+
+curl -s "localhost:8080/api/items/$IID1/comments?offset=2&limit=2" \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+echo 
+echo "## Edit by other member (403) ##"
+echo 
+
+curl -s -X PATCH localhost:8080/api/items/$IID1/comments/$CID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/member_cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"text": "edited"}' \
+-b /tmp/member_cookie.file
+
+echo 
+echo "## Edit by author ##"
+echo 
+
+curl -s -X PATCH localhost:8080/api/items/$IID1/comments/$CID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"text": "edited"}' \
+-b /tmp/cookie.file
+
+echo 
+echo "## Delete by owner ##"
+echo 
+
+curl -s -X DELETE localhost:8080/api/items/$IID1/comments/$CID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+echo 
+echo "## Delete item (comments are removed) ##"
+echo 
+
+curl -s -X DELETE localhost:8080/api/items/$IID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+curl -s localhost:8080/api/items/$IID1/comments \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
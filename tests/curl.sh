@@ -2,6 +2,9 @@
 
 set -eu
 
+# csrf prints CSRF token issued at sign-in from a cookie file.
+csrf() { awk '$6 == "XSRF-TOKEN" { print $7 }' "$1"; }
+
 echo "#############################################"
 echo "############       Start        #############"
 echo "#############################################"
@@ -23,11 +26,11 @@ echo "############        Get         #############"
 echo "#############################################"
 
 echo "----Get Resources----"
-curl -s -i localhost:8080/api/resources -H 'X-XSRF-TOKEN:csrf' -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file
+curl -s -i localhost:8080/api/resources -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file
 echo ""
 
 echo "----Get Boards----"
-curl -s -i localhost:8080/api/boards -H 'X-XSRF-TOKEN:csrf' -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file
+curl -s -i localhost:8080/api/boards -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file
 echo ""
 
 echo "#############################################"
@@ -36,7 +39,7 @@ echo "#############################################"
 
 echo "----Create Board----"
 curl -s -i -X POST localhost:8080/api/boards \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"title": "test", "color":"red"}' \
 -b /tmp/cookie.file \
@@ -47,7 +50,7 @@ BID=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
 
 echo "----Create List----"
 curl -s -i -X POST localhost:8080/api/lists \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"board_id":"'$BID'", "title": "list"}' \
 -b /tmp/cookie.file \
@@ -58,7 +61,7 @@ LID=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
 
 echo "----Create Item----"
 curl -s -i -X POST localhost:8080/api/items \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"list_id":"'$LID'", "title": "item", "text": "hahaha", "tags":[]}' \
 -b /tmp/cookie.file \
@@ -67,7 +70,7 @@ echo ""
 
 echo "----Create List----"
 curl -s -i -X POST localhost:8080/api/lists \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"board_id":"'$BID'", "title": "list"}' \
 -b /tmp/cookie.file \
@@ -78,7 +81,7 @@ LID=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
 
 echo "----Create Item----"
 curl -s -i -X POST localhost:8080/api/items \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"list_id":"'$LID'", "title": "item", "text": "hahaha", "tags":[]}' \
 -b /tmp/cookie.file \
@@ -87,7 +90,7 @@ echo ""
 
 echo "----Create Item----"
 curl -s -i -X POST localhost:8080/api/items \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"list_id":"'$LID'", "title": "item", "text": "hahaha", "tags":[]}' \
 -b /tmp/cookie.file \
@@ -102,7 +105,7 @@ echo "#############################################"
 
 echo "----Get Board----"
 curl -s -i localhost:8080/api/boards/$BID \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -b /tmp/cookie.file
 echo ""
@@ -113,7 +116,7 @@ echo "#############################################"
 
 echo "----Update Board----"
 curl -s -i -X PATCH localhost:8080/api/boards/$BID \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"title": "edited title", "color":"blue", "text":"Additional text"}' \
 -b /tmp/cookie.file
@@ -121,7 +124,7 @@ echo ""
 
 echo "----Update List----"
 curl -s -i -X PATCH localhost:8080/api/lists/$LID \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"board_id":"'$BID'", "title": "edited list"}' \
 -b /tmp/cookie.file
@@ -129,7 +132,7 @@ echo ""
 
 echo "----Update Item----"
 curl -s -i -X PATCH localhost:8080/api/items/$IID \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"list_id":"'$LID'", "title": "edited item", "text": "fofofo", "tags":["1","2"]}' \
 -b /tmp/cookie.file
@@ -141,7 +144,7 @@ echo "#############################################"
 
 echo "----Get Boards----"
 curl -s -i localhost:8080/api/boards \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -b /tmp/cookie.file
 echo ""
@@ -150,7 +153,7 @@ ID=$(cat /tmp/tmp.file | tail -1 | jq .boards[0].id -r)
 
 echo "----Get Board----"
 curl -s -i localhost:8080/api/boards/$BID \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -b /tmp/cookie.file
 echo ""
@@ -161,21 +164,21 @@ echo "#############################################"
 
 echo "----Delete Item----"
 curl -s -i -X DELETE localhost:8080/api/items/$IID \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -b /tmp/cookie.file
 echo ""
 
 echo "----Delete List----"
 curl -s -i -X DELETE localhost:8080/api/lists/$LID \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -b /tmp/cookie.file
 echo ""
 
 echo "----Delete Board----"
 curl -s -i -X DELETE localhost:8080/api/boards/$BID \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -b /tmp/cookie.file
 echo ""
@@ -186,7 +189,7 @@ echo "#############################################"
 
 echo "----Get Boards----"
 curl -s -i localhost:8080/api/boards \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -b /tmp/cookie.file
 echo ""
@@ -0,0 +1,76 @@
+#!/bin/bash
+
+set -eu
+
+# csrf prints CSRF token issued at sign-in from a cookie file.
+csrf() { awk '$6 == "XSRF-TOKEN" { print $7 }' "$1"; }
+
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie.file
+
+# Create
+
+curl -s -X POST localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "exported", "text": "board text", "color":"green"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+BID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/lists \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID1'", "title": "first_list"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+LID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/items \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"list_id":"'$LID1'", "title": "first_item", "text": "line1\nline2, \"quoted\"", "tags": ["0", "2"], "due_date": "2020-01-10T00:00:00Z"}' \
+-b /tmp/cookie.file
+
+curl -s -X POST localhost:8080/api/items \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"list_id":"'$LID1'", "title": "second_item", "text": "", "tags": [], "completed": true}' \
+-b /tmp/cookie.file
+
+echo
+
+# Export
+
+for FORMAT in csv markdown json; do
+curl -s "localhost:8080/api/boards/$BID1/export?format=$FORMAT" \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+echo
+done
+
+# Import exported JSON into a new board
+
+curl -s -X POST 'localhost:8080/api/boards/import?format=json' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d @/tmp/tmp.file \
+-b /tmp/cookie.file \
+| tee /tmp/tmp2.file
+
+echo
+
+BID2=$(cat /tmp/tmp2.file | tail -1 | jq .board.id -r)
+
+curl -s "localhost:8080/api/boards/$BID2/export?format=json" \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+echo
@@ -0,0 +1,73 @@
+#!/bin/bash
+
+set -eu
+
+# csrf prints CSRF token issued at sign-in from a cookie file.
+csrf() { awk '$6 == "XSRF-TOKEN" { print $7 }' "$1"; }
+
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie.file
+
+# Import a board exported from Trello
+
+curl -s -X POST 'localhost:8080/api/boards/import?format=trello' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{
+  "id": "b1", "name": "from trello", "desc": "imported", "prefs": {"background": "purple"},
+  "labels": [
+    {"id": "lb1", "name": "bug", "color": "red"},
+    {"id": "lb2", "name": "idea", "color": "sky_dark"},
+    {"id": "lb3", "name": "misc", "color": "purple"},
+    {"id": "lb4", "name": "later", "color": "black"},
+    {"id": "lb5", "name": "", "color": "red"}
+  ],
+  "lists": [
+    {"id": "l2", "name": "doing", "closed": false, "pos": 2048},
+    {"id": "l1", "name": "todo", "closed": false, "pos": 1024},
+    {"id": "l3", "name": "old", "closed": true, "pos": 4096}
+  ],
+  "cards": [
+    {"id": "c2", "idList": "l1", "name": "second", "desc": "", "idLabels": ["lb2", "lb3", "lb4"], "closed": false, "pos": 200},
+    {"id": "c1", "idList": "l1", "name": "first", "desc": "text", "idLabels": ["lb1", "lb5"], "closed": false, "pos": 100,
+     "start": "2020-01-01T00:00:00.000Z", "due": "2020-01-10T00:00:00.000Z", "dueComplete": true},
+    {"id": "c3", "idList": "l2", "name": "archived", "desc": "", "idLabels": [], "closed": true, "pos": 100},
+    {"id": "c4", "idList": "l3", "name": "in archived list", "desc": "", "idLabels": [], "closed": false, "pos": 100}
+  ],
+  "checklists": [
+    {"id": "cl1", "idCard": "c1", "name": "steps", "pos": 1,
+     "checkItems": [{"id": "ci2", "name": "two", "state": "incomplete", "pos": 2}, {"id": "ci1", "name": "one", "state": "complete", "pos": 1}]}
+  ]
+}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+echo
+
+BID1=$(cat /tmp/tmp.file | tail -1 | jq .board.id -r)
+
+curl -s localhost:8080/api/boards/$BID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+echo
+
+# Each label is created as a tag of the board
+curl -s localhost:8080/api/resources \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file \
+| jq -c '[.tags[] | select(.board_id == "'$BID1'") | [.title, .color]]'
+
+# Unsupported format
+
+curl -s -X POST 'localhost:8080/api/boards/import?format=asana' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{}' \
+-b /tmp/cookie.file
+
+echo
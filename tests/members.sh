@@ -0,0 +1,107 @@
+#!/bin/bash
+
+set -eu
+
+# csrf prints CSRF token issued at sign-in from a cookie file.
+csrf() { awk '$6 == "XSRF-TOKEN" { print $7 }' "$1"; }
+
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie.file
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testmember", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testmember", "password":"pass"}' -c /tmp/member_cookie.file
+
+# Create
+
+curl -s -X POST localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "shared", "color":"red"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+BID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/lists \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID1'", "title": "first_list"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+LID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+# Invite
+
+curl -s -X POST localhost:8080/api/boards/$BID1/members \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"name": "testmember", "role":"viewer"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+MID=$(cat /tmp/tmp.file | tail -1 | jq .user_id -r)
+
+echo 
+echo "## Members ##"
+echo 
+
+curl -s localhost:8080/api/boards/$BID1/members \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file \
+| jq .
+
+echo 
+echo "## Viewer can not create item (403) ##"
+echo 
+
+curl -s -X POST localhost:8080/api/items \
+-H "X-XSRF-TOKEN:$(csrf /tmp/member_cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"list_id":"'$LID1'", "title": "member_item", "text": "hahaha", "tags":[]}' \
+-b /tmp/member_cookie.file \
+
+# Change role
+
+curl -s -X PATCH localhost:8080/api/boards/$BID1/members/$MID \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"role":"editor"}' \
+-b /tmp/cookie.file \
+
+curl -s -X POST localhost:8080/api/items \
+-H "X-XSRF-TOKEN:$(csrf /tmp/member_cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"list_id":"'$LID1'", "title": "member_item", "text": "hahaha", "tags":[]}' \
+-b /tmp/member_cookie.file \
+
+echo 
+echo "## Shared board ##"
+echo 
+
+curl -s localhost:8080/api/boards/$BID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/member_cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/member_cookie.file \
+| jq .
+
+# Remove
+
+curl -s -X DELETE localhost:8080/api/boards/$BID1/members/$MID \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file \
+
+echo 
+echo "## Removed ##"
+echo 
+
+curl -s localhost:8080/api/boards/$BID1/members \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file \
+| jq .
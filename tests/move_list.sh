@@ -2,19 +2,22 @@
 
 set -eu
 
+# csrf prints CSRF token issued at sign-in from a cookie file.
+csrf() { awk '$6 == "XSRF-TOKEN" { print $7 }' "$1"; }
+
 
 curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}'
 
 curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie.file
 
-curl -s localhost:8080/api/resources -H 'X-XSRF-TOKEN:csrf' -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file
+curl -s localhost:8080/api/resources -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file
 
-curl -s localhost:8080/api/boards -H 'X-XSRF-TOKEN:csrf' -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file
+curl -s localhost:8080/api/boards -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file
 
 # Create
 
 curl -s -X POST localhost:8080/api/boards \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"title": "first", "color":"red"}' \
 -b /tmp/cookie.file \
@@ -24,7 +27,7 @@ BID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
 
 # Create lists
 curl -s -X POST localhost:8080/api/lists \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"board_id":"'$BID1'", "title": "first_list"}' \
 -b /tmp/cookie.file \
@@ -33,7 +36,7 @@ curl -s -X POST localhost:8080/api/lists \
 LID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
 
 curl -s -X POST localhost:8080/api/lists \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"board_id":"'$BID1'", "title": "second_list"}' \
 -b /tmp/cookie.file \
@@ -42,7 +45,7 @@ curl -s -X POST localhost:8080/api/lists \
 LID2=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
 
 curl -s -X POST localhost:8080/api/lists \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"board_id":"'$BID1'", "title": "first_list"}' \
 -b /tmp/cookie.file \
@@ -52,19 +55,19 @@ LID3=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
 
 # Create items
 curl -s -X POST localhost:8080/api/items \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"list_id":"'$LID1'", "title": "first_item", "text": "hahaha", "tags":[]}' \
 -b /tmp/cookie.file \
 
 curl -s -X POST localhost:8080/api/items \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"list_id":"'$LID2'", "title": "fourth_item", "text": "hahaha", "tags":[]}' \
 -b /tmp/cookie.file \
 
 curl -s -X POST localhost:8080/api/items \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"list_id":"'$LID3'", "title": "first_item", "text": "hahaha", "tags":[]}' \
 -b /tmp/cookie.file \
@@ -77,7 +80,7 @@ echo "## Show ##"
 echo 
 
 curl -s localhost:8080/api/boards/$BID1 \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -b /tmp/cookie.file \
 | jq .
@@ -85,7 +88,7 @@ curl -s localhost:8080/api/boards/$BID1 \
 # Move
 
 curl -s -X PATCH localhost:8080/api/lists/$LID2/move \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -d '{"board_id":"'$BID1'", "before": "'$LID3'"}' \
 -b /tmp/cookie.file \
@@ -95,7 +98,7 @@ echo "## Moved ##"
 echo 
 
 curl -s localhost:8080/api/boards/$BID1 \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -b /tmp/cookie.file \
 | jq .
@@ -103,7 +106,7 @@ curl -s localhost:8080/api/boards/$BID1 \
 # Delete
 
 curl -s -X DELETE localhost:8080/api/lists/$LID2 \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -b /tmp/cookie.file \
 
@@ -112,7 +115,7 @@ echo "## Deleted ##"
 echo 
 
 curl -s localhost:8080/api/boards/$BID1 \
--H 'X-XSRF-TOKEN:csrf' \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
 -H 'Content-Type:application/json; charset=UTF-8' \
 -b /tmp/cookie.file \
 | jq .
@@ -0,0 +1,64 @@
+#!/bin/bash
+
+set -eu
+
+# csrf prints CSRF token issued at sign-in from a cookie file.
+csrf() { awk '$6 == "XSRF-TOKEN" { print $7 }' "$1"; }
+
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}'
+
+# Sign in on two devices
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie.file
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie2.file
+
+curl -s -X GET localhost:8080/api/sessions \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file \
+| jq -c '[.sessions[].current]'
+
+# Token of a signed out session is rejected even if it was kept (401)
+curl -s -X GET localhost:8080/auth/signout -b /tmp/cookie.file
+
+curl -s -X GET localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+curl -s -X GET localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie2.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie2.file
+
+# Token signed by an unknown key is rejected (401)
+b64url() { openssl base64 -A | tr '+/' '-_' | tr -d '='; }
+HEADER=$(echo -n '{"alg":"HS256","typ":"JWT"}' | b64url)
+SID=$(curl -s -X GET localhost:8080/api/sessions -H "X-XSRF-TOKEN:$(csrf /tmp/cookie2.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie2.file | jq '.sessions[0].id' -r)
+PAYLOAD=$(echo -n '{"jti":"'$SID'","exp":4102444800}' | b64url)
+SIGN=$(echo -n "$HEADER.$PAYLOAD" | openssl dgst -sha256 -hmac secret -binary | b64url)
+
+curl -s -X GET localhost:8080/api/boards \
+-H 'X-XSRF-TOKEN:csrf' \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-H "Cookie: token=$HEADER.$PAYLOAD.$SIGN"
+
+# Sign out of all devices
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie.file
+
+curl -s -X DELETE localhost:8080/api/sessions \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie2.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie2.file \
+-w '%{http_code}\n'
+
+curl -s -X GET localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+curl -s -X GET localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie2.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie2.file
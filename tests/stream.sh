@@ -0,0 +1,66 @@
+#!/bin/bash
+
+set -eu
+
+# csrf prints CSRF token issued at sign-in from a cookie file.
+csrf() { awk '$6 == "XSRF-TOKEN" { print $7 }' "$1"; }
+
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie.file
+
+curl -s -X POST localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "first", "color":"red"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+BID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+# Stream changes in the background
+curl -s -N -i --max-time 3 localhost:8080/api/boards/$BID1/stream -b /tmp/cookie.file > /tmp/stream.file &
+sleep 1
+
+curl -s -X POST localhost:8080/api/lists \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID1'", "title": "first_list"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+LID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/items \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"list_id":"'$LID1'", "title": "first_item", "text": "hahaha", "tags":[]}' \
+-b /tmp/cookie.file
+
+wait
+cat /tmp/stream.file
+
+# Resume changes after the first event
+EID1=$(grep '^id: ' /tmp/stream.file | head -1 | cut -d' ' -f2 | tr -d '\r')
+
+curl -s -N --max-time 1 localhost:8080/api/boards/$BID1/stream \
+-H "Last-Event-ID:$EID1" \
+-b /tmp/cookie.file || true
+
+curl -s -N --max-time 1 "localhost:8080/api/boards/$BID1/stream?cursor=$EID1" -b /tmp/cookie.file || true
+
+# Stream with an unknown cursor
+curl -s -i -N --max-time 1 "localhost:8080/api/boards/$BID1/stream?cursor=unknown" -b /tmp/cookie.file || true
+
+# Deletion of the board ends the stream
+curl -s -N --max-time 3 localhost:8080/api/boards/$BID1/stream -b /tmp/cookie.file > /tmp/stream.file &
+sleep 1
+
+curl -s -X DELETE localhost:8080/api/boards/$BID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+wait
+cat /tmp/stream.file
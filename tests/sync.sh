@@ -0,0 +1,158 @@
+#!/bin/bash
+
+set -eu
+
+# csrf prints CSRF token issued at sign-in from a cookie file.
+csrf() { awk '$6 == "XSRF-TOKEN" { print $7 }' "$1"; }
+
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie.file
+
+curl -s -X POST localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "first", "color":"red"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+BID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/lists \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID1'", "title": "first_list"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+LID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+# Get all data first
+curl -s localhost:8080/api/sync -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+CURSOR=$(cat /tmp/tmp.file | tail -1 | jq .cursor -r)
+
+# Apply operations made offline. A created list is referred by an ID given by the client.
+curl -s -X POST localhost:8080/api/sync \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"operations": [
+  {"action": "create", "target": "list", "data": {"id": "offline-list", "board_id": "'$BID1'", "title": "second_list"}},
+  {"action": "create", "target": "item", "data": {"id": "offline-item", "list_id": "offline-list", "title": "first_item", "text": "", "tags": []}},
+  {"action": "create", "target": "item", "data": {"id": "offline-item2", "list_id": "offline-list", "title": "second_item", "text": "", "tags": []}},
+  {"action": "move", "target": "item", "version": 1, "data": {"id": "offline-item2", "list_id": "offline-list", "before": ""}},
+  {"action": "update", "target": "list", "version": 1, "data": {"id": "'$LID1'", "title": "renamed_list"}},
+  {"action": "update", "target": "list", "version": 1, "data": {"id": "'$LID1'", "title": "conflicted_list"}},
+  {"action": "delete", "target": "item", "data": {"id": "unknown"}},
+  {"action": "archive", "target": "board", "data": {"id": "'$BID1'"}}
+]}' \
+-b /tmp/cookie.file
+
+# Get changes since the cursor
+curl -s "localhost:8080/api/sync?since=$CURSOR" -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+CURSOR=$(cat /tmp/tmp.file | tail -1 | jq .cursor -r)
+
+# Deleted data is responded as tombstones
+curl -s -X DELETE localhost:8080/api/lists/$LID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+curl -s "localhost:8080/api/sync?since=$CURSOR" -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+CURSOR=$(cat /tmp/tmp.file | tail -1 | jq .cursor -r)
+
+# No changes
+curl -s "localhost:8080/api/sync?since=$CURSOR" -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file
+
+# Unknown cursor
+curl -s "localhost:8080/api/sync?since=unknown" -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file
+
+# Deleted board is not in boards
+curl -s -X DELETE localhost:8080/api/boards/$BID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+curl -s "localhost:8080/api/sync?since=$CURSOR" -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file
+
+# Items moved to a board of another user are tombstones, and other items there are not responded
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testmember", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testmember", "password":"pass"}' -c /tmp/member_cookie.file
+
+curl -s -X POST localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "shared", "color":"blue"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+BID2=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/lists \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID2'", "title": "shared_list"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+LID2=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/items \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"list_id":"'$LID2'", "title": "moved_out"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+IID2=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/boards/$BID2/members \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"name": "testmember", "role":"editor"}' \
+-b /tmp/cookie.file
+
+curl -s -X POST localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/member_cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "private", "color":"green"}' \
+-b /tmp/member_cookie.file \
+| tee /tmp/tmp.file
+
+BID3=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/lists \
+-H "X-XSRF-TOKEN:$(csrf /tmp/member_cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID3'", "title": "private_list"}' \
+-b /tmp/member_cookie.file \
+| tee /tmp/tmp.file
+
+LID3=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/items \
+-H "X-XSRF-TOKEN:$(csrf /tmp/member_cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"list_id":"'$LID3'", "title": "secret"}' \
+-b /tmp/member_cookie.file
+
+curl -s "localhost:8080/api/sync" -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file \
+> /tmp/tmp.file
+
+CURSOR=$(cat /tmp/tmp.file | tail -1 | jq .cursor -r)
+
+curl -s -X PATCH localhost:8080/api/items/$IID2/move \
+-H "X-XSRF-TOKEN:$(csrf /tmp/member_cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"list_id":"'$LID3'", "before": ""}' \
+-b /tmp/member_cookie.file \
+> /dev/null
+
+curl -s "localhost:8080/api/sync?since=$CURSOR" -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file
@@ -0,0 +1,126 @@
+#!/bin/bash
+
+set -eu
+
+# csrf prints CSRF token issued at sign-in from a cookie file.
+csrf() { awk '$6 == "XSRF-TOKEN" { print $7 }' "$1"; }
+
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie.file
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"otheruser", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"otheruser", "password":"pass"}' -c /tmp/cookie2.file
+
+# Create
+
+curl -s -X POST localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "tagged", "color":"red"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+BID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/lists \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID1'", "title": "first_list"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+LID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+# Create a tag in the board and a tag for the user
+
+curl -s -X POST localhost:8080/api/tags \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID1'", "title": "bug", "color": "red"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+TID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/tags \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "mine", "color": "blue"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+TID2=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+# Invalid color and a board of other user
+
+curl -s -X POST localhost:8080/api/tags \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "invalid", "color": "pink"}' \
+-b /tmp/cookie.file
+
+curl -s -X POST localhost:8080/api/tags \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie2.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID1'", "title": "intruder", "color": "red"}' \
+-b /tmp/cookie2.file
+
+# Attach tags
+
+curl -s -X POST localhost:8080/api/items \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"list_id":"'$LID1'", "title": "first_item", "text": "", "tags": ["0", "'$TID1'", "'$TID2'"]}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+IID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+# Resources of each user
+
+curl -s localhost:8080/api/resources \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+curl -s localhost:8080/api/resources \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie2.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie2.file
+
+# Update, and delete a default tag
+
+curl -s -X PATCH localhost:8080/api/tags/$TID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "defect", "color": "yellow"}' \
+-b /tmp/cookie.file
+
+curl -s -X DELETE localhost:8080/api/tags/0 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+# Delete and detach
+
+curl -s -X DELETE localhost:8080/api/tags/$TID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+curl -s localhost:8080/api/boards/$BID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+# Detached item is recorded as updated
+curl -s localhost:8080/api/boards/$BID1/activity \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file \
+| jq -c '.activities[0] | [.action, .target, (.before.Tags | length), (.after.Tags | length)]'
+
+echo
@@ -0,0 +1,157 @@
+#!/bin/bash
+
+set -eu
+
+# csrf prints CSRF token issued at sign-in from a cookie file.
+csrf() { awk '$6 == "XSRF-TOKEN" { print $7 }' "$1"; }
+
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie.file
+
+curl -s -X POST localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "first", "color":"red"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+BID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "second", "color":"blue"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+BID2=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+# Create tokens. Tokens are only responded here (shortened in this output)
+curl -s -X POST localhost:8080/api/tokens \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"name": "full"}' \
+-b /tmp/cookie.file \
+> /tmp/tmp.file
+jq -c '.token |= .[0:4]' /tmp/tmp.file
+
+FULL=$(cat /tmp/tmp.file | jq .token -r)
+
+curl -s -X POST localhost:8080/api/tokens \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"name": "reader", "read_only": true, "expires_at": "2099-01-01T00:00:00Z"}' \
+-b /tmp/cookie.file \
+> /tmp/tmp.file
+jq -c '.token |= .[0:4]' /tmp/tmp.file
+
+READER=$(cat /tmp/tmp.file | jq .token -r)
+
+curl -s -X POST localhost:8080/api/tokens \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"name": "first board", "board_id": "'$BID1'"}' \
+-b /tmp/cookie.file \
+> /tmp/tmp.file
+jq -c '.token |= .[0:4]' /tmp/tmp.file
+
+BOARD=$(cat /tmp/tmp.file | jq .token -r)
+
+curl -s -X POST localhost:8080/api/tokens \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"name": "short", "expires_at": "'$(date -u -d '+2 seconds' +%Y-%m-%dT%H:%M:%SZ)'"}' \
+-b /tmp/cookie.file \
+> /tmp/tmp.file
+
+SHORT=$(cat /tmp/tmp.file | jq .token -r)
+TID4=$(cat /tmp/tmp.file | jq .id -r)
+
+# Invalid tokens (400): no name, expired
+curl -s -X POST localhost:8080/api/tokens \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"name": ""}' \
+-b /tmp/cookie.file
+
+curl -s -X POST localhost:8080/api/tokens \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"name": "old", "expires_at": "2000-01-01T00:00:00Z"}' \
+-b /tmp/cookie.file
+
+curl -s -X GET localhost:8080/api/tokens \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file
+
+# Full token works without cookie and CSRF token
+curl -s -X POST localhost:8080/api/lists \
+-H "Authorization: Bearer $FULL" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID2'", "title": "by token"}'
+
+curl -s -X GET localhost:8080/api/boards \
+-H "Authorization: Bearer $FULL" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+| jq -c '[.boards[].title]'
+
+# Tokens can not manage tokens (403)
+curl -s -X GET localhost:8080/api/tokens \
+-H "Authorization: Bearer $FULL" \
+-H 'Content-Type:application/json; charset=UTF-8'
+
+# Read-only token can get data, but can not change it (403)
+curl -s -X GET localhost:8080/api/boards/$BID1 \
+-H "Authorization: Bearer $READER" \
+-H 'Content-Type:application/json; charset=UTF-8'
+
+curl -s -X POST localhost:8080/api/lists \
+-H "Authorization: Bearer $READER" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID1'", "title": "by reader"}'
+
+# Board token can only access its board (403 for others)
+curl -s -X POST localhost:8080/api/lists \
+-H "Authorization: Bearer $BOARD" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID1'", "title": "by board token"}'
+
+curl -s -X GET localhost:8080/api/boards/$BID2 \
+-H "Authorization: Bearer $BOARD" \
+-H 'Content-Type:application/json; charset=UTF-8'
+
+curl -s -X GET localhost:8080/api/boards \
+-H "Authorization: Bearer $BOARD" \
+-H 'Content-Type:application/json; charset=UTF-8'
+
+# Unknown and expired tokens (401)
+curl -s -X GET localhost:8080/api/boards \
+-H "Authorization: Bearer vtp_unknown" \
+-H 'Content-Type:application/json; charset=UTF-8'
+
+sleep 3
+curl -s -X GET localhost:8080/api/boards \
+-H "Authorization: Bearer $SHORT" \
+-H 'Content-Type:application/json; charset=UTF-8'
+
+# Revoked token (401)
+TID1=$(curl -s -X GET localhost:8080/api/tokens -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file | jq '.tokens[0].id' -r)
+
+curl -s -X DELETE localhost:8080/api/tokens/$TID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file \
+-w '%{http_code}\n'
+
+curl -s -X DELETE localhost:8080/api/tokens/$TID4 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-b /tmp/cookie.file \
+-w '%{http_code}\n'
+
+curl -s -X GET localhost:8080/api/boards \
+-H "Authorization: Bearer $FULL" \
+-H 'Content-Type:application/json; charset=UTF-8'
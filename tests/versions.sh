@@ -0,0 +1,129 @@
+#!/bin/bash
+
+set -eu
+
+# csrf prints CSRF token issued at sign-in from a cookie file.
+csrf() { awk '$6 == "XSRF-TOKEN" { print $7 }' "$1"; }
+
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie.file
+
+# Create
+
+curl -s -X POST localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "first", "color":"red"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+BID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/lists \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID1'", "title": "first_list"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+LID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -i -X POST localhost:8080/api/items \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"list_id":"'$LID1'", "title": "first_item", "text": "hahaha", "tags":[]}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+IID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+# Update an item with a current version (ETag "1" -> "2")
+curl -s -i -X PATCH localhost:8080/api/items/$IID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-H 'If-Match:"1"' \
+-d '{"title": "second_item", "text": "from first tab", "tags":[]}' \
+-b /tmp/cookie.file
+
+# Update an item with an old version. The current item is returned with 412.
+curl -s -i -X PATCH localhost:8080/api/items/$IID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-H 'If-Match:"1"' \
+-d '{"title": "third_item", "text": "from second tab", "tags":[]}' \
+-b /tmp/cookie.file
+
+# Update an item without If-Match
+curl -s -i -X PATCH localhost:8080/api/items/$IID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "third_item", "text": "from second tab", "tags":[]}' \
+-b /tmp/cookie.file
+
+# Move an item with an old version and a current version
+curl -s -i -X PATCH localhost:8080/api/items/$IID1/move \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-H 'If-Match:"2"' \
+-d '{"list_id":"'$LID1'", "before": ""}' \
+-b /tmp/cookie.file
+
+curl -s -i -X PATCH localhost:8080/api/items/$IID1/move \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-H 'If-Match:"3"' \
+-d '{"list_id":"'$LID1'", "before": ""}' \
+-b /tmp/cookie.file
+
+# Update a list and a board with weak and malformed ETags
+curl -s -i -X PATCH localhost:8080/api/lists/$LID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-H 'If-Match:W/"1"' \
+-d '{"title": "renamed_list"}' \
+-b /tmp/cookie.file
+
+curl -s -i -X PATCH localhost:8080/api/lists/$LID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-H 'If-Match:"1"' \
+-d '{"title": "renamed_list"}' \
+-b /tmp/cookie.file
+
+curl -s -i -X PATCH localhost:8080/api/boards/$BID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-H 'If-Match:1' \
+-d '{"title": "renamed", "color":"blue"}' \
+-b /tmp/cookie.file
+
+curl -s -i -X PATCH localhost:8080/api/boards/$BID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-H 'If-Match:*' \
+-d '{"title": "renamed", "color":"blue"}' \
+-b /tmp/cookie.file
+
+# Get a board with versions of lists and items
+curl -s -i localhost:8080/api/boards/$BID1 -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file
+
+# Delete an item with an old version and a current version
+curl -s -i -X DELETE localhost:8080/api/items/$IID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-H 'If-Match:"3"' \
+-b /tmp/cookie.file
+
+curl -s -i -X DELETE localhost:8080/api/items/$IID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-H 'If-Match:"4"' \
+-b /tmp/cookie.file
+
+curl -s -i -X DELETE localhost:8080/api/boards/$BID1 \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-H 'If-Match:"2"' \
+-b /tmp/cookie.file
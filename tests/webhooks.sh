@@ -0,0 +1,111 @@
+#!/bin/bash
+# Run the API server with WEBHOOK_BACKOFF=1s to retry failed deliveries quickly,
+# and WEBHOOK_ALLOWED_NETWORKS=127.0.0.1/32,::1/128 to send deliveries to the receiver on localhost.
+
+set -eu
+
+# csrf prints CSRF token issued at sign-in from a cookie file.
+csrf() { awk '$6 == "XSRF-TOKEN" { print $7 }' "$1"; }
+
+SERVER=${SERVER:-./dist/server}
+
+curl -s -X POST localhost:8080/auth/signup -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}'
+
+curl -s -X POST localhost:8080/auth/signin -H 'Content-Type:application/json; charset=UTF-8' -d '{"name":"testuser", "password":"pass"}' -c /tmp/cookie.file
+
+curl -s -X POST localhost:8080/api/boards \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"title": "first", "color":"red"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+BID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/lists \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID1'", "title": "first_list"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+LID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/lists \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"board_id":"'$BID1'", "title": "second_list"}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+LID2=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+curl -s -X POST localhost:8080/api/items \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"list_id":"'$LID1'", "title": "first_item", "text": "hahaha", "tags":[]}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+IID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+# Invalid URL and event (400)
+curl -s -X POST localhost:8080/api/boards/$BID1/webhooks \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"url": "ftp://localhost:9000", "events": ["item.move"]}' \
+-b /tmp/cookie.file
+
+curl -s -X POST localhost:8080/api/boards/$BID1/webhooks \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"url": "http://localhost:9000", "events": ["item.jump"]}' \
+-b /tmp/cookie.file
+
+# Private and link-local addresses (400)
+curl -s -X POST localhost:8080/api/boards/$BID1/webhooks \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"url": "http://169.254.169.254/latest/meta-data", "events": ["item.move"]}' \
+-b /tmp/cookie.file
+
+curl -s -X POST localhost:8080/api/boards/$BID1/webhooks \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"url": "http://10.0.0.1:9000", "events": ["item.move"]}' \
+-b /tmp/cookie.file
+
+# Register a webhook for moves of items
+curl -s -X POST localhost:8080/api/boards/$BID1/webhooks \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"url": "http://localhost:9000", "secret": "s3cret", "events": ["item.move"]}' \
+-b /tmp/cookie.file \
+| tee /tmp/tmp.file
+
+WID1=$(cat /tmp/tmp.file | tail -1 | jq .id -r)
+
+# Secret is not responded after creation
+curl -s -X GET localhost:8080/api/boards/$BID1/webhooks -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file
+
+# Receiver fails the first delivery, so it is retried
+$SERVER webhook-receiver -secret s3cret -fail 1 > /tmp/receiver.file &
+RECEIVER=$!
+sleep 1
+
+curl -s -X PATCH localhost:8080/api/items/$IID1/move \
+-H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" \
+-H 'Content-Type:application/json; charset=UTF-8' \
+-d '{"list_id":"'$LID2'", "before": ""}' \
+-b /tmp/cookie.file \
+-w '%{http_code}\n'
+
+sleep 4
+kill $RECEIVER
+cat /tmp/receiver.file
+
+curl -s -X GET localhost:8080/api/boards/$BID1/webhooks/$WID1/deliveries -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file
+
+curl -s -X DELETE localhost:8080/api/boards/$BID1/webhooks/$WID1 -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file -w '%{http_code}\n'
+
+curl -s -X GET localhost:8080/api/boards/$BID1/webhooks -H "X-XSRF-TOKEN:$(csrf /tmp/cookie.file)" -H 'Content-Type:application/json; charset=UTF-8' -b /tmp/cookie.file
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/x-color/vue-trello/model"
+)
+
+// ActivityUsecase is interface. It defines to read Activities in a Board.
+type ActivityUsecase interface {
+	GetActivities(ctx context.Context, user model.User, board model.Board, cursor string, limit int) (model.Activities, string, error)
+}
+
+// ActivityInteractor includes repogitories and a logger.
+type ActivityInteractor struct {
+	txRepo       TransactionRepository
+	activityRepo ActivityRepository
+	memberRepo   MemberRepository
+	userRepo     UserRepository
+	logger       Logger
+}
+
+// NewActivityInteractor generates new interactor for a Activity.
+func NewActivityInteractor(
+	txRepo TransactionRepository,
+	activityRepo ActivityRepository,
+	memberRepo MemberRepository,
+	userRepo UserRepository,
+	logger Logger,
+) (ActivityInteractor, error) {
+	i := ActivityInteractor{
+		txRepo:       txRepo,
+		activityRepo: activityRepo,
+		memberRepo:   memberRepo,
+		userRepo:     userRepo,
+		logger:       logger,
+	}
+	return i, nil
+}
+
+// GetActivities returns a page of Activities in a Board from newest to oldest.
+// The second returned value is a cursor of next page. It is empty if there are no more Activities.
+func (i *ActivityInteractor) GetActivities(ctx context.Context, user model.User, board model.Board, cursor string, limit int) (model.Activities, string, error) {
+	if limit <= 0 {
+		err := model.InvalidContentError{
+			UserID: user.ID,
+			Err:    nil,
+			ID:     board.ID,
+			Act:    "validate page of activities",
+		}
+		logError(i.logger, err)
+		return model.Activities{}, "", err
+	}
+
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	if _, err := authorize(ctx, tx, i.memberRepo, board.ID, user.ID, model.VIEWER); err != nil {
+		logError(i.logger, err)
+		return model.Activities{}, "", err
+	}
+
+	// Get one more activity to know whether a next page exists.
+	activities, err := i.activityRepo.FindPage(ctx, tx, board.ID, cursor, limit+1)
+	if err != nil {
+		logError(i.logger, err)
+		return model.Activities{}, "", err
+	}
+	next := ""
+	if len(activities) > limit {
+		activities = activities[:limit]
+		next = activities[limit-1].ID
+	}
+
+	if err := fillUserNames(ctx, tx, i.userRepo, activities, map[string]string{}); err != nil {
+		logError(i.logger, err)
+		return model.Activities{}, "", err
+	}
+	i.logger.Info(formatLogMsg(user.ID, "Get activities in board("+board.ID+")"))
+
+	return activities, next, nil
+}
+
+// fillUserNames fills names of users in activities. names caches names by user ID.
+// A user may be deleted, so missing user is ignored.
+func fillUserNames(ctx context.Context, tx Transaction, userRepo UserRepository, activities model.Activities, names map[string]string) error {
+	for j, a := range activities {
+		if _, ok := names[a.UserID]; !ok {
+			u, err := userRepo.Find(ctx, tx, UserFilter{
+				IDs: []string{a.UserID},
+			})
+			if err != nil && !errors.Is(err, model.NotFoundError{}) {
+				return err
+			}
+			names[a.UserID] = u.Name
+		}
+		activities[j].UserName = names[a.UserID]
+	}
+	return nil
+}
+
+// recordActivity saves a Activity in the transaction of the change and returns the saved Activity.
+// before and after are saved as JSON. nil means that data does not exist.
+func recordActivity(ctx context.Context, tx Transaction, activityRepo ActivityRepository, activity model.Activity, before, after interface{}) (model.Activity, error) {
+	activity.ID = uuid.New().String()
+	activity.CreatedAt = time.Now()
+
+	var err error
+	if activity.Before, err = snapshot(before); err != nil {
+		return model.Activity{}, model.ServerError{
+			UserID: activity.UserID,
+			Err:    err,
+			ID:     activity.TargetID,
+			Act:    "convert data before " + string(activity.Action),
+		}
+	}
+	if activity.After, err = snapshot(after); err != nil {
+		return model.Activity{}, model.ServerError{
+			UserID: activity.UserID,
+			Err:    err,
+			ID:     activity.TargetID,
+			Act:    "convert data after " + string(activity.Action),
+		}
+	}
+
+	if err := activityRepo.Create(ctx, tx, activity); err != nil {
+		return model.Activity{}, err
+	}
+	return activity, nil
+}
+
+func snapshot(data interface{}) (string, error) {
+	if data == nil {
+		return "", nil
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
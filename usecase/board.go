@@ -1,27 +1,39 @@
 package usecase
 
 import (
+	"context"
+	"sort"
+
 	"github.com/google/uuid"
 	"github.com/x-color/vue-trello/model"
 )
 
 // BoardUsecase is interface. It defines to control a Board.
 type BoardUsecase interface {
-	Get(board model.Board) (model.Board, error)
-	GetBoards(user model.User) (model.Boards, error)
-	Create(board model.Board) (model.Board, error)
-	Delete(board model.Board) error
-	Update(board model.Board) (model.Board, error)
-	Move(board model.Board) error
+	Get(ctx context.Context, board model.Board) (model.Board, error)
+	GetBoards(ctx context.Context, user model.User) (model.Boards, error)
+	Create(ctx context.Context, board model.Board) (model.Board, error)
+	Delete(ctx context.Context, board model.Board) error
+	Update(ctx context.Context, board model.Board) (model.Board, error)
+	Move(ctx context.Context, board model.Board) (model.Board, error)
 }
 
-// BoardInteractor includes repogitories and a logger.
+// BoardInteractor includes repogitories, a bus to publish changes and a logger.
 type BoardInteractor struct {
-	txRepo    TransactionRepository
-	boardRepo BoardRepository
-	listRepo  ListRepository
-	itemRepo  ItemRepository
-	logger    Logger
+	txRepo        TransactionRepository
+	boardRepo     BoardRepository
+	listRepo      ListRepository
+	itemRepo      ItemRepository
+	memberRepo    MemberRepository
+	checklistRepo ChecklistRepository
+	checkItemRepo CheckItemRepository
+	commentRepo   CommentRepository
+	activityRepo  ActivityRepository
+	tagRepo       TagRepository
+	webhookRepo   WebhookRepository
+	deliveryRepo  DeliveryRepository
+	bus           EventBus
+	logger        Logger
 }
 
 // NewBoardInteractor generates new interactor for a Board.
@@ -30,77 +42,101 @@ func NewBoardInteractor(
 	boardRepo BoardRepository,
 	listRepo ListRepository,
 	itemRepo ItemRepository,
+	memberRepo MemberRepository,
+	checklistRepo ChecklistRepository,
+	checkItemRepo CheckItemRepository,
+	commentRepo CommentRepository,
+	activityRepo ActivityRepository,
+	tagRepo TagRepository,
+	webhookRepo WebhookRepository,
+	deliveryRepo DeliveryRepository,
+	bus EventBus,
 	logger Logger,
 ) (BoardInteractor, error) {
 	i := BoardInteractor{
-		txRepo:    txRepo,
-		boardRepo: boardRepo,
-		listRepo:  listRepo,
-		itemRepo:  itemRepo,
-		logger:    logger,
+		txRepo:        txRepo,
+		boardRepo:     boardRepo,
+		listRepo:      listRepo,
+		itemRepo:      itemRepo,
+		memberRepo:    memberRepo,
+		checklistRepo: checklistRepo,
+		checkItemRepo: checkItemRepo,
+		commentRepo:   commentRepo,
+		activityRepo:  activityRepo,
+		tagRepo:       tagRepo,
+		webhookRepo:   webhookRepo,
+		deliveryRepo:  deliveryRepo,
+		bus:           bus,
+		logger:        logger,
 	}
 	return i, nil
 }
 
 // Create saves new Board to a repository and returns created Board.
-func (i *BoardInteractor) Create(board model.Board) (model.Board, error) {
+func (i *BoardInteractor) Create(ctx context.Context, board model.Board) (model.Board, error) {
 	board.ID = uuid.New().String()
+	board.Version = 1
 	if err := i.validateBoard(board); err != nil {
 		logError(i.logger, err)
 		return model.Board{}, err
 	}
 
-	tx := i.txRepo.BeginTransaction(true)
-	i.logger.Info(formatLogMsg(board.UserID, "Start transaction"))
-
-	// Get last board
-	boards, err := i.boardRepo.Find(tx, map[string]interface{}{
-		"UserID": board.UserID,
-		"After":  "",
-	})
-	if err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-		logError(i.logger, err)
-		return model.Board{}, err
-	}
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, board.UserID, func(tx Transaction) error {
+		// Put new board at the end of user's boards
+		boards, err := i.boardRepo.Find(ctx, tx, BoardFilter{
+			UserIDs: []string{board.UserID},
+		})
+		if err != nil {
+			return err
+		}
+		boards = sortBoards(boards)
 
-	if len(boards) > 0 {
-		lastBoard := boards[0]
-		i.logger.Info(formatLogMsg(board.UserID, "Find last board("+lastBoard.ID+")"))
+		board.Rank, err = rankBoard(ctx, tx, i.boardRepo, boards, len(boards))
+		if err != nil {
+			return err
+		}
 
-		board.Before = lastBoard.ID
+		if err := i.boardRepo.Create(ctx, tx, board); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(board.UserID, "Create board("+board.ID+")"))
 
-		query := map[string]interface{}{
-			"After": board.ID,
+		owner := model.Member{
+			BoardID: board.ID,
+			UserID:  board.UserID,
+			Role:    model.OWNER,
 		}
-		if err := i.boardRepo.Update(tx, lastBoard, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-			logError(i.logger, err)
-			return model.Board{}, err
+		if err := i.memberRepo.Create(ctx, tx, owner); err != nil {
+			return err
 		}
-		i.logger.Info(formatLogMsg(board.UserID, "Update board("+lastBoard.ID+")"))
-	} else {
-		i.logger.Info(formatLogMsg(board.UserID, "Find no board"))
-	}
+		i.logger.Info(formatLogMsg(board.UserID, "Add owner of board("+board.ID+")"))
+
+		activity := model.Activity{
+			BoardID:  board.ID,
+			UserID:   board.UserID,
+			Action:   model.CREATE,
+			Target:   model.BOARD,
+			TargetID: board.ID,
+		}
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, nil, board)
+		if err != nil {
+			return err
+		}
+		events = append(events, recorded)
 
-	if err := i.boardRepo.Create(tx, board); err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-		logError(i.logger, err)
+		return nil
+	})
+	if err != nil {
 		return model.Board{}, err
 	}
-	i.logger.Info(formatLogMsg(board.UserID, "Create board("+board.ID+")"))
-
-	tx.Commit()
-	i.logger.Info(formatLogMsg(board.UserID, "Commit transaction"))
 
+	i.bus.Publish(events...)
 	return board, nil
 }
 
 // Delete removes Board in repository.
-func (i *BoardInteractor) Delete(board model.Board) error {
+func (i *BoardInteractor) Delete(ctx context.Context, board model.Board) error {
 	if board.ID == "" {
 		i.logger.Info(formatLogMsg(board.UserID, "Invalid board. ID is empty"))
 		return model.InvalidContentError{
@@ -111,117 +147,140 @@ func (i *BoardInteractor) Delete(board model.Board) error {
 		}
 	}
 
-	tx := i.txRepo.BeginTransaction(true)
-	i.logger.Info(formatLogMsg(board.UserID, "Start transaction"))
-
-	// Get board's info (e.g. board.Before, board.After...) and rewrite 'board'.
-	board, err := i.boardRepo.FindByID(tx, board.ID, board.UserID)
-	if err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-		logError(i.logger, err)
-		return err
-	}
-	i.logger.Info(formatLogMsg(board.UserID, "Find board("+board.ID+")"))
-
-	if board.Before != "" {
-		// Update board before deleting board
-		before := model.Board{
-			ID:     board.Before,
-			UserID: board.UserID,
-		}
-		query := map[string]interface{}{
-			"After": board.After,
-		}
-		if err := i.boardRepo.Update(tx, before, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, board.UserID, func(tx Transaction) error {
+		// Only an owner can delete a board.
+		if _, err := authorize(ctx, tx, i.memberRepo, board.ID, board.UserID, model.OWNER); err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(board.UserID, "Update board("+before.ID+") before deleted board("+board.ID+")"))
-	}
 
-	if board.After != "" {
-		// Update board after deleting board
-		after := model.Board{
-			ID:     board.After,
-			UserID: board.UserID,
+		// Get board's info (e.g. board.Title...) and rewrite 'board'.
+		expected := board.Version
+		board, err := i.boardRepo.FindByID(ctx, tx, board.ID)
+		if err != nil {
+			return err
 		}
-		query := map[string]interface{}{
-			"Before": board.Before,
+		i.logger.Info(formatLogMsg(board.UserID, "Find board("+board.ID+")"))
+
+		if err := checkVersion(expected, board.Version, board, board.UserID, board.ID, "check version of board to delete"); err != nil {
+			return err
 		}
-		if err := i.boardRepo.Update(tx, after, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+
+		recorded, err := i.deleteBoard(ctx, tx, board)
+		if err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(board.UserID, "Update board("+after.ID+") after deleted board("+board.ID+")"))
-	}
+		events = append(events, recorded)
 
-	if err := i.boardRepo.Delete(tx, board); err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-		logError(i.logger, err)
+		return nil
+	})
+	if err != nil {
 		return err
 	}
+
+	i.bus.Publish(events...)
+	return nil
+}
+
+// deleteBoard removes a Board with Lists, Items, members and Tags in it, and records the deletion by board.UserID.
+func (i *BoardInteractor) deleteBoard(ctx context.Context, tx Transaction, board model.Board) (model.Activity, error) {
+	if err := i.boardRepo.Delete(ctx, tx, board); err != nil {
+		return model.Activity{}, err
+	}
 	i.logger.Info(formatLogMsg(board.UserID, "Delete board("+board.ID+")"))
 
 	// Get lists in deleted board
-	lists, err := i.listRepo.Find(tx, map[string]interface{}{
-		"UserID":  board.UserID,
-		"BoardID": board.ID,
+	lists, err := i.listRepo.Find(ctx, tx, ListFilter{
+		BoardIDs: []string{board.ID},
 	})
 	if err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-		logError(i.logger, err)
-		return err
+		return model.Activity{}, err
 	}
 	i.logger.Info(formatLogMsg(board.UserID, "Find lists in deleted board("+board.ID+")"))
 
 	for _, list := range lists {
 		// Delete list
-		if err := i.listRepo.Delete(tx, list); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-			logError(i.logger, err)
-			return err
+		if err := i.listRepo.Delete(ctx, tx, list); err != nil {
+			return model.Activity{}, err
 		}
 		i.logger.Info(formatLogMsg(board.UserID, "Delete lists in deleted board("+board.ID+")"))
 
-		items, err := i.itemRepo.Find(tx, map[string]interface{}{
-			"UserID": list.UserID,
-			"ListID": list.ID,
+		items, err := i.itemRepo.Find(ctx, tx, ItemFilter{
+			ListIDs: []string{list.ID},
 		})
 		if err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-			logError(i.logger, err)
-			return err
+			return model.Activity{}, err
 		}
 		i.logger.Info(formatLogMsg(board.UserID, "Find items in deleted list("+list.ID+")"))
 
 		for _, item := range items {
-			if err := i.itemRepo.Delete(tx, item); err != nil {
-				tx.Rollback()
-				i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-				logError(i.logger, err)
-				return err
+			if err := i.itemRepo.Delete(ctx, tx, item); err != nil {
+				return model.Activity{}, err
+			}
+			if err := deleteChecklists(ctx, tx, i.checklistRepo, i.checkItemRepo, item); err != nil {
+				return model.Activity{}, err
+			}
+			if err := deleteComments(ctx, tx, i.commentRepo, item); err != nil {
+				return model.Activity{}, err
 			}
 		}
 		i.logger.Info(formatLogMsg(board.UserID, "Delete items in deleted list("+list.ID+")"))
 	}
 
-	tx.Commit()
-	i.logger.Info(formatLogMsg(board.UserID, "Commit transaction"))
+	// Remove members of deleted board
+	members, err := i.memberRepo.Find(ctx, tx, MemberFilter{
+		BoardIDs: []string{board.ID},
+	})
+	if err != nil {
+		return model.Activity{}, err
+	}
+	for _, member := range members {
+		if err := i.memberRepo.Delete(ctx, tx, member); err != nil {
+			return model.Activity{}, err
+		}
+	}
+	i.logger.Info(formatLogMsg(board.UserID, "Remove members of deleted board("+board.ID+")"))
 
-	return nil
+	// Delete tags in deleted board
+	tags, err := i.tagRepo.Find(ctx, tx, TagFilter{
+		BoardIDs: []string{board.ID},
+	})
+	if err != nil {
+		return model.Activity{}, err
+	}
+	for _, tag := range tags {
+		if err := i.tagRepo.Delete(ctx, tx, tag); err != nil {
+			return model.Activity{}, err
+		}
+	}
+	i.logger.Info(formatLogMsg(board.UserID, "Delete tags in deleted board("+board.ID+")"))
+
+	// Delete webhooks in deleted board with their deliveries
+	webhooks, err := i.webhookRepo.Find(ctx, tx, WebhookFilter{
+		BoardIDs: []string{board.ID},
+	})
+	if err != nil {
+		return model.Activity{}, err
+	}
+	for _, webhook := range webhooks {
+		if err := deleteWebhook(ctx, tx, i.webhookRepo, i.deliveryRepo, webhook); err != nil {
+			return model.Activity{}, err
+		}
+	}
+	i.logger.Info(formatLogMsg(board.UserID, "Delete webhooks in deleted board("+board.ID+")"))
+
+	activity := model.Activity{
+		BoardID:  board.ID,
+		UserID:   board.UserID,
+		Action:   model.DELETE,
+		Target:   model.BOARD,
+		TargetID: board.ID,
+	}
+	return recordActivity(ctx, tx, i.activityRepo, activity, board, nil)
 }
 
 // Update replaces a Board and returns new Board.
-func (i *BoardInteractor) Update(board model.Board) (model.Board, error) {
+func (i *BoardInteractor) Update(ctx context.Context, board model.Board) (model.Board, error) {
 	if err := i.validateBoard(board); err != nil {
 		logError(i.logger, err)
 		return model.Board{}, err
@@ -233,173 +292,158 @@ func (i *BoardInteractor) Update(board model.Board) (model.Board, error) {
 		"Color": string(board.Color),
 	}
 
-	tx := i.txRepo.BeginTransaction(false)
-	if err := i.boardRepo.Update(tx, board, query); err != nil {
-		logError(i.logger, err)
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, board.UserID, func(tx Transaction) error {
+		if _, err := authorize(ctx, tx, i.memberRepo, board.ID, board.UserID, model.EDITOR); err != nil {
+			return err
+		}
+
+		old, err := i.boardRepo.FindByID(ctx, tx, board.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(board.Version, old.Version, old, board.UserID, board.ID, "check version of board to update"); err != nil {
+			return err
+		}
+
+		if err := i.boardRepo.Update(ctx, tx, old, query); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(board.UserID, "Update board("+board.ID+")"))
+
+		updated := old
+		updated.Title = board.Title
+		updated.Text = board.Text
+		updated.Color = board.Color
+		updated.Version = old.Version + 1
+		board.Version = updated.Version
+		activity := model.Activity{
+			BoardID:  board.ID,
+			UserID:   board.UserID,
+			Action:   model.UPDATE,
+			Target:   model.BOARD,
+			TargetID: board.ID,
+		}
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, old, updated)
+		if err != nil {
+			return err
+		}
+		events = append(events, recorded)
+
+		return nil
+	})
+	if err != nil {
 		return model.Board{}, err
 	}
-	i.logger.Info(formatLogMsg(board.UserID, "Update board("+board.ID+")"))
 
+	i.bus.Publish(events...)
 	return board, nil
 }
 
 // Move moves Boards.
-func (i *BoardInteractor) Move(board model.Board) error {
+// The order of Boards belongs to an owner, so only an owner can move a Board. It returns the moved Board.
+func (i *BoardInteractor) Move(ctx context.Context, board model.Board) (model.Board, error) {
 	board.Title = "dummy title"
 	board.Color = model.RED
 	if err := i.validateBoard(board); err != nil {
 		logError(i.logger, err)
-		return err
+		return model.Board{}, err
 	}
 	board.Title = ""
 
-	tx := i.txRepo.BeginTransaction(true)
-	i.logger.Info(formatLogMsg(board.UserID, "Start transaction"))
-
-	// Get a board to move
-	old, err := i.boardRepo.FindByID(tx, board.ID, board.UserID)
-	if err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-		logError(i.logger, err)
-		return err
-	}
-	i.logger.Info(formatLogMsg(board.UserID, "Find board("+board.ID+") to move"))
-
-	// Get a board before board to move
-	if old.Before != "" {
-		beforeOld := model.Board{
-			ID:     old.Before,
-			UserID: old.UserID,
-		}
-		query := map[string]interface{}{
-			"After": old.After,
-		}
-		if err := i.boardRepo.Update(tx, beforeOld, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+	var moved model.Board
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, board.UserID, func(tx Transaction) error {
+		if _, err := authorize(ctx, tx, i.memberRepo, board.ID, board.UserID, model.OWNER); err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(board.UserID, "Find a board("+beforeOld.ID+") before board("+board.ID+") to move"))
-	}
 
-	// Get a board after board to move
-	if old.After != "" {
-		afterOld := model.Board{
-			ID:     old.After,
-			UserID: old.UserID,
-		}
-		query := map[string]interface{}{
-			"Before": old.Before,
+		// Get a board to move
+		old, err := i.boardRepo.FindByID(ctx, tx, board.ID)
+		if err != nil {
+			return err
 		}
-		if err := i.boardRepo.Update(tx, afterOld, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+		i.logger.Info(formatLogMsg(board.UserID, "Find board("+board.ID+") to move"))
+		if err := checkVersion(board.Version, old.Version, old, board.UserID, board.ID, "check version of board to move"); err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(board.UserID, "Find a board("+afterOld.ID+") after board("+board.ID+") to move"))
-	}
 
-	// Get a board after moved board
-	if board.Before == "" {
-		conditions := map[string]interface{}{
-			"UserID": board.UserID,
-			"Before": "",
-		}
-		l, err := i.boardRepo.Find(tx, conditions)
+		// Get other boards of the user
+		boards, err := i.boardRepo.Find(ctx, tx, BoardFilter{
+			UserIDs: []string{old.UserID},
+		})
 		if err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-			logError(i.logger, err)
 			return err
 		}
-		if len(l) != 1 {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-			err = model.ServerError{
+		others := model.Boards{}
+		ids := []string{}
+		for _, b := range sortBoards(boards) {
+			if b.ID != old.ID {
+				others = append(others, b)
+				ids = append(ids, b.ID)
+			}
+		}
+
+		index := positionAfter(ids, board.Before)
+		if index < 0 {
+			return model.InvalidContentError{
 				ID:     board.ID,
 				UserID: board.UserID,
 				Err:    nil,
-				Act:    "find a board before moved board",
+				Act:    "validate board before moved board",
 			}
-			logError(i.logger, err)
-			return err
 		}
-		board.After = l[0].ID
-	} else {
-		before, err := i.boardRepo.FindByID(tx, board.Before, board.UserID)
+
+		rank, err := rankBoard(ctx, tx, i.boardRepo, others, index)
 		if err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-			logError(i.logger, err)
 			return err
 		}
-		board.After = before.After
-	}
-	i.logger.Info(formatLogMsg(board.UserID, "Find a board("+board.After+") after moved board("+board.ID+")"))
 
-	// Update a board before moved board
-	if board.Before != "" {
-		before := model.Board{
-			ID:     board.Before,
-			UserID: board.UserID,
-		}
+		// Move board
 		query := map[string]interface{}{
-			"After": board.ID,
+			"Rank": rank,
 		}
-		if err := i.boardRepo.Update(tx, before, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+		if err := i.boardRepo.Update(ctx, tx, old, query); err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(board.UserID, "Update board("+before.ID+") before board("+board.ID+")"))
-	}
-
-	// Update a board after moved board
-	if board.After != "" {
-		after := model.Board{
-			ID:     board.After,
-			UserID: board.UserID,
-		}
-		query := map[string]interface{}{
-			"Before": board.ID,
+		i.logger.Info(formatLogMsg(board.UserID, "Move board("+board.ID+") after board("+board.Before+")"))
+
+		moved = old
+		moved.Rank = rank
+		moved.Version = old.Version + 1
+		activity := model.Activity{
+			BoardID:  board.ID,
+			UserID:   board.UserID,
+			Action:   model.MOVE,
+			Target:   model.BOARD,
+			TargetID: board.ID,
 		}
-		if err := i.boardRepo.Update(tx, after, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, old, moved)
+		if err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(board.UserID, "Update board("+after.ID+") after board("+board.ID+")"))
-	}
+		events = append(events, recorded)
 
-	// Move board
-	query := map[string]interface{}{
-		"Before": board.Before,
-		"After":  board.After,
-	}
-	if err := i.boardRepo.Update(tx, board, query); err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(board.UserID, "Rollback transaction"))
-		logError(i.logger, err)
-		return err
+		return nil
+	})
+	if err != nil {
+		return model.Board{}, err
 	}
-	i.logger.Info(formatLogMsg(board.UserID, "Move board("+board.ID+") after board("+board.Before+")"))
-
-	tx.Commit()
-	i.logger.Info(formatLogMsg(board.UserID, "Commit transaction"))
 
-	return nil
+	i.bus.Publish(events...)
+	return moved, nil
 }
 
 // Get returns Board embedded all data.
-func (i *BoardInteractor) Get(board model.Board) (model.Board, error) {
-	tx := i.txRepo.BeginTransaction(false)
+func (i *BoardInteractor) Get(ctx context.Context, board model.Board) (model.Board, error) {
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	if _, err := authorize(ctx, tx, i.memberRepo, board.ID, board.UserID, model.VIEWER); err != nil {
+		logError(i.logger, err)
+		return model.Board{}, err
+	}
 
-	board, err := i.boardRepo.FindByID(tx, board.ID, board.UserID)
+	board, err := i.boardRepo.FindByID(ctx, tx, board.ID)
 	if err != nil {
 		logError(i.logger, err)
 		return model.Board{}, err
@@ -407,9 +451,8 @@ func (i *BoardInteractor) Get(board model.Board) (model.Board, error) {
 	i.logger.Info(formatLogMsg(board.UserID, "Find board("+board.ID+")"))
 
 	// Get Lists in Board.
-	lists, err := i.listRepo.Find(tx, map[string]interface{}{
-		"BoardID": board.ID,
-		"UserID":  board.UserID,
+	lists, err := i.listRepo.Find(ctx, tx, ListFilter{
+		BoardIDs: []string{board.ID},
 	})
 	if err != nil {
 		logError(i.logger, err)
@@ -419,16 +462,30 @@ func (i *BoardInteractor) Get(board model.Board) (model.Board, error) {
 	i.logger.Info(formatLogMsg(board.UserID, "Find lists in board("+board.ID+")"))
 
 	// Get Items in Lists.
+	listIDs := []string{}
+	for _, list := range board.Lists {
+		listIDs = append(listIDs, list.ID)
+	}
+	items, err := i.itemRepo.Find(ctx, tx, ItemFilter{
+		ListIDs: listIDs,
+	})
+	if err != nil {
+		logError(i.logger, err)
+		return model.Board{}, err
+	}
+
+	// Count check items in each item
+	if err := countCheckItems(ctx, tx, i.checkItemRepo, items); err != nil {
+		logError(i.logger, err)
+		return model.Board{}, err
+	}
+
+	itemsOf := map[string]model.Items{}
+	for _, item := range sortItems(items) {
+		itemsOf[item.ListID] = append(itemsOf[item.ListID], item)
+	}
 	for j, list := range board.Lists {
-		items, err := i.itemRepo.Find(tx, map[string]interface{}{
-			"ListID": list.ID,
-			"UserID": list.UserID,
-		})
-		if err != nil {
-			logError(i.logger, err)
-			return model.Board{}, err
-		}
-		board.Lists[j].Items = sortItems(items)
+		board.Lists[j].Items = itemsOf[list.ID]
 	}
 	i.logger.Info(formatLogMsg(board.UserID, "Find items in board("+board.ID+")"))
 
@@ -436,19 +493,40 @@ func (i *BoardInteractor) Get(board model.Board) (model.Board, error) {
 	return board, nil
 }
 
-// GetBoards returns User's Boards.
-func (i *BoardInteractor) GetBoards(user model.User) (model.Boards, error) {
-	tx := i.txRepo.BeginTransaction(false)
+// GetBoards returns User's Boards followed by Boards shared with User.
+func (i *BoardInteractor) GetBoards(ctx context.Context, user model.User) (model.Boards, error) {
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	boards, err := i.boardRepo.Find(ctx, tx, BoardFilter{
+		UserIDs: []string{user.ID},
+	})
+	if err != nil {
+		logError(i.logger, err)
+		return model.Boards{}, err
+	}
+	boards = sortBoards(boards)
 
-	boards, err := i.boardRepo.Find(tx, map[string]interface{}{
-		"UserID": user.ID,
+	members, err := i.memberRepo.Find(ctx, tx, MemberFilter{
+		UserIDs: []string{user.ID},
 	})
 	if err != nil {
 		logError(i.logger, err)
 		return model.Boards{}, err
 	}
+	for _, m := range members {
+		if m.Role == model.OWNER {
+			continue
+		}
+		b, err := i.boardRepo.FindByID(ctx, tx, m.BoardID)
+		if err != nil {
+			logError(i.logger, err)
+			return model.Boards{}, err
+		}
+		boards = append(boards, b)
+	}
+
 	i.logger.Info(formatLogMsg(user.ID, "Get boards"))
-	return sortBoards(boards), nil
+	return boards, nil
 }
 
 func (i *BoardInteractor) validateBoard(board model.Board) error {
@@ -476,18 +554,33 @@ func (i *BoardInteractor) validateBoard(board model.Board) error {
 	}
 }
 
-func sortBoards(boards model.Boards) model.Boards {
-	l := map[string]model.Board{}
-	for _, b := range boards {
-		l[b.Before] = b
+// rankBoard returns a rank to put a Board at index in sorted Boards of a user.
+// The Boards are rebalanced if the rank gets too long.
+func rankBoard(ctx context.Context, tx Transaction, boardRepo BoardRepository, boards model.Boards, index int) (string, error) {
+	ranks := []string{}
+	for _, x := range boards {
+		ranks = append(ranks, x.Rank)
+	}
+	if rank, ok := rankAt(ranks, index); ok {
+		return rank, nil
 	}
 
-	board := l[""]
-	r := model.Boards{}
-	for range boards {
-		r = append(r, board)
-		board = l[board.ID]
+	ranks = model.Ranks(len(boards))
+	for j, x := range boards {
+		if err := boardRepo.Update(ctx, tx, x, map[string]interface{}{"Rank": ranks[j]}); err != nil {
+			return "", err
+		}
 	}
+	rank, _ := rankAt(ranks, index)
+	return rank, nil
+}
 
-	return r
+func sortBoards(boards model.Boards) model.Boards {
+	sort.SliceStable(boards, func(a, b int) bool {
+		if boards[a].Rank != boards[b].Rank {
+			return boards[a].Rank < boards[b].Rank
+		}
+		return boards[a].ID < boards[b].ID
+	})
+	return boards
 }
@@ -0,0 +1,780 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/x-color/vue-trello/model"
+)
+
+// ChecklistUsecase is interface. It defines to control Checklists and CheckItems in a Item.
+type ChecklistUsecase interface {
+	GetChecklists(ctx context.Context, item model.Item) (model.Checklists, error)
+	Create(ctx context.Context, checklist model.Checklist) (model.Checklist, error)
+	Delete(ctx context.Context, checklist model.Checklist) error
+	Update(ctx context.Context, checklist model.Checklist) (model.Checklist, error)
+	Move(ctx context.Context, checklist model.Checklist) error
+	CreateCheckItem(ctx context.Context, checkItem model.CheckItem) (model.CheckItem, error)
+	DeleteCheckItem(ctx context.Context, checkItem model.CheckItem) error
+	UpdateCheckItem(ctx context.Context, checkItem model.CheckItem) (model.CheckItem, error)
+	MoveCheckItem(ctx context.Context, checkItem model.CheckItem) error
+}
+
+// ChecklistInteractor includes repogitories, a bus to publish changes and a logger.
+type ChecklistInteractor struct {
+	txRepo        TransactionRepository
+	checklistRepo ChecklistRepository
+	checkItemRepo CheckItemRepository
+	itemRepo      ItemRepository
+	listRepo      ListRepository
+	memberRepo    MemberRepository
+	activityRepo  ActivityRepository
+	bus           EventBus
+	logger        Logger
+}
+
+// NewChecklistInteractor generates new interactor for a Checklist.
+func NewChecklistInteractor(
+	txRepo TransactionRepository,
+	checklistRepo ChecklistRepository,
+	checkItemRepo CheckItemRepository,
+	itemRepo ItemRepository,
+	listRepo ListRepository,
+	memberRepo MemberRepository,
+	activityRepo ActivityRepository,
+	bus EventBus,
+	logger Logger,
+) (ChecklistInteractor, error) {
+	i := ChecklistInteractor{
+		txRepo:        txRepo,
+		checklistRepo: checklistRepo,
+		checkItemRepo: checkItemRepo,
+		itemRepo:      itemRepo,
+		listRepo:      listRepo,
+		memberRepo:    memberRepo,
+		activityRepo:  activityRepo,
+		bus:           bus,
+		logger:        logger,
+	}
+	return i, nil
+}
+
+// GetChecklists returns Checklists embedded CheckItems in a Item.
+func (i *ChecklistInteractor) GetChecklists(ctx context.Context, item model.Item) (model.Checklists, error) {
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	if _, err := authorizeItem(ctx, tx, i.itemRepo, i.listRepo, i.memberRepo, item.ID, item.UserID, model.VIEWER); err != nil {
+		logError(i.logger, err)
+		return model.Checklists{}, err
+	}
+
+	checklists, err := i.checklistRepo.Find(ctx, tx, ChecklistFilter{
+		ItemIDs: []string{item.ID},
+	})
+	if err != nil {
+		logError(i.logger, err)
+		return model.Checklists{}, err
+	}
+	checklists = sortChecklists(checklists)
+	i.logger.Info(formatLogMsg(item.UserID, "Find checklists in item("+item.ID+")"))
+
+	for j, checklist := range checklists {
+		checkItems, err := i.checkItemRepo.Find(ctx, tx, CheckItemFilter{
+			ChecklistIDs: []string{checklist.ID},
+		})
+		if err != nil {
+			logError(i.logger, err)
+			return model.Checklists{}, err
+		}
+		checklists[j].CheckItems = sortCheckItems(checkItems)
+	}
+	i.logger.Info(formatLogMsg(item.UserID, "Find check items in item("+item.ID+")"))
+
+	return checklists, nil
+}
+
+// Create saves new Checklist to a repository and returns created Checklist.
+func (i *ChecklistInteractor) Create(ctx context.Context, checklist model.Checklist) (model.Checklist, error) {
+	checklist.ID = uuid.New().String()
+	if err := validateChecklist(checklist); err != nil {
+		logError(i.logger, err)
+		return model.Checklist{}, err
+	}
+
+	checklist.CheckItems = model.CheckItems{}
+
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, checklist.UserID, func(tx Transaction) error {
+		boardID, err := i.findBoardID(ctx, tx, checklist.ItemID, checklist.UserID, model.EDITOR)
+		if err != nil {
+			return err
+		}
+
+		// Put new checklist at the end of item
+		checklists, err := i.checklistRepo.Find(ctx, tx, ChecklistFilter{
+			ItemIDs: []string{checklist.ItemID},
+		})
+		if err != nil {
+			return err
+		}
+		checklists = sortChecklists(checklists)
+
+		checklist.Rank, err = rankChecklist(ctx, tx, i.checklistRepo, checklists, len(checklists))
+		if err != nil {
+			return err
+		}
+
+		if err := i.checklistRepo.Create(ctx, tx, checklist); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(checklist.UserID, "Create checklist("+checklist.ID+")"))
+
+		activity := model.Activity{
+			BoardID:  boardID,
+			UserID:   checklist.UserID,
+			Action:   model.CREATE,
+			Target:   model.CHECKLIST,
+			TargetID: checklist.ID,
+		}
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, nil, checklist)
+		if err != nil {
+			return err
+		}
+		events = append(events, recorded)
+
+		return nil
+	})
+	if err != nil {
+		return model.Checklist{}, err
+	}
+
+	i.bus.Publish(events...)
+	return checklist, nil
+}
+
+// Delete removes Checklist and its CheckItems in repository.
+func (i *ChecklistInteractor) Delete(ctx context.Context, checklist model.Checklist) error {
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, checklist.UserID, func(tx Transaction) error {
+		old, boardID, err := i.findChecklist(ctx, tx, checklist, model.EDITOR)
+		if err != nil {
+			return err
+		}
+
+		if err := i.checklistRepo.Delete(ctx, tx, old); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(checklist.UserID, "Delete checklist("+old.ID+")"))
+
+		checkItems, err := i.checkItemRepo.Find(ctx, tx, CheckItemFilter{
+			ChecklistIDs: []string{old.ID},
+		})
+		if err != nil {
+			return err
+		}
+		for _, checkItem := range checkItems {
+			if err := i.checkItemRepo.Delete(ctx, tx, checkItem); err != nil {
+				return err
+			}
+		}
+		i.logger.Info(formatLogMsg(checklist.UserID, "Delete check items in deleted checklist("+old.ID+")"))
+
+		old.CheckItems = sortCheckItems(checkItems)
+		activity := model.Activity{
+			BoardID:  boardID,
+			UserID:   checklist.UserID,
+			Action:   model.DELETE,
+			Target:   model.CHECKLIST,
+			TargetID: old.ID,
+		}
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, old, nil)
+		if err != nil {
+			return err
+		}
+		events = append(events, recorded)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	i.bus.Publish(events...)
+	return nil
+}
+
+// Update replaces a Checklist and returns new Checklist.
+func (i *ChecklistInteractor) Update(ctx context.Context, checklist model.Checklist) (model.Checklist, error) {
+	if err := validateChecklist(checklist); err != nil {
+		logError(i.logger, err)
+		return model.Checklist{}, err
+	}
+
+	var updated model.Checklist
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, checklist.UserID, func(tx Transaction) error {
+		old, boardID, err := i.findChecklist(ctx, tx, checklist, model.EDITOR)
+		if err != nil {
+			return err
+		}
+
+		query := map[string]interface{}{
+			"Title": checklist.Title,
+		}
+		if err := i.checklistRepo.Update(ctx, tx, checklist, query); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(checklist.UserID, "Update checklist("+checklist.ID+")"))
+
+		updated = old
+		updated.Title = checklist.Title
+		activity := model.Activity{
+			BoardID:  boardID,
+			UserID:   checklist.UserID,
+			Action:   model.UPDATE,
+			Target:   model.CHECKLIST,
+			TargetID: checklist.ID,
+		}
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, old, updated)
+		if err != nil {
+			return err
+		}
+		events = append(events, recorded)
+
+		return nil
+	})
+	if err != nil {
+		return model.Checklist{}, err
+	}
+
+	i.bus.Publish(events...)
+	return updated, nil
+}
+
+// Move moves a Checklist in a Item.
+func (i *ChecklistInteractor) Move(ctx context.Context, checklist model.Checklist) error {
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, checklist.UserID, func(tx Transaction) error {
+		// Get a checklist to move
+		old, boardID, err := i.findChecklist(ctx, tx, checklist, model.EDITOR)
+		if err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(checklist.UserID, "Find checklist("+checklist.ID+") to move"))
+
+		// Get other checklists in item
+		checklists, err := i.checklistRepo.Find(ctx, tx, ChecklistFilter{
+			ItemIDs: []string{old.ItemID},
+		})
+		if err != nil {
+			return err
+		}
+		others := model.Checklists{}
+		ids := []string{}
+		for _, c := range sortChecklists(checklists) {
+			if c.ID != old.ID {
+				others = append(others, c)
+				ids = append(ids, c.ID)
+			}
+		}
+
+		index := positionAfter(ids, checklist.Before)
+		if index < 0 {
+			return model.InvalidContentError{
+				UserID: checklist.UserID,
+				Err:    nil,
+				ID:     checklist.ID,
+				Act:    "validate checklist(" + checklist.Before + ") before moved checklist",
+			}
+		}
+
+		rank, err := rankChecklist(ctx, tx, i.checklistRepo, others, index)
+		if err != nil {
+			return err
+		}
+
+		// Move checklist
+		query := map[string]interface{}{
+			"Rank": rank,
+		}
+		if err := i.checklistRepo.Update(ctx, tx, old, query); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(checklist.UserID, "Move checklist("+checklist.ID+") after checklist("+checklist.Before+") in item("+old.ItemID+")"))
+
+		moved := old
+		moved.Rank = rank
+		activity := model.Activity{
+			BoardID:  boardID,
+			UserID:   checklist.UserID,
+			Action:   model.MOVE,
+			Target:   model.CHECKLIST,
+			TargetID: checklist.ID,
+		}
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, old, moved)
+		if err != nil {
+			return err
+		}
+		events = append(events, recorded)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	i.bus.Publish(events...)
+	return nil
+}
+
+// CreateCheckItem saves new CheckItem to a repository and returns created CheckItem.
+func (i *ChecklistInteractor) CreateCheckItem(ctx context.Context, checkItem model.CheckItem) (model.CheckItem, error) {
+	checkItem.ID = uuid.New().String()
+	if err := validateCheckItem(checkItem); err != nil {
+		logError(i.logger, err)
+		return model.CheckItem{}, err
+	}
+
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, checkItem.UserID, func(tx Transaction) error {
+		checklist := model.Checklist{
+			ID:     checkItem.ChecklistID,
+			ItemID: checkItem.ItemID,
+			UserID: checkItem.UserID,
+		}
+		_, boardID, err := i.findChecklist(ctx, tx, checklist, model.EDITOR)
+		if err != nil {
+			return err
+		}
+
+		// Put new check item at the end of checklist
+		checkItems, err := i.checkItemRepo.Find(ctx, tx, CheckItemFilter{
+			ChecklistIDs: []string{checkItem.ChecklistID},
+		})
+		if err != nil {
+			return err
+		}
+		checkItems = sortCheckItems(checkItems)
+
+		checkItem.Rank, err = rankCheckItem(ctx, tx, i.checkItemRepo, checkItems, len(checkItems))
+		if err != nil {
+			return err
+		}
+
+		if err := i.checkItemRepo.Create(ctx, tx, checkItem); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(checkItem.UserID, "Create check item("+checkItem.ID+")"))
+
+		activity := model.Activity{
+			BoardID:  boardID,
+			UserID:   checkItem.UserID,
+			Action:   model.CREATE,
+			Target:   model.CHECKITEM,
+			TargetID: checkItem.ID,
+		}
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, nil, checkItem)
+		if err != nil {
+			return err
+		}
+		events = append(events, recorded)
+
+		return nil
+	})
+	if err != nil {
+		return model.CheckItem{}, err
+	}
+
+	i.bus.Publish(events...)
+	return checkItem, nil
+}
+
+// DeleteCheckItem removes CheckItem in repository.
+func (i *ChecklistInteractor) DeleteCheckItem(ctx context.Context, checkItem model.CheckItem) error {
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, checkItem.UserID, func(tx Transaction) error {
+		old, boardID, err := i.findCheckItem(ctx, tx, checkItem, model.EDITOR)
+		if err != nil {
+			return err
+		}
+
+		if err := i.checkItemRepo.Delete(ctx, tx, old); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(checkItem.UserID, "Delete check item("+old.ID+")"))
+
+		activity := model.Activity{
+			BoardID:  boardID,
+			UserID:   checkItem.UserID,
+			Action:   model.DELETE,
+			Target:   model.CHECKITEM,
+			TargetID: old.ID,
+		}
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, old, nil)
+		if err != nil {
+			return err
+		}
+		events = append(events, recorded)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	i.bus.Publish(events...)
+	return nil
+}
+
+// UpdateCheckItem replaces a CheckItem and returns new CheckItem.
+func (i *ChecklistInteractor) UpdateCheckItem(ctx context.Context, checkItem model.CheckItem) (model.CheckItem, error) {
+	if err := validateCheckItem(checkItem); err != nil {
+		logError(i.logger, err)
+		return model.CheckItem{}, err
+	}
+
+	var updated model.CheckItem
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, checkItem.UserID, func(tx Transaction) error {
+		old, boardID, err := i.findCheckItem(ctx, tx, checkItem, model.EDITOR)
+		if err != nil {
+			return err
+		}
+
+		query := map[string]interface{}{
+			"Title":   checkItem.Title,
+			"Checked": checkItem.Checked,
+		}
+		if err := i.checkItemRepo.Update(ctx, tx, checkItem, query); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(checkItem.UserID, "Update check item("+checkItem.ID+")"))
+
+		updated = old
+		updated.Title = checkItem.Title
+		updated.Checked = checkItem.Checked
+		activity := model.Activity{
+			BoardID:  boardID,
+			UserID:   checkItem.UserID,
+			Action:   model.UPDATE,
+			Target:   model.CHECKITEM,
+			TargetID: checkItem.ID,
+		}
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, old, updated)
+		if err != nil {
+			return err
+		}
+		events = append(events, recorded)
+
+		return nil
+	})
+	if err != nil {
+		return model.CheckItem{}, err
+	}
+
+	i.bus.Publish(events...)
+	return updated, nil
+}
+
+// MoveCheckItem moves a CheckItem. It can be moved to other Checklist in the same Item.
+func (i *ChecklistInteractor) MoveCheckItem(ctx context.Context, checkItem model.CheckItem) error {
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, checkItem.UserID, func(tx Transaction) error {
+		// Get a check item to move. checkItem.ChecklistID is a destination.
+		src := checkItem
+		src.ChecklistID = ""
+		old, boardID, err := i.findCheckItem(ctx, tx, src, model.EDITOR)
+		if err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(checkItem.UserID, "Find check item("+checkItem.ID+") to move"))
+
+		if checkItem.ChecklistID == "" {
+			checkItem.ChecklistID = old.ChecklistID
+		}
+		dst, err := i.checklistRepo.FindByID(ctx, tx, checkItem.ChecklistID)
+		if err == nil && dst.ItemID != old.ItemID {
+			err = model.InvalidContentError{
+				UserID: checkItem.UserID,
+				Err:    nil,
+				ID:     checkItem.ID,
+				Act:    "validate checklist(" + checkItem.ChecklistID + ") to move check item",
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		// Get other check items in destination checklist
+		checkItems, err := i.checkItemRepo.Find(ctx, tx, CheckItemFilter{
+			ChecklistIDs: []string{checkItem.ChecklistID},
+		})
+		if err != nil {
+			return err
+		}
+		others := model.CheckItems{}
+		ids := []string{}
+		for _, c := range sortCheckItems(checkItems) {
+			if c.ID != old.ID {
+				others = append(others, c)
+				ids = append(ids, c.ID)
+			}
+		}
+
+		index := positionAfter(ids, checkItem.Before)
+		if index < 0 {
+			return model.InvalidContentError{
+				UserID: checkItem.UserID,
+				Err:    nil,
+				ID:     checkItem.ID,
+				Act:    "validate check item(" + checkItem.Before + ") before moved check item",
+			}
+		}
+
+		rank, err := rankCheckItem(ctx, tx, i.checkItemRepo, others, index)
+		if err != nil {
+			return err
+		}
+
+		// Move check item
+		query := map[string]interface{}{
+			"ChecklistID": checkItem.ChecklistID,
+			"Rank":        rank,
+		}
+		if err := i.checkItemRepo.Update(ctx, tx, old, query); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(checkItem.UserID, "Move check item("+checkItem.ID+") after check item("+checkItem.Before+") in checklist("+checkItem.ChecklistID+")"))
+
+		moved := old
+		moved.ChecklistID = checkItem.ChecklistID
+		moved.Rank = rank
+		activity := model.Activity{
+			BoardID:  boardID,
+			UserID:   checkItem.UserID,
+			Action:   model.MOVE,
+			Target:   model.CHECKITEM,
+			TargetID: checkItem.ID,
+		}
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, old, moved)
+		if err != nil {
+			return err
+		}
+		events = append(events, recorded)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	i.bus.Publish(events...)
+	return nil
+}
+
+// findChecklist returns saved Checklist and ID of its Board if it is in the Item and a user has the required role.
+func (i *ChecklistInteractor) findChecklist(ctx context.Context, tx Transaction, checklist model.Checklist, required model.Role) (model.Checklist, string, error) {
+	old, err := i.checklistRepo.FindByID(ctx, tx, checklist.ID)
+	if err != nil {
+		return model.Checklist{}, "", err
+	}
+	if old.ItemID != checklist.ItemID {
+		return model.Checklist{}, "", model.NotFoundError{
+			UserID: checklist.UserID,
+			Err:    nil,
+			ID:     checklist.ID,
+			Act:    "find checklist in item(" + checklist.ItemID + ")",
+		}
+	}
+
+	boardID, err := i.findBoardID(ctx, tx, old.ItemID, checklist.UserID, required)
+	if err != nil {
+		return model.Checklist{}, "", err
+	}
+	return old, boardID, nil
+}
+
+// findCheckItem returns saved CheckItem and ID of its Board if it is in the Item and a user has the required role.
+// ChecklistID of checkItem is not checked if it is empty.
+func (i *ChecklistInteractor) findCheckItem(ctx context.Context, tx Transaction, checkItem model.CheckItem, required model.Role) (model.CheckItem, string, error) {
+	old, err := i.checkItemRepo.FindByID(ctx, tx, checkItem.ID)
+	if err != nil {
+		return model.CheckItem{}, "", err
+	}
+	if old.ItemID != checkItem.ItemID || (checkItem.ChecklistID != "" && old.ChecklistID != checkItem.ChecklistID) {
+		return model.CheckItem{}, "", model.NotFoundError{
+			UserID: checkItem.UserID,
+			Err:    nil,
+			ID:     checkItem.ID,
+			Act:    "find check item in checklist(" + checkItem.ChecklistID + ")",
+		}
+	}
+
+	boardID, err := i.findBoardID(ctx, tx, old.ItemID, checkItem.UserID, required)
+	if err != nil {
+		return model.CheckItem{}, "", err
+	}
+	return old, boardID, nil
+}
+
+// findBoardID returns ID of a Board including a Item if a user has the required role in the Board.
+// Activities of Checklists and CheckItems are recorded in the Board.
+func (i *ChecklistInteractor) findBoardID(ctx context.Context, tx Transaction, itemID, userID string, required model.Role) (string, error) {
+	item, err := i.itemRepo.FindByID(ctx, tx, itemID)
+	if err != nil {
+		return "", err
+	}
+	list, err := authorizeList(ctx, tx, i.listRepo, i.memberRepo, item.ListID, userID, required)
+	if err != nil {
+		return "", err
+	}
+	return list.BoardID, nil
+}
+
+func validateChecklist(checklist model.Checklist) error {
+	if checklist.ID == "" || checklist.Title == "" || checklist.ItemID == "" || checklist.UserID == "" {
+		return model.InvalidContentError{
+			UserID: checklist.UserID,
+			Err:    nil,
+			ID:     checklist.ID,
+			Act:    "validate contents in checklist",
+		}
+	}
+	return nil
+}
+
+func validateCheckItem(checkItem model.CheckItem) error {
+	if checkItem.ID == "" || checkItem.Title == "" || checkItem.ChecklistID == "" || checkItem.ItemID == "" || checkItem.UserID == "" {
+		return model.InvalidContentError{
+			UserID: checkItem.UserID,
+			Err:    nil,
+			ID:     checkItem.ID,
+			Act:    "validate contents in check item",
+		}
+	}
+	return nil
+}
+
+// deleteChecklists removes Checklists and CheckItems in a deleted Item.
+func deleteChecklists(ctx context.Context, tx Transaction, checklistRepo ChecklistRepository, checkItemRepo CheckItemRepository, item model.Item) error {
+	checkItems, err := checkItemRepo.Find(ctx, tx, CheckItemFilter{
+		ItemIDs: []string{item.ID},
+	})
+	if err != nil {
+		return err
+	}
+	for _, checkItem := range checkItems {
+		if err := checkItemRepo.Delete(ctx, tx, checkItem); err != nil {
+			return err
+		}
+	}
+
+	checklists, err := checklistRepo.Find(ctx, tx, ChecklistFilter{
+		ItemIDs: []string{item.ID},
+	})
+	if err != nil {
+		return err
+	}
+	for _, checklist := range checklists {
+		if err := checklistRepo.Delete(ctx, tx, checklist); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countCheckItems sets the number of checked CheckItems and all CheckItems to each Item.
+// CheckItems of all Items are got at once.
+func countCheckItems(ctx context.Context, tx Transaction, checkItemRepo CheckItemRepository, items model.Items) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids := []string{}
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	checkItems, err := checkItemRepo.Find(ctx, tx, CheckItemFilter{
+		ItemIDs: ids,
+	})
+	if err != nil {
+		return err
+	}
+
+	done := map[string]int{}
+	total := map[string]int{}
+	for _, checkItem := range checkItems {
+		total[checkItem.ItemID]++
+		if checkItem.Checked {
+			done[checkItem.ItemID]++
+		}
+	}
+	for j := range items {
+		items[j].CheckItemsDone = done[items[j].ID]
+		items[j].CheckItemsTotal = total[items[j].ID]
+	}
+	return nil
+}
+
+// rankChecklist returns a rank to put a Checklist at index in sorted Checklists of a Item.
+// The Checklists are rebalanced if the rank gets too long.
+func rankChecklist(ctx context.Context, tx Transaction, checklistRepo ChecklistRepository, checklists model.Checklists, index int) (string, error) {
+	ranks := []string{}
+	for _, c := range checklists {
+		ranks = append(ranks, c.Rank)
+	}
+	if rank, ok := rankAt(ranks, index); ok {
+		return rank, nil
+	}
+
+	ranks = model.Ranks(len(checklists))
+	for j, c := range checklists {
+		if err := checklistRepo.Update(ctx, tx, c, map[string]interface{}{"Rank": ranks[j]}); err != nil {
+			return "", err
+		}
+	}
+	rank, _ := rankAt(ranks, index)
+	return rank, nil
+}
+
+// rankCheckItem returns a rank to put a CheckItem at index in sorted CheckItems of a Checklist.
+// The CheckItems are rebalanced if the rank gets too long.
+func rankCheckItem(ctx context.Context, tx Transaction, checkItemRepo CheckItemRepository, checkItems model.CheckItems, index int) (string, error) {
+	ranks := []string{}
+	for _, c := range checkItems {
+		ranks = append(ranks, c.Rank)
+	}
+	if rank, ok := rankAt(ranks, index); ok {
+		return rank, nil
+	}
+
+	ranks = model.Ranks(len(checkItems))
+	for j, c := range checkItems {
+		if err := checkItemRepo.Update(ctx, tx, c, map[string]interface{}{"Rank": ranks[j]}); err != nil {
+			return "", err
+		}
+	}
+	rank, _ := rankAt(ranks, index)
+	return rank, nil
+}
+
+func sortChecklists(checklists model.Checklists) model.Checklists {
+	sort.SliceStable(checklists, func(a, b int) bool {
+		if checklists[a].Rank != checklists[b].Rank {
+			return checklists[a].Rank < checklists[b].Rank
+		}
+		return checklists[a].ID < checklists[b].ID
+	})
+	return checklists
+}
+
+func sortCheckItems(checkItems model.CheckItems) model.CheckItems {
+	sort.SliceStable(checkItems, func(a, b int) bool {
+		if checkItems[a].Rank != checkItems[b].Rank {
+			return checkItems[a].Rank < checkItems[b].Rank
+		}
+		return checkItems[a].ID < checkItems[b].ID
+	})
+	return checkItems
+}
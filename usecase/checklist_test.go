@@ -0,0 +1,133 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/x-color/vue-trello/interface/repository/memory"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+func TestChecklistActivities(t *testing.T) {
+	ctx := context.Background()
+	dbm := memory.NewDBManager()
+	i := newInteractors(t, &dbm, nil)
+	list := createList(t, i, "u1")
+	item, err := i.item.Create(ctx, model.Item{ListID: list.ID, UserID: "u1", Title: "item"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	published, cancel := i.bus.Subscribe(list.BoardID)
+	defer cancel()
+
+	first, err := i.checklist.Create(ctx, model.Checklist{ItemID: item.ID, UserID: "u1", Title: "first"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := i.checklist.Create(ctx, model.Checklist{ItemID: item.ID, UserID: "u1", Title: "second"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkItem, err := i.checklist.CreateCheckItem(ctx, model.CheckItem{ChecklistID: first.ID, ItemID: item.ID, UserID: "u1", Title: "check"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkItem.Checked = true
+	if _, err := i.checklist.UpdateCheckItem(ctx, checkItem); err != nil {
+		t.Fatal(err)
+	}
+	checkItem.ChecklistID = second.ID
+	if err := i.checklist.MoveCheckItem(ctx, checkItem); err != nil {
+		t.Fatal(err)
+	}
+	first.Title = "updated"
+	if _, err := i.checklist.Update(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := i.checklist.Move(ctx, second); err != nil {
+		t.Fatal(err)
+	}
+	if err := i.checklist.DeleteCheckItem(ctx, checkItem); err != nil {
+		t.Fatal(err)
+	}
+	if err := i.checklist.Delete(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"checklist.create",
+		"checklist.create",
+		"check_item.create",
+		"check_item.update",
+		"check_item.move",
+		"checklist.update",
+		"checklist.move",
+		"check_item.delete",
+		"checklist.delete",
+	}
+
+	// Activities are recorded in the Board of the Item
+	tx := dbm.TransactionManager.BeginTransaction(ctx, false)
+	activities, err := dbm.ActivityDBManager.FindPage(ctx, tx, list.BoardID, "", len(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := []string{}
+	for j := len(activities) - 1; j >= 0; j-- {
+		recorded = append(recorded, activities[j].Event())
+	}
+	if !reflect.DeepEqual(recorded, want) {
+		t.Errorf("recorded %v, want %v", recorded, want)
+	}
+	if a := activities[len(activities)-1]; a.TargetID != first.ID || a.UserID != "u1" || a.Before != "" || a.After == "" {
+		t.Errorf("first activity = %+v, want creation of checklist(%s) by u1", a, first.ID)
+	}
+
+	// They are published after they are committed
+	got := []string{}
+	for range want {
+		select {
+		case a := <-published:
+			got = append(got, a.Event())
+		case <-time.After(5 * time.Second):
+			t.Fatalf("published %v, want %v", got, want)
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+}
+
+func TestRollbackChecklist(t *testing.T) {
+	ctx := context.Background()
+	dbm := memory.NewDBManager()
+	i := newInteractors(t, &dbm, nil)
+	failing := newInteractors(t, &dbm, failingActivityRepository{})
+
+	list := createList(t, i, "u1")
+	item, err := i.item.Create(ctx, model.Item{ListID: list.ID, UserID: "u1", Title: "item"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A Checklist is not kept if recording an Activity fails
+	_, err = failing.checklist.Create(ctx, model.Checklist{ItemID: item.ID, UserID: "u1", Title: "checklist"})
+	if !errors.Is(err, errActivity) {
+		t.Fatalf("Create() error = %v, want %v", err, errActivity)
+	}
+
+	tx := dbm.TransactionManager.BeginTransaction(ctx, false)
+	checklists, err := dbm.ChecklistDBManager.Find(ctx, tx, usecase.ChecklistFilter{
+		ItemIDs: []string{item.ID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checklists) != 0 {
+		t.Errorf("got %d checklists after rollback, want none", len(checklists))
+	}
+}
@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/x-color/vue-trello/model"
+)
+
+// CommentUsecase is interface. It defines to control Comments in a Item.
+type CommentUsecase interface {
+	GetComments(ctx context.Context, item model.Item, offset, limit int) (model.Comments, bool, error)
+	Create(ctx context.Context, comment model.Comment) (model.Comment, error)
+	Delete(ctx context.Context, comment model.Comment) error
+	Update(ctx context.Context, comment model.Comment) (model.Comment, error)
+}
+
+// CommentInteractor includes repogitories and a logger.
+type CommentInteractor struct {
+	txRepo      TransactionRepository
+	commentRepo CommentRepository
+	itemRepo    ItemRepository
+	listRepo    ListRepository
+	memberRepo  MemberRepository
+	userRepo    UserRepository
+	logger      Logger
+}
+
+// NewCommentInteractor generates new interactor for a Comment.
+func NewCommentInteractor(
+	txRepo TransactionRepository,
+	commentRepo CommentRepository,
+	itemRepo ItemRepository,
+	listRepo ListRepository,
+	memberRepo MemberRepository,
+	userRepo UserRepository,
+	logger Logger,
+) (CommentInteractor, error) {
+	i := CommentInteractor{
+		txRepo:      txRepo,
+		commentRepo: commentRepo,
+		itemRepo:    itemRepo,
+		listRepo:    listRepo,
+		memberRepo:  memberRepo,
+		userRepo:    userRepo,
+		logger:      logger,
+	}
+	return i, nil
+}
+
+// GetComments returns a page of Comments in a Item from newest to oldest.
+// The second returned value reports whether older Comments remain.
+func (i *CommentInteractor) GetComments(ctx context.Context, item model.Item, offset, limit int) (model.Comments, bool, error) {
+	if offset < 0 || limit <= 0 {
+		err := model.InvalidContentError{
+			UserID: item.UserID,
+			Err:    nil,
+			ID:     item.ID,
+			Act:    "validate page of comments",
+		}
+		logError(i.logger, err)
+		return model.Comments{}, false, err
+	}
+
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	if _, err := authorizeItem(ctx, tx, i.itemRepo, i.listRepo, i.memberRepo, item.ID, item.UserID, model.VIEWER); err != nil {
+		logError(i.logger, err)
+		return model.Comments{}, false, err
+	}
+
+	// Get one more comment to know whether a next page exists.
+	comments, err := i.commentRepo.Find(ctx, tx, CommentFilter{
+		ItemIDs: []string{item.ID},
+		Sort:    Sort{Key: SortByCreatedAt, Desc: true},
+		Page:    Page{Offset: offset, Limit: limit + 1},
+	})
+	if err != nil {
+		logError(i.logger, err)
+		return model.Comments{}, false, err
+	}
+	hasNext := len(comments) > limit
+	if hasNext {
+		comments = comments[:limit]
+	}
+
+	// Fill names of authors
+	names := map[string]string{}
+	for j, c := range comments {
+		if _, ok := names[c.UserID]; !ok {
+			// An author of a deleted account has no name.
+			u, err := i.userRepo.Find(ctx, tx, UserFilter{
+				IDs: []string{c.UserID},
+			})
+			if err != nil && !errors.Is(err, model.NotFoundError{}) {
+				logError(i.logger, err)
+				return model.Comments{}, false, err
+			}
+			names[c.UserID] = u.Name
+		}
+		comments[j].UserName = names[c.UserID]
+	}
+	i.logger.Info(formatLogMsg(item.UserID, "Get comments in item("+item.ID+")"))
+
+	return comments, hasNext, nil
+}
+
+// Create saves new Comment to a repository and returns created Comment.
+func (i *CommentInteractor) Create(ctx context.Context, comment model.Comment) (model.Comment, error) {
+	comment.ID = uuid.New().String()
+	if err := validateComment(comment); err != nil {
+		logError(i.logger, err)
+		return model.Comment{}, err
+	}
+
+	err := runInTx(ctx, i.txRepo, i.logger, comment.UserID, func(tx Transaction) error {
+		if _, err := authorizeItem(ctx, tx, i.itemRepo, i.listRepo, i.memberRepo, comment.ItemID, comment.UserID, model.EDITOR); err != nil {
+			return err
+		}
+
+		author, err := i.userRepo.Find(ctx, tx, UserFilter{
+			IDs: []string{comment.UserID},
+		})
+		if err != nil {
+			return err
+		}
+		comment.UserName = author.Name
+
+		now := time.Now()
+		comment.CreatedAt = now
+		comment.UpdatedAt = now
+		if err := i.commentRepo.Create(ctx, tx, comment); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(comment.UserID, "Create comment("+comment.ID+")"))
+
+		return nil
+	})
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	return comment, nil
+}
+
+// Delete removes Comment in repository. An author or an owner of the Board can delete it.
+func (i *CommentInteractor) Delete(ctx context.Context, comment model.Comment) error {
+	return runInTx(ctx, i.txRepo, i.logger, comment.UserID, func(tx Transaction) error {
+		old, err := i.findComment(ctx, tx, comment)
+		if err != nil {
+			return err
+		}
+
+		required := model.VIEWER
+		if old.UserID != comment.UserID {
+			required = model.OWNER
+		}
+		if _, err := authorizeItem(ctx, tx, i.itemRepo, i.listRepo, i.memberRepo, old.ItemID, comment.UserID, required); err != nil {
+			return err
+		}
+
+		if err := i.commentRepo.Delete(ctx, tx, old); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(comment.UserID, "Delete comment("+old.ID+")"))
+
+		return nil
+	})
+}
+
+// Update replaces text of a Comment and returns new Comment. Only an author can edit it.
+func (i *CommentInteractor) Update(ctx context.Context, comment model.Comment) (model.Comment, error) {
+	if err := validateComment(comment); err != nil {
+		logError(i.logger, err)
+		return model.Comment{}, err
+	}
+
+	var updated model.Comment
+	err := runInTx(ctx, i.txRepo, i.logger, comment.UserID, func(tx Transaction) error {
+		old, err := i.findComment(ctx, tx, comment)
+		if err != nil {
+			return err
+		}
+		if old.UserID != comment.UserID {
+			return model.ForbiddenError{
+				UserID: comment.UserID,
+				Err:    nil,
+				ID:     comment.ID,
+				Act:    "edit comment of other user",
+			}
+		}
+
+		// The author may have left the Board.
+		if _, err := authorizeItem(ctx, tx, i.itemRepo, i.listRepo, i.memberRepo, old.ItemID, comment.UserID, model.VIEWER); err != nil {
+			return err
+		}
+
+		author, err := i.userRepo.Find(ctx, tx, UserFilter{
+			IDs: []string{comment.UserID},
+		})
+		if err != nil {
+			return err
+		}
+		old.UserName = author.Name
+
+		query := map[string]interface{}{
+			"Text": comment.Text,
+		}
+		if err := i.commentRepo.Update(ctx, tx, old, query); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(comment.UserID, "Update comment("+comment.ID+")"))
+
+		updated = old
+		updated.Text = comment.Text
+		updated.UpdatedAt = time.Now()
+		return nil
+	})
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	return updated, nil
+}
+
+// findComment returns saved Comment if it is in the Item.
+func (i *CommentInteractor) findComment(ctx context.Context, tx Transaction, comment model.Comment) (model.Comment, error) {
+	old, err := i.commentRepo.FindByID(ctx, tx, comment.ID)
+	if err != nil {
+		return model.Comment{}, err
+	}
+	if old.ItemID != comment.ItemID {
+		return model.Comment{}, model.NotFoundError{
+			UserID: comment.UserID,
+			Err:    nil,
+			ID:     comment.ID,
+			Act:    "find comment in item(" + comment.ItemID + ")",
+		}
+	}
+	return old, nil
+}
+
+func validateComment(comment model.Comment) error {
+	if comment.ID == "" || comment.ItemID == "" || comment.UserID == "" || comment.Text == "" {
+		return model.InvalidContentError{
+			UserID: comment.UserID,
+			Err:    nil,
+			ID:     comment.ID,
+			Act:    "validate contents in comment",
+		}
+	}
+	return nil
+}
+
+// deleteComments removes Comments in a deleted Item.
+func deleteComments(ctx context.Context, tx Transaction, commentRepo CommentRepository, item model.Item) error {
+	comments, err := commentRepo.Find(ctx, tx, CommentFilter{
+		ItemIDs: []string{item.ID},
+	})
+	if err != nil {
+		return err
+	}
+	for _, comment := range comments {
+		if err := commentRepo.Delete(ctx, tx, comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/x-color/vue-trello/model"
+)
+
+// replayPageSize is a number of Activities got at once to send them to a stream.
+const replayPageSize = 100
+
+// EventUsecase is interface. It defines to stream changes in a Board.
+type EventUsecase interface {
+	Stream(ctx context.Context, user model.User, board model.Board, cursor string, stream EventStream) error
+}
+
+// EventStream is interface. It defines to send changes in a Board to a client.
+// Open is called once after a user is authorized and before any Activity is sent.
+type EventStream interface {
+	Open() error
+	Send(activity model.Activity) error
+}
+
+// EventInteractor includes repogitories, a bus to subscribe changes and a logger.
+type EventInteractor struct {
+	txRepo       TransactionRepository
+	activityRepo ActivityRepository
+	memberRepo   MemberRepository
+	userRepo     UserRepository
+	bus          EventBus
+	logger       Logger
+}
+
+// NewEventInteractor generates new interactor to stream changes.
+func NewEventInteractor(
+	txRepo TransactionRepository,
+	activityRepo ActivityRepository,
+	memberRepo MemberRepository,
+	userRepo UserRepository,
+	bus EventBus,
+	logger Logger,
+) (EventInteractor, error) {
+	i := EventInteractor{
+		txRepo:       txRepo,
+		activityRepo: activityRepo,
+		memberRepo:   memberRepo,
+		userRepo:     userRepo,
+		bus:          bus,
+		logger:       logger,
+	}
+	return i, nil
+}
+
+// Stream sends changes in a Board to stream as Activities until ctx is done. Only members of the Board
+// can stream them. If cursor is not empty, Activities recorded after the Activity had cursor as ID are
+// sent first, so a client can resume changes without loss after reconnecting. Activities are sent in
+// order of recording, because they are read from the repository and the bus only tells that some
+// are recorded. The stream ends when the user leaves the Board or the Board is deleted.
+func (i *EventInteractor) Stream(ctx context.Context, user model.User, board model.Board, cursor string, stream EventStream) error {
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	if _, err := authorize(ctx, tx, i.memberRepo, board.ID, user.ID, model.VIEWER); err != nil {
+		logError(i.logger, err)
+		return err
+	}
+
+	// Subscribe before reading, so that changes committed while reading are not missed.
+	published, cancel := i.bus.Subscribe(board.ID)
+	defer func() { cancel() }()
+
+	// A new stream starts after the newest Activity.
+	last := cursor
+	if last == "" {
+		newest, err := i.activityRepo.FindPage(ctx, tx, board.ID, "", 1)
+		if err != nil {
+			logError(i.logger, err)
+			return err
+		}
+		if len(newest) > 0 {
+			last = newest[0].ID
+		}
+	}
+
+	replay := model.Activities{}
+	if cursor != "" {
+		var err error
+		if replay, err = i.findSince(ctx, tx, board.ID, cursor); err != nil {
+			logError(i.logger, err)
+			return err
+		}
+	}
+
+	if err := stream.Open(); err != nil {
+		return err
+	}
+	i.logger.Info(formatLogMsg(user.ID, "Start streaming board("+board.ID+")"))
+	defer i.logger.Info(formatLogMsg(user.ID, "Stop streaming board("+board.ID+")"))
+
+	names := map[string]string{}
+	activities := replay
+	for {
+		end, err := i.send(ctx, tx, user, board, activities, names, stream)
+		if err != nil || end {
+			return err
+		}
+		if len(activities) > 0 {
+			last = activities[len(activities)-1].ID
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-published:
+			if !ok {
+				// A slow subscriber is dropped by the bus. It only misses notifications, so subscribe again.
+				published, cancel = i.bus.Subscribe(board.ID)
+			}
+		}
+		drain(published)
+
+		if activities, err = i.findSince(ctx, tx, board.ID, last); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			logError(i.logger, err)
+			return err
+		}
+	}
+}
+
+// send sends Activities to stream and reports whether the stream ends. Members are removed with
+// a deleted Board, so deletion of the Board is sent even if the user is no longer a member.
+func (i *EventInteractor) send(ctx context.Context, tx Transaction, user model.User, board model.Board, activities model.Activities, names map[string]string, stream EventStream) (bool, error) {
+	if len(activities) == 0 {
+		return false, nil
+	}
+
+	if _, err := authorize(ctx, tx, i.memberRepo, board.ID, user.ID, model.VIEWER); err != nil {
+		if ctx.Err() != nil {
+			return true, nil
+		}
+		if !errors.Is(err, model.NotFoundError{}) && !errors.Is(err, model.ForbiddenError{}) {
+			logError(i.logger, err)
+			return true, err
+		}
+		deleted := model.Activities{}
+		for _, a := range activities {
+			if a.Target == model.BOARD && a.Action == model.DELETE {
+				deleted = append(deleted, a)
+			}
+		}
+		activities = deleted
+	}
+
+	if err := fillUserNames(ctx, tx, i.userRepo, activities, names); err != nil {
+		logError(i.logger, err)
+		return true, err
+	}
+	for _, a := range activities {
+		if err := stream.Send(a); err != nil {
+			return true, err
+		}
+		if a.Target == model.BOARD && a.Action == model.DELETE {
+			return true, nil
+		}
+	}
+	return len(activities) == 0, nil
+}
+
+// findSince gets all Activities in a Board recorded after the Activity had cursor as ID.
+// All Activities in the Board are got if cursor is empty.
+func (i *EventInteractor) findSince(ctx context.Context, tx Transaction, boardID, cursor string) (model.Activities, error) {
+	activities := model.Activities{}
+	for {
+		var page model.Activities
+		var err error
+		if cursor == "" {
+			page, err = i.activityRepo.Find(ctx, tx, ActivityFilter{
+				BoardIDs: []string{boardID},
+				Page:     Page{Limit: replayPageSize},
+			})
+		} else {
+			page, err = i.activityRepo.FindSince(ctx, tx, boardID, cursor, replayPageSize)
+		}
+		if err != nil {
+			return model.Activities{}, err
+		}
+		activities = append(activities, page...)
+
+		if len(page) < replayPageSize {
+			return activities, nil
+		}
+		cursor = page[len(page)-1].ID
+	}
+}
+
+// drain discards notifications which have been published, because the following read gets all of them.
+func drain(published <-chan model.Activity) {
+	for {
+		select {
+		case _, ok := <-published:
+			if !ok {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package usecase_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/x-color/vue-trello/interface/presenter/logging"
+	"github.com/x-color/vue-trello/interface/pubsub"
+	"github.com/x-color/vue-trello/interface/repository/memory"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// chanStream passes Activities sent to a stream to a channel.
+type chanStream struct {
+	opened chan bool
+	sent   chan model.Activity
+}
+
+func newChanStream() chanStream {
+	return chanStream{opened: make(chan bool, 1), sent: make(chan model.Activity, 10)}
+}
+
+func (s chanStream) Open() error {
+	s.opened <- true
+	return nil
+}
+
+func (s chanStream) Send(activity model.Activity) error {
+	s.sent <- activity
+	return nil
+}
+
+func (s chanStream) receive(t *testing.T) model.Activity {
+	t.Helper()
+	select {
+	case a := <-s.sent:
+		return a
+	case <-time.After(5 * time.Second):
+		t.Fatal("no activity is sent")
+		return model.Activity{}
+	}
+}
+
+func TestStreamOrder(t *testing.T) {
+	dbm := memory.NewDBManager()
+	i := newInteractors(t, &dbm, nil)
+	list := createList(t, i, "u1")
+
+	logger, err := logging.NewLogger(ioutil.Discard, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus := pubsub.NewBus()
+	events, err := usecase.NewEventInteractor(
+		&dbm.TransactionManager,
+		&dbm.ActivityDBManager,
+		&dbm.MemberDBManager,
+		&dbm.UserDBManager,
+		bus,
+		&logger,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := newChanStream()
+	done := make(chan error, 1)
+	go func() {
+		done <- events.Stream(ctx, model.User{ID: "u1"}, model.Board{ID: list.BoardID}, "", stream)
+	}()
+	<-stream.opened
+
+	// The second Activity is published before the first one, and the first one is never published
+	recorded := model.Activities{}
+	for _, id := range []string{"a1", "a2"} {
+		a := model.Activity{ID: id, BoardID: list.BoardID, UserID: "u1", Action: model.UPDATE, Target: model.ITEM}
+		tx := dbm.TransactionManager.BeginTransaction(ctx, false)
+		if err := dbm.ActivityDBManager.Create(ctx, tx, a); err != nil {
+			t.Fatal(err)
+		}
+		recorded = append(recorded, a)
+	}
+	bus.Publish(recorded[1])
+
+	for _, want := range recorded {
+		if got := stream.receive(t); got.ID != want.ID {
+			t.Errorf("sent %s, want %s", got.ID, want.ID)
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Stream() error = %v", err)
+	}
+
+	// A resumed stream sends Activities after the cursor first
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	stream = newChanStream()
+	go func() {
+		done <- events.Stream(ctx, model.User{ID: "u1"}, model.Board{ID: list.BoardID}, "a1", stream)
+	}()
+	if got := stream.receive(t); got.ID != "a2" {
+		t.Errorf("sent %s after resuming, want a2", got.ID)
+	}
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/x-color/vue-trello/model"
+)
+
+// ExportUsecase is interface. It defines to export a Board with all data in it.
+type ExportUsecase interface {
+	Export(ctx context.Context, board model.Board) (model.Board, error)
+}
+
+// ExportInteractor includes repogitories and a logger.
+type ExportInteractor struct {
+	txRepo     TransactionRepository
+	boardRepo  BoardRepository
+	listRepo   ListRepository
+	itemRepo   ItemRepository
+	memberRepo MemberRepository
+	logger     Logger
+}
+
+// NewExportInteractor generates new interactor to export a Board.
+func NewExportInteractor(
+	txRepo TransactionRepository,
+	boardRepo BoardRepository,
+	listRepo ListRepository,
+	itemRepo ItemRepository,
+	memberRepo MemberRepository,
+	logger Logger,
+) (ExportInteractor, error) {
+	i := ExportInteractor{
+		txRepo:     txRepo,
+		boardRepo:  boardRepo,
+		listRepo:   listRepo,
+		itemRepo:   itemRepo,
+		memberRepo: memberRepo,
+		logger:     logger,
+	}
+	return i, nil
+}
+
+// Export returns a Board including Lists and Items in order.
+func (i *ExportInteractor) Export(ctx context.Context, board model.Board) (model.Board, error) {
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	if _, err := authorize(ctx, tx, i.memberRepo, board.ID, board.UserID, model.VIEWER); err != nil {
+		logError(i.logger, err)
+		return model.Board{}, err
+	}
+
+	userID := board.UserID
+	board, err := i.boardRepo.FindByID(ctx, tx, board.ID)
+	if err != nil {
+		logError(i.logger, err)
+		return model.Board{}, err
+	}
+
+	lists, err := i.listRepo.Find(ctx, tx, ListFilter{
+		BoardIDs: []string{board.ID},
+	})
+	if err != nil {
+		logError(i.logger, err)
+		return model.Board{}, err
+	}
+	board.Lists = sortLists(lists)
+
+	listIDs := []string{}
+	for _, list := range board.Lists {
+		listIDs = append(listIDs, list.ID)
+	}
+	items, err := i.itemRepo.Find(ctx, tx, ItemFilter{
+		ListIDs: listIDs,
+	})
+	if err != nil {
+		logError(i.logger, err)
+		return model.Board{}, err
+	}
+
+	itemsOf := map[string]model.Items{}
+	for _, item := range sortItems(items) {
+		itemsOf[item.ListID] = append(itemsOf[item.ListID], item)
+	}
+	for j, list := range board.Lists {
+		board.Lists[j].Items = itemsOf[list.ID]
+	}
+
+	i.logger.Info(formatLogMsg(userID, "Export board("+board.ID+")"))
+	return board, nil
+}
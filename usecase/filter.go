@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/x-color/vue-trello/model"
+)
+
+// Filters select data got by Find methods of repositories.
+// A nil slice of a filter adds no condition, and an empty slice matches no data.
+// A value matches a slice if it equals one of values in the slice.
+// Text conditions (e.g. TitleContains) match data including the text case-insensitively.
+
+// SortKey defines a field to sort found data.
+type SortKey string
+
+// SortKey pattern. Each repository supports keys of fields its data has.
+const (
+	SortByRank      SortKey = "rank"
+	SortByTitle     SortKey = "title"
+	SortByName      SortKey = "name"
+	SortByDueDate   SortKey = "due_date"
+	SortByCreatedAt SortKey = "created_at"
+)
+
+// Sort defines an order of found data. Data having the same value is sorted by ID.
+// Data is in no specific order if Key is empty.
+type Sort struct {
+	Key  SortKey
+	Desc bool
+}
+
+// Page defines a range of found data. All data from Offset is got if Limit is 0.
+type Page struct {
+	Offset int
+	Limit  int
+}
+
+// BoardFilter selects Boards.
+type BoardFilter struct {
+	IDs           []string
+	UserIDs       []string
+	TitleContains string
+	Sort          Sort
+	Page          Page
+}
+
+// ListFilter selects Lists.
+type ListFilter struct {
+	IDs           []string
+	BoardIDs      []string
+	TitleContains string
+	Sort          Sort
+	Page          Page
+}
+
+// ItemFilter selects Items.
+// Items having one of TagIDs are selected. Completed is ignored if it is nil.
+// If DueUntil is not zero, Items having a due date until DueUntil are selected.
+type ItemFilter struct {
+	IDs           []string
+	ListIDs       []string
+	TagIDs        []string
+	TitleContains string
+	Completed     *bool
+	DueUntil      time.Time
+	Sort          Sort
+	Page          Page
+}
+
+// ChecklistFilter selects Checklists.
+type ChecklistFilter struct {
+	IDs     []string
+	ItemIDs []string
+	Sort    Sort
+	Page    Page
+}
+
+// CheckItemFilter selects CheckItems.
+type CheckItemFilter struct {
+	IDs          []string
+	ChecklistIDs []string
+	ItemIDs      []string
+	Sort         Sort
+	Page         Page
+}
+
+// CommentFilter selects Comments.
+type CommentFilter struct {
+	IDs          []string
+	ItemIDs      []string
+	UserIDs      []string
+	TextContains string
+	Sort         Sort
+	Page         Page
+}
+
+// TagFilter selects Tags.
+type TagFilter struct {
+	IDs          []string
+	UserIDs      []string
+	BoardIDs     []string
+	NameContains string
+	Sort         Sort
+	Page         Page
+}
+
+// MemberFilter selects Members. Members are got in order of joining.
+type MemberFilter struct {
+	BoardIDs []string
+	UserIDs  []string
+	Roles    model.Roles
+	Page     Page
+}
+
+// ActivityFilter selects Activities. Activities are got in order of recording, or in reverse order if Desc is true.
+// If After is not empty, Activities recorded after the Activity had After as ID are selected.
+type ActivityFilter struct {
+	IDs      []string
+	BoardIDs []string
+	After    string
+	Desc     bool
+	Page     Page
+}
+
+// WebhookFilter selects Webhooks. Webhooks are got in order of registration.
+type WebhookFilter struct {
+	IDs      []string
+	BoardIDs []string
+	Page     Page
+}
+
+// AccessTokenFilter selects AccessTokens. AccessTokens are got in order of creation.
+type AccessTokenFilter struct {
+	IDs     []string
+	UserIDs []string
+	Page    Page
+}
+
+// SessionFilter selects Sessions. Sessions are got in order of creation.
+type SessionFilter struct {
+	IDs     []string
+	UserIDs []string
+	Page    Page
+}
+
+// DeliveryFilter selects Deliveries.
+// If DueUntil is not zero, Deliveries to be attempted until DueUntil are selected.
+type DeliveryFilter struct {
+	IDs        []string
+	WebhookIDs []string
+	Statuses   []model.DeliveryStatus
+	DueUntil   time.Time
+	Sort       Sort
+	Page       Page
+}
+
+// UserFilter selects a User.
+type UserFilter struct {
+	IDs   []string
+	Names []string
+}
@@ -0,0 +1,353 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/x-color/vue-trello/model"
+)
+
+// FsckUsecase is interface. It defines to check and repair order of data.
+type FsckUsecase interface {
+	Check(ctx context.Context, repair bool) (model.Anomalies, error)
+}
+
+// FsckInteractor includes repogitories and a logger.
+type FsckInteractor struct {
+	txRepo        TransactionRepository
+	boardRepo     BoardRepository
+	listRepo      ListRepository
+	itemRepo      ItemRepository
+	checklistRepo ChecklistRepository
+	checkItemRepo CheckItemRepository
+	logger        Logger
+}
+
+// NewFsckInteractor generates new interactor to check order of data.
+func NewFsckInteractor(
+	txRepo TransactionRepository,
+	boardRepo BoardRepository,
+	listRepo ListRepository,
+	itemRepo ItemRepository,
+	checklistRepo ChecklistRepository,
+	checkItemRepo CheckItemRepository,
+	logger Logger,
+) (FsckInteractor, error) {
+	i := FsckInteractor{
+		txRepo:        txRepo,
+		boardRepo:     boardRepo,
+		listRepo:      listRepo,
+		itemRepo:      itemRepo,
+		checklistRepo: checklistRepo,
+		checkItemRepo: checkItemRepo,
+		logger:        logger,
+	}
+	return i, nil
+}
+
+const fsckUserID = "(fsck)"
+
+// Check scans ranks of all Boards, Lists, Items, Checklists and CheckItems and returns anomalies
+// ordered by parents. If repair is true, ranks in a parent including anomalies are rebalanced
+// in current order and orphans are deleted. All repairs are done in a transaction,
+// so nothing is changed if an error occurs.
+func (i *FsckInteractor) Check(ctx context.Context, repair bool) (model.Anomalies, error) {
+	if !repair {
+		anomalies, err := i.check(ctx, i.txRepo.BeginTransaction(ctx, false), false)
+		if err != nil {
+			logError(i.logger, err)
+			return model.Anomalies{}, err
+		}
+		return anomalies, nil
+	}
+
+	var anomalies model.Anomalies
+	err := runInTx(ctx, i.txRepo, i.logger, fsckUserID, func(tx Transaction) error {
+		var err error
+		anomalies, err = i.check(ctx, tx, true)
+		return err
+	})
+	if err != nil {
+		return model.Anomalies{}, err
+	}
+
+	return anomalies, nil
+}
+
+func (i *FsckInteractor) check(ctx context.Context, tx Transaction, repair bool) (model.Anomalies, error) {
+	anomalies := model.Anomalies{}
+
+	// Boards are ordered in each owner.
+	boards, err := i.boardRepo.Find(ctx, tx, BoardFilter{})
+	if err != nil {
+		return model.Anomalies{}, err
+	}
+	boardByID := map[string]model.Board{}
+	boardsByUser := map[string]model.Boards{}
+	for _, b := range boards {
+		boardByID[b.ID] = b
+		boardsByUser[b.UserID] = append(boardsByUser[b.UserID], b)
+	}
+	userIDs := []string{}
+	for userID := range boardsByUser {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+	for _, userID := range userIDs {
+		boards := sortBoards(boardsByUser[userID])
+		entries := model.Anomalies{}
+		for _, b := range boards {
+			entries = append(entries, model.Anomaly{
+				UserID:   userID,
+				BoardID:  b.ID,
+				Target:   model.BOARD,
+				ID:       b.ID,
+				ParentID: userID,
+				Rank:     b.Rank,
+			})
+		}
+		found, err := checkRanks(entries, repair, func(j int, rank string) error {
+			return i.boardRepo.Update(ctx, tx, boards[j], map[string]interface{}{"Rank": rank})
+		})
+		if err != nil {
+			return model.Anomalies{}, err
+		}
+		anomalies = append(anomalies, found...)
+	}
+
+	// Lists are ordered in each Board.
+	lists, err := i.listRepo.Find(ctx, tx, ListFilter{})
+	if err != nil {
+		return model.Anomalies{}, err
+	}
+	listByID := map[string]model.List{}
+	listsByBoard := map[string]model.Lists{}
+	for _, l := range sortLists(lists) {
+		if _, ok := boardByID[l.BoardID]; !ok {
+			anomalies = append(anomalies, orphan(l.UserID, "", model.LIST, l.ID, l.BoardID, l.Rank, repair))
+			if repair {
+				if err := i.listRepo.Delete(ctx, tx, l); err != nil {
+					return model.Anomalies{}, err
+				}
+			}
+			continue
+		}
+		listByID[l.ID] = l
+		listsByBoard[l.BoardID] = append(listsByBoard[l.BoardID], l)
+	}
+	boardIDs := []string{}
+	for boardID := range listsByBoard {
+		boardIDs = append(boardIDs, boardID)
+	}
+	sort.Strings(boardIDs)
+	for _, boardID := range boardIDs {
+		lists := listsByBoard[boardID]
+		entries := model.Anomalies{}
+		for _, l := range lists {
+			entries = append(entries, model.Anomaly{
+				UserID:   boardByID[boardID].UserID,
+				BoardID:  boardID,
+				Target:   model.LIST,
+				ID:       l.ID,
+				ParentID: boardID,
+				Rank:     l.Rank,
+			})
+		}
+		found, err := checkRanks(entries, repair, func(j int, rank string) error {
+			return i.listRepo.Update(ctx, tx, lists[j], map[string]interface{}{"Rank": rank})
+		})
+		if err != nil {
+			return model.Anomalies{}, err
+		}
+		anomalies = append(anomalies, found...)
+	}
+
+	// Items are ordered in each List.
+	items, err := i.itemRepo.Find(ctx, tx, ItemFilter{})
+	if err != nil {
+		return model.Anomalies{}, err
+	}
+	itemByID := map[string]model.Item{}
+	itemsByList := map[string]model.Items{}
+	for _, it := range sortItems(items) {
+		if _, ok := listByID[it.ListID]; !ok {
+			anomalies = append(anomalies, orphan(it.UserID, "", model.ITEM, it.ID, it.ListID, it.Rank, repair))
+			if repair {
+				if err := i.itemRepo.Delete(ctx, tx, it); err != nil {
+					return model.Anomalies{}, err
+				}
+			}
+			continue
+		}
+		itemByID[it.ID] = it
+		itemsByList[it.ListID] = append(itemsByList[it.ListID], it)
+	}
+	listIDs := []string{}
+	for listID := range itemsByList {
+		listIDs = append(listIDs, listID)
+	}
+	sort.Strings(listIDs)
+	for _, listID := range listIDs {
+		items := itemsByList[listID]
+		boardID := listByID[listID].BoardID
+		entries := model.Anomalies{}
+		for _, it := range items {
+			entries = append(entries, model.Anomaly{
+				UserID:   boardByID[boardID].UserID,
+				BoardID:  boardID,
+				Target:   model.ITEM,
+				ID:       it.ID,
+				ParentID: listID,
+				Rank:     it.Rank,
+			})
+		}
+		found, err := checkRanks(entries, repair, func(j int, rank string) error {
+			return i.itemRepo.Update(ctx, tx, items[j], map[string]interface{}{"Rank": rank})
+		})
+		if err != nil {
+			return model.Anomalies{}, err
+		}
+		anomalies = append(anomalies, found...)
+	}
+
+	// Checklists are ordered in each Item.
+	checklists, err := i.checklistRepo.Find(ctx, tx, ChecklistFilter{})
+	if err != nil {
+		return model.Anomalies{}, err
+	}
+	checklistByID := map[string]model.Checklist{}
+	checklistsByItem := map[string]model.Checklists{}
+	for _, c := range sortChecklists(checklists) {
+		if _, ok := itemByID[c.ItemID]; !ok {
+			anomalies = append(anomalies, orphan(c.UserID, "", model.CHECKLIST, c.ID, c.ItemID, c.Rank, repair))
+			if repair {
+				if err := i.checklistRepo.Delete(ctx, tx, c); err != nil {
+					return model.Anomalies{}, err
+				}
+			}
+			continue
+		}
+		checklistByID[c.ID] = c
+		checklistsByItem[c.ItemID] = append(checklistsByItem[c.ItemID], c)
+	}
+	itemIDs := []string{}
+	for itemID := range checklistsByItem {
+		itemIDs = append(itemIDs, itemID)
+	}
+	sort.Strings(itemIDs)
+	for _, itemID := range itemIDs {
+		checklists := checklistsByItem[itemID]
+		boardID := listByID[itemByID[itemID].ListID].BoardID
+		entries := model.Anomalies{}
+		for _, c := range checklists {
+			entries = append(entries, model.Anomaly{
+				UserID:   boardByID[boardID].UserID,
+				BoardID:  boardID,
+				Target:   model.CHECKLIST,
+				ID:       c.ID,
+				ParentID: itemID,
+				Rank:     c.Rank,
+			})
+		}
+		found, err := checkRanks(entries, repair, func(j int, rank string) error {
+			return i.checklistRepo.Update(ctx, tx, checklists[j], map[string]interface{}{"Rank": rank})
+		})
+		if err != nil {
+			return model.Anomalies{}, err
+		}
+		anomalies = append(anomalies, found...)
+	}
+
+	// CheckItems are ordered in each Checklist.
+	checkItems, err := i.checkItemRepo.Find(ctx, tx, CheckItemFilter{})
+	if err != nil {
+		return model.Anomalies{}, err
+	}
+	checkItemsByChecklist := map[string]model.CheckItems{}
+	for _, ci := range sortCheckItems(checkItems) {
+		if _, ok := checklistByID[ci.ChecklistID]; !ok {
+			anomalies = append(anomalies, orphan(ci.UserID, "", model.CHECKITEM, ci.ID, ci.ChecklistID, ci.Rank, repair))
+			if repair {
+				if err := i.checkItemRepo.Delete(ctx, tx, ci); err != nil {
+					return model.Anomalies{}, err
+				}
+			}
+			continue
+		}
+		checkItemsByChecklist[ci.ChecklistID] = append(checkItemsByChecklist[ci.ChecklistID], ci)
+	}
+	checklistIDs := []string{}
+	for checklistID := range checkItemsByChecklist {
+		checklistIDs = append(checklistIDs, checklistID)
+	}
+	sort.Strings(checklistIDs)
+	for _, checklistID := range checklistIDs {
+		checkItems := checkItemsByChecklist[checklistID]
+		boardID := listByID[itemByID[checklistByID[checklistID].ItemID].ListID].BoardID
+		entries := model.Anomalies{}
+		for _, ci := range checkItems {
+			entries = append(entries, model.Anomaly{
+				UserID:   boardByID[boardID].UserID,
+				BoardID:  boardID,
+				Target:   model.CHECKITEM,
+				ID:       ci.ID,
+				ParentID: checklistID,
+				Rank:     ci.Rank,
+			})
+		}
+		found, err := checkRanks(entries, repair, func(j int, rank string) error {
+			return i.checkItemRepo.Update(ctx, tx, checkItems[j], map[string]interface{}{"Rank": rank})
+		})
+		if err != nil {
+			return model.Anomalies{}, err
+		}
+		anomalies = append(anomalies, found...)
+	}
+
+	i.logger.Info(formatLogMsg(fsckUserID, "Find "+strconv.Itoa(len(anomalies))+" anomalies"))
+	return anomalies, nil
+}
+
+// checkRanks returns anomalies in entries of a parent sorted by rank.
+// If repair is true and anomalies are found, update is called with new ranks of all entries.
+func checkRanks(entries model.Anomalies, repair bool, update func(j int, rank string) error) (model.Anomalies, error) {
+	anomalies := model.Anomalies{}
+	for j, e := range entries {
+		switch {
+		case e.Rank == "":
+			e.Problem = model.NORANK
+		case !model.ValidRank(e.Rank) || len(e.Rank) > maxRankLength:
+			e.Problem = model.INVALIDRANK
+		case j > 0 && entries[j-1].Rank == e.Rank:
+			e.Problem = model.DUPLICATEDRANK
+		default:
+			continue
+		}
+		e.Repaired = repair
+		anomalies = append(anomalies, e)
+	}
+
+	if repair && len(anomalies) > 0 {
+		ranks := model.Ranks(len(entries))
+		for j := range entries {
+			if err := update(j, ranks[j]); err != nil {
+				return model.Anomalies{}, err
+			}
+		}
+	}
+	return anomalies, nil
+}
+
+func orphan(userID, boardID string, target model.Target, id, parentID, rank string, repair bool) model.Anomaly {
+	return model.Anomaly{
+		UserID:   userID,
+		BoardID:  boardID,
+		Target:   target,
+		ID:       id,
+		ParentID: parentID,
+		Rank:     rank,
+		Problem:  model.ORPHAN,
+		Repaired: repair,
+	}
+}
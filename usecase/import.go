@@ -0,0 +1,297 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/x-color/vue-trello/model"
+)
+
+// ImportUsecase is interface. It defines to import a Board from other services.
+type ImportUsecase interface {
+	Import(ctx context.Context, board model.Board) (model.Board, model.Skips, error)
+}
+
+// ImportInteractor includes repogitories and a logger.
+type ImportInteractor struct {
+	txRepo       TransactionRepository
+	boardRepo    BoardRepository
+	listRepo     ListRepository
+	itemRepo     ItemRepository
+	tagRepo      TagRepository
+	memberRepo   MemberRepository
+	activityRepo ActivityRepository
+	logger       Logger
+}
+
+// NewImportInteractor generates new interactor to import a Board.
+func NewImportInteractor(
+	txRepo TransactionRepository,
+	boardRepo BoardRepository,
+	listRepo ListRepository,
+	itemRepo ItemRepository,
+	tagRepo TagRepository,
+	memberRepo MemberRepository,
+	activityRepo ActivityRepository,
+	logger Logger,
+) (ImportInteractor, error) {
+	i := ImportInteractor{
+		txRepo:       txRepo,
+		boardRepo:    boardRepo,
+		listRepo:     listRepo,
+		itemRepo:     itemRepo,
+		tagRepo:      tagRepo,
+		memberRepo:   memberRepo,
+		activityRepo: activityRepo,
+		logger:       logger,
+	}
+	return i, nil
+}
+
+// Import creates a Board with Lists and Items in board in a transaction and returns created Board.
+// Lists and Items are put in order of board.Lists and list.Items. IDs in board are only used in
+// a report, and new IDs are given to all data. Tags of Items are mapped onto existing Tags by ID or
+// by name and color, and other Tags are created in the Board. Data which can not be imported is
+// skipped and returned as Skips.
+func (i *ImportInteractor) Import(ctx context.Context, board model.Board) (model.Board, model.Skips, error) {
+	skips := model.Skips{}
+
+	if board.Title == "" || board.UserID == "" {
+		err := model.InvalidContentError{
+			UserID: board.UserID,
+			Err:    nil,
+			ID:     board.ID,
+			Act:    "validate contents in imported board",
+		}
+		logError(i.logger, err)
+		return model.Board{}, model.Skips{}, err
+	}
+	if !validColor(board.Color) {
+		err := model.InvalidContentError{
+			UserID: board.UserID,
+			Err:    nil,
+			ID:     board.ID,
+			Act:    "validate color of imported board",
+		}
+		logError(i.logger, err)
+		return model.Board{}, model.Skips{}, err
+	}
+
+	var created model.Board
+	err := runInTx(ctx, i.txRepo, i.logger, board.UserID, func(tx Transaction) error {
+		// Default Tags and Tags of the user are usable in new Board.
+		allTags, err := i.tagRepo.Find(ctx, tx, TagFilter{})
+		if err != nil {
+			return err
+		}
+		tags := model.Tags{}
+		for _, t := range allTags {
+			if usableTag(t, "", board.UserID) {
+				tags = append(tags, t)
+			}
+		}
+		sort.SliceStable(tags, func(a, b int) bool {
+			return tags[a].ID < tags[b].ID
+		})
+
+		// Put imported board at the end of user's boards
+		boards, err := i.boardRepo.Find(ctx, tx, BoardFilter{
+			UserIDs: []string{board.UserID},
+		})
+		if err != nil {
+			return err
+		}
+		boards = sortBoards(boards)
+
+		created = model.Board{
+			ID:      uuid.New().String(),
+			UserID:  board.UserID,
+			Title:   board.Title,
+			Text:    board.Text,
+			Color:   board.Color,
+			Lists:   model.Lists{},
+			Version: 1,
+		}
+		created.Rank, err = rankBoard(ctx, tx, i.boardRepo, boards, len(boards))
+		if err != nil {
+			return err
+		}
+
+		if err := i.boardRepo.Create(ctx, tx, created); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(board.UserID, "Create imported board("+created.ID+")"))
+
+		owner := model.Member{
+			BoardID: created.ID,
+			UserID:  board.UserID,
+			Role:    model.OWNER,
+		}
+		if err := i.memberRepo.Create(ctx, tx, owner); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(board.UserID, "Add owner of board("+created.ID+")"))
+
+		// Tags created in the Board are shared by Items having the same name and color.
+		boardTags := model.Tags{}
+		// Tags which can not be created are reported once.
+		invalid := map[model.Tag]bool{}
+
+		lists := model.Lists{}
+		for _, l := range board.Lists {
+			if l.Title == "" {
+				skips = append(skips, model.Skip{Target: model.LIST, ID: l.ID, Reason: "title is empty"})
+				skips = append(skips, skipItems(l.Items, "list is skipped")...)
+				continue
+			}
+			lists = append(lists, l)
+		}
+
+		listRanks := model.Ranks(len(lists))
+		for j, l := range lists {
+			list := model.List{
+				ID:      uuid.New().String(),
+				BoardID: created.ID,
+				UserID:  board.UserID,
+				Title:   l.Title,
+				Rank:    listRanks[j],
+				Items:   model.Items{},
+				Version: 1,
+			}
+			if err := i.listRepo.Create(ctx, tx, list); err != nil {
+				return err
+			}
+
+			items := model.Items{}
+			for _, it := range l.Items {
+				if it.Title == "" {
+					skips = append(skips, model.Skip{Target: model.ITEM, ID: it.ID, Reason: "title is empty"})
+					continue
+				}
+				items = append(items, it)
+			}
+
+			itemRanks := model.Ranks(len(items))
+			for k, it := range items {
+				item := model.Item{
+					ID:        uuid.New().String(),
+					ListID:    list.ID,
+					UserID:    board.UserID,
+					Title:     it.Title,
+					Text:      it.Text,
+					Tags:      model.Tags{},
+					StartDate: it.StartDate,
+					DueDate:   it.DueDate,
+					Completed: it.Completed,
+					Rank:      itemRanks[k],
+					Version:   1,
+				}
+				if !item.StartDate.IsZero() && !item.DueDate.IsZero() && item.StartDate.After(item.DueDate) {
+					item.StartDate = time.Time{}
+					skips = append(skips, model.Skip{Target: model.ITEM, ID: it.ID, Name: it.Title, Reason: "start date is after due date"})
+				}
+				for _, tag := range it.Tags {
+					t, ok := findTag(tags, tag)
+					if !ok {
+						t, ok = findTag(boardTags, tag)
+					}
+					if !ok {
+						t = model.Tag{
+							ID:      uuid.New().String(),
+							BoardID: created.ID,
+							UserID:  board.UserID,
+							Name:    tag.Name,
+							Color:   tag.Color,
+						}
+						if err := validateTag(t); err != nil {
+							if !invalid[tag] {
+								invalid[tag] = true
+								skips = append(skips, model.Skip{Target: model.TAG, ID: tag.ID, Name: tag.Name, Reason: "title or color is invalid"})
+							}
+							continue
+						}
+						if err := i.tagRepo.Create(ctx, tx, t); err != nil {
+							return err
+						}
+						i.logger.Info(formatLogMsg(board.UserID, "Create tag("+t.ID+") in imported board("+created.ID+")"))
+						boardTags = append(boardTags, t)
+					}
+					if !hasTag(item.Tags, t.ID) {
+						item.Tags = append(item.Tags, t)
+					}
+				}
+
+				if err := i.itemRepo.Create(ctx, tx, item); err != nil {
+					return err
+				}
+				list.Items = append(list.Items, item)
+			}
+			created.Lists = append(created.Lists, list)
+		}
+		i.logger.Info(formatLogMsg(board.UserID, "Import lists and items into board("+created.ID+")"))
+
+		// Lists and Items are not included in a snapshot to keep an activity small.
+		after := created
+		after.Lists = nil
+		activity := model.Activity{
+			BoardID:  created.ID,
+			UserID:   board.UserID,
+			Action:   model.CREATE,
+			Target:   model.BOARD,
+			TargetID: created.ID,
+		}
+		if _, err := recordActivity(ctx, tx, i.activityRepo, activity, nil, after); err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Board{}, model.Skips{}, err
+	}
+
+	return created, skips, nil
+}
+
+func skipItems(items model.Items, reason string) model.Skips {
+	skips := model.Skips{}
+	for _, it := range items {
+		skips = append(skips, model.Skip{Target: model.ITEM, ID: it.ID, Name: it.Title, Reason: reason})
+	}
+	return skips
+}
+
+// findTag returns a Tag in tags matched with tag by ID, or by name and color in this order.
+func findTag(tags model.Tags, tag model.Tag) (model.Tag, bool) {
+	for _, t := range tags {
+		if tag.ID != "" && t.ID == tag.ID {
+			return t, true
+		}
+	}
+	for _, t := range tags {
+		if t.Name == tag.Name && t.Color == tag.Color {
+			return t, true
+		}
+	}
+	return model.Tag{}, false
+}
+
+func hasTag(tags model.Tags, id string) bool {
+	for _, t := range tags {
+		if t.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func validColor(color model.Color) bool {
+	for _, c := range model.COLORS {
+		if color == c {
+			return true
+		}
+	}
+	return false
+}
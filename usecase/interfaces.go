@@ -1,6 +1,9 @@
 package usecase
 
 import (
+	"context"
+	"time"
+
 	"github.com/x-color/vue-trello/model"
 )
 
@@ -13,51 +16,159 @@ type Logger interface {
 
 // Transaction is interface. It defined transaction methods.
 type Transaction interface {
-	Commit()
-	Rollback()
+	Commit() error
+	Rollback() error
 	DB() interface{}
 }
 
 // TransactionRepository is interface. It defines Transaction getter.
+// RunInTx runs f in a transaction. The transaction is committed if f returns nil, and rolled back
+// if f returns an error or panics. It returns ServerError if the transaction fails to be committed.
 type TransactionRepository interface {
-	BeginTransaction(on bool) Transaction
+	BeginTransaction(ctx context.Context, on bool) Transaction
+	RunInTx(ctx context.Context, f func(tx Transaction) error) error
+}
+
+// EventBus is interface. It defines in-process pub/sub of changes in Boards recorded as Activities.
+// Interactors publish Activities after their transactions are committed.
+// Subscribe returns a channel of Activities published to a Board after it is called and a function
+// to stop the subscription. The channel is closed if the subscriber can not keep up with changes.
+type EventBus interface {
+	Publish(activities ...model.Activity)
+	Subscribe(boardID string) (<-chan model.Activity, func())
 }
 
 // ItemRepository is interface. It defines CURD methods for Item.
 type ItemRepository interface {
-	Create(tx Transaction, item model.Item) error
-	Update(tx Transaction, item model.Item, updates map[string]interface{}) error
-	Delete(tx Transaction, item model.Item) error
-	FindByID(tx Transaction, id, userID string) (model.Item, error)
-	Find(tx Transaction, conditions map[string]interface{}) (model.Items, error)
+	Create(ctx context.Context, tx Transaction, item model.Item) error
+	Update(ctx context.Context, tx Transaction, item model.Item, updates map[string]interface{}) error
+	Delete(ctx context.Context, tx Transaction, item model.Item) error
+	FindByID(ctx context.Context, tx Transaction, id string) (model.Item, error)
+	Find(ctx context.Context, tx Transaction, filter ItemFilter) (model.Items, error)
+}
+
+// ChecklistRepository is interface. It defines CURD methods for Checklist.
+type ChecklistRepository interface {
+	Create(ctx context.Context, tx Transaction, checklist model.Checklist) error
+	Update(ctx context.Context, tx Transaction, checklist model.Checklist, updates map[string]interface{}) error
+	Delete(ctx context.Context, tx Transaction, checklist model.Checklist) error
+	FindByID(ctx context.Context, tx Transaction, id string) (model.Checklist, error)
+	Find(ctx context.Context, tx Transaction, filter ChecklistFilter) (model.Checklists, error)
+}
+
+// CheckItemRepository is interface. It defines CURD methods for CheckItem.
+type CheckItemRepository interface {
+	Create(ctx context.Context, tx Transaction, checkItem model.CheckItem) error
+	Update(ctx context.Context, tx Transaction, checkItem model.CheckItem, updates map[string]interface{}) error
+	Delete(ctx context.Context, tx Transaction, checkItem model.CheckItem) error
+	FindByID(ctx context.Context, tx Transaction, id string) (model.CheckItem, error)
+	Find(ctx context.Context, tx Transaction, filter CheckItemFilter) (model.CheckItems, error)
+}
+
+// CommentRepository is interface. It defines CURD methods for Comment.
+type CommentRepository interface {
+	Create(ctx context.Context, tx Transaction, comment model.Comment) error
+	Update(ctx context.Context, tx Transaction, comment model.Comment, updates map[string]interface{}) error
+	Delete(ctx context.Context, tx Transaction, comment model.Comment) error
+	FindByID(ctx context.Context, tx Transaction, id string) (model.Comment, error)
+	Find(ctx context.Context, tx Transaction, filter CommentFilter) (model.Comments, error)
+}
+
+// ActivityRepository is interface. It defines methods to record and read Activities.
+// FindPage gets Activities in a Board from newest to oldest. Activities older than cursor are got if cursor is not empty.
+// FindSince gets Activities in a Board newer than cursor from oldest to newest.
+type ActivityRepository interface {
+	Create(ctx context.Context, tx Transaction, activity model.Activity) error
+	FindPage(ctx context.Context, tx Transaction, boardID, cursor string, limit int) (model.Activities, error)
+	FindSince(ctx context.Context, tx Transaction, boardID, cursor string, limit int) (model.Activities, error)
+	Find(ctx context.Context, tx Transaction, filter ActivityFilter) (model.Activities, error)
+}
+
+// WebhookRepository is interface. It defines CURD methods for Webhook.
+type WebhookRepository interface {
+	Create(ctx context.Context, tx Transaction, webhook model.Webhook) error
+	Delete(ctx context.Context, tx Transaction, webhook model.Webhook) error
+	FindByID(ctx context.Context, tx Transaction, id string) (model.Webhook, error)
+	Find(ctx context.Context, tx Transaction, filter WebhookFilter) (model.Webhooks, error)
+}
+
+// DeliveryRepository is interface. It defines methods to record and read Deliveries of Webhooks.
+// Claim takes a pending Delivery for an attempt. It increments Attempts and postpones NextAttemptAt to until
+// only if the Delivery still has Attempts of delivery, and otherwise returns PreconditionFailedError.
+type DeliveryRepository interface {
+	Create(ctx context.Context, tx Transaction, delivery model.Delivery) error
+	Update(ctx context.Context, tx Transaction, delivery model.Delivery, updates map[string]interface{}) error
+	Claim(ctx context.Context, tx Transaction, delivery model.Delivery, until time.Time) error
+	Delete(ctx context.Context, tx Transaction, delivery model.Delivery) error
+	Find(ctx context.Context, tx Transaction, filter DeliveryFilter) (model.Deliveries, error)
+}
+
+// WebhookSender is interface. It defines to send an Activity to a Webhook.
+// Send returns a status code of the response, and an error if no response is got.
+// Validate returns an error if the Sender can not send requests to a URL.
+type WebhookSender interface {
+	Send(ctx context.Context, webhook model.Webhook, delivery model.Delivery, activity model.Activity) (int, error)
+	Validate(ctx context.Context, url string) error
+}
+
+// AccessTokenRepository is interface. It defines CURD methods for AccessToken.
+type AccessTokenRepository interface {
+	Create(ctx context.Context, tx Transaction, token model.AccessToken) error
+	Delete(ctx context.Context, tx Transaction, token model.AccessToken) error
+	FindByID(ctx context.Context, tx Transaction, id string) (model.AccessToken, error)
+	FindByHash(ctx context.Context, tx Transaction, hash string) (model.AccessToken, error)
+	Find(ctx context.Context, tx Transaction, filter AccessTokenFilter) (model.AccessTokens, error)
+}
+
+// SessionRepository is interface. It defines CURD methods for Session.
+type SessionRepository interface {
+	Create(ctx context.Context, tx Transaction, session model.Session) error
+	Delete(ctx context.Context, tx Transaction, session model.Session) error
+	FindByID(ctx context.Context, tx Transaction, id string) (model.Session, error)
+	Find(ctx context.Context, tx Transaction, filter SessionFilter) (model.Sessions, error)
 }
 
 // ListRepository is interface. It defines CURD methods for List.
 type ListRepository interface {
-	Create(tx Transaction, list model.List) error
-	Update(tx Transaction, list model.List, updates map[string]interface{}) error
-	Delete(tx Transaction, list model.List) error
-	FindByID(tx Transaction, id, UserID string) (model.List, error)
-	Find(tx Transaction, conditions map[string]interface{}) (model.Lists, error)
+	Create(ctx context.Context, tx Transaction, list model.List) error
+	Update(ctx context.Context, tx Transaction, list model.List, updates map[string]interface{}) error
+	Delete(ctx context.Context, tx Transaction, list model.List) error
+	FindByID(ctx context.Context, tx Transaction, id string) (model.List, error)
+	Find(ctx context.Context, tx Transaction, filter ListFilter) (model.Lists, error)
 }
 
 // BoardRepository is interface. It defines CURD methods for Board.
 type BoardRepository interface {
-	Create(tx Transaction, board model.Board) error
-	Update(tx Transaction, board model.Board, updates map[string]interface{}) error
-	Delete(tx Transaction, board model.Board) error
-	FindByID(tx Transaction, id, userID string) (model.Board, error)
-	Find(tx Transaction, condititons map[string]interface{}) (model.Boards, error)
+	Create(ctx context.Context, tx Transaction, board model.Board) error
+	Update(ctx context.Context, tx Transaction, board model.Board, updates map[string]interface{}) error
+	Delete(ctx context.Context, tx Transaction, board model.Board) error
+	FindByID(ctx context.Context, tx Transaction, id string) (model.Board, error)
+	Find(ctx context.Context, tx Transaction, filter BoardFilter) (model.Boards, error)
 }
 
 // UserRepository is interface. It defines CR methods for User.
+// Find gets a User matched with a filter. It returns NotFoundError if no User matches.
 type UserRepository interface {
-	Create(tx Transaction, user model.User) error
-	Find(tx Transaction, conditions map[string]interface{}) (model.User, error)
+	Create(ctx context.Context, tx Transaction, user model.User) error
+	Update(ctx context.Context, tx Transaction, user model.User, updates map[string]interface{}) error
+	Delete(ctx context.Context, tx Transaction, user model.User) error
+	Find(ctx context.Context, tx Transaction, filter UserFilter) (model.User, error)
 }
 
-// TagRepository is interface. It defines CR methods for Tag.
+// TagRepository is interface. It defines CURD methods for Tag.
 type TagRepository interface {
-	Create(tx Transaction, tag model.Tag) error
-	Find(tx Transaction, conditions map[string]interface{}) (model.Tags, error)
+	Create(ctx context.Context, tx Transaction, tag model.Tag) error
+	Update(ctx context.Context, tx Transaction, tag model.Tag, updates map[string]interface{}) error
+	Delete(ctx context.Context, tx Transaction, tag model.Tag) error
+	FindByID(ctx context.Context, tx Transaction, id string) (model.Tag, error)
+	Find(ctx context.Context, tx Transaction, filter TagFilter) (model.Tags, error)
+}
+
+// MemberRepository is interface. It defines CURD methods for Member.
+type MemberRepository interface {
+	Create(ctx context.Context, tx Transaction, member model.Member) error
+	Update(ctx context.Context, tx Transaction, member model.Member, updates map[string]interface{}) error
+	Delete(ctx context.Context, tx Transaction, member model.Member) error
+	FindByID(ctx context.Context, tx Transaction, boardID, userID string) (model.Member, error)
+	Find(ctx context.Context, tx Transaction, filter MemberFilter) (model.Members, error)
 }
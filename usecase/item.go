@@ -1,25 +1,37 @@
 package usecase
 
 import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/x-color/vue-trello/model"
 )
 
 // ItemUsecase is interface. It defines to control a Item.
 type ItemUsecase interface {
-	Create(item model.Item) (model.Item, error)
-	Delete(item model.Item) error
-	Update(item model.Item) (model.Item, error)
-	Move(item model.Item) error
+	Create(ctx context.Context, item model.Item) (model.Item, error)
+	Delete(ctx context.Context, item model.Item) error
+	Update(ctx context.Context, item model.Item) (model.Item, error)
+	Move(ctx context.Context, item model.Item) (model.Item, error)
+	GetDue(ctx context.Context, user model.User, within time.Duration) (model.Items, model.Items, error)
 }
 
-// ItemInteractor includes repogitories and a logger.
+// ItemInteractor includes repogitories, a bus to publish changes and a logger.
 type ItemInteractor struct {
-	txRepo   TransactionRepository
-	itemRepo ItemRepository
-	listRepo ListRepository
-	tagRepo  TagRepository
-	logger   Logger
+	txRepo        TransactionRepository
+	itemRepo      ItemRepository
+	listRepo      ListRepository
+	tagRepo       TagRepository
+	memberRepo    MemberRepository
+	checklistRepo ChecklistRepository
+	checkItemRepo CheckItemRepository
+	commentRepo   CommentRepository
+	activityRepo  ActivityRepository
+	bus           EventBus
+	logger        Logger
 }
 
 // NewItemInteractor generates new interactor for a Item.
@@ -28,78 +40,86 @@ func NewItemInteractor(
 	itemRepo ItemRepository,
 	listRepo ListRepository,
 	tagRepo TagRepository,
+	memberRepo MemberRepository,
+	checklistRepo ChecklistRepository,
+	checkItemRepo CheckItemRepository,
+	commentRepo CommentRepository,
+	activityRepo ActivityRepository,
+	bus EventBus,
 	logger Logger,
 ) (ItemInteractor, error) {
 	i := ItemInteractor{
-		txRepo:   txRepo,
-		itemRepo: itemRepo,
-		listRepo: listRepo,
-		tagRepo:  tagRepo,
-		logger:   logger,
+		txRepo:        txRepo,
+		itemRepo:      itemRepo,
+		listRepo:      listRepo,
+		tagRepo:       tagRepo,
+		memberRepo:    memberRepo,
+		checklistRepo: checklistRepo,
+		checkItemRepo: checkItemRepo,
+		commentRepo:   commentRepo,
+		activityRepo:  activityRepo,
+		bus:           bus,
+		logger:        logger,
 	}
 	return i, nil
 }
 
 // Create saves new Item to a repository and returns created Item.
-func (i *ItemInteractor) Create(item model.Item) (model.Item, error) {
+func (i *ItemInteractor) Create(ctx context.Context, item model.Item) (model.Item, error) {
 	item.ID = uuid.New().String()
-	if err := i.validateItem(item); err != nil {
-		logError(i.logger, err)
-		return model.Item{}, err
-	}
-
-	tx := i.txRepo.BeginTransaction(true)
-	i.logger.Info(formatLogMsg(item.UserID, "Start transaction"))
+	item.Version = 1
 
-	// Get last item in list
-	items, err := i.itemRepo.Find(tx, map[string]interface{}{
-		"ListID": item.ListID,
-		"UserID": item.UserID,
-		"After":  "",
-	})
-	if err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-		logError(i.logger, err)
-		return model.Item{}, err
-	}
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, item.UserID, func(tx Transaction) error {
+		list, err := i.validateItem(ctx, tx, item, nil)
+		if err != nil {
+			return err
+		}
 
-	if len(items) > 0 {
-		lastItem := items[0]
-		i.logger.Info(formatLogMsg(item.UserID, "Find last item("+lastItem.ID+") in list("+lastItem.ListID+")"))
+		// Put new item at the end of list
+		items, err := i.itemRepo.Find(ctx, tx, ItemFilter{
+			ListIDs: []string{item.ListID},
+		})
+		if err != nil {
+			return err
+		}
+		items = sortItems(items)
 
-		item.Before = lastItem.ID
+		item.Rank, err = rankItem(ctx, tx, i.itemRepo, items, len(items))
+		if err != nil {
+			return err
+		}
 
-		query := map[string]interface{}{
-			"After": item.ID,
+		if err := i.itemRepo.Create(ctx, tx, item); err != nil {
+			return err
 		}
-		if err := i.itemRepo.Update(tx, lastItem, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-			logError(i.logger, err)
-			return model.Item{}, err
+		i.logger.Info(formatLogMsg(item.UserID, "Create item("+item.ID+")"))
+
+		activity := model.Activity{
+			BoardID:  list.BoardID,
+			UserID:   item.UserID,
+			Action:   model.CREATE,
+			Target:   model.ITEM,
+			TargetID: item.ID,
 		}
-		i.logger.Info(formatLogMsg(item.UserID, "Update item("+lastItem.ID+")"))
-	} else {
-		i.logger.Info(formatLogMsg(item.UserID, "Find no item in list("+item.ListID+")"))
-	}
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, nil, item)
+		if err != nil {
+			return err
+		}
+		events = append(events, recorded)
 
-	if err := i.itemRepo.Create(tx, item); err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-		logError(i.logger, err)
+		return nil
+	})
+	if err != nil {
 		return model.Item{}, err
 	}
-	i.logger.Info(formatLogMsg(item.UserID, "Create item("+item.ID+")"))
-
-	tx.Commit()
-	i.logger.Info(formatLogMsg(item.UserID, "Commit transaction"))
 
+	i.bus.Publish(events...)
 	return item, nil
 }
 
 // Delete removes Item in repository.
-func (i *ItemInteractor) Delete(item model.Item) error {
+func (i *ItemInteractor) Delete(ctx context.Context, item model.Item) error {
 	if item.ID == "" || item.UserID == "" {
 		i.logger.Info(formatLogMsg(item.UserID, "Invalid item. ID is empty"))
 		return model.InvalidContentError{
@@ -110,271 +130,257 @@ func (i *ItemInteractor) Delete(item model.Item) error {
 		}
 	}
 
-	tx := i.txRepo.BeginTransaction(true)
-	i.logger.Info(formatLogMsg(item.UserID, "Start transaction"))
-
-	// Get item's info (e.g. item.Before, item.After...) and rewrite 'item'.
-	item, err := i.itemRepo.FindByID(tx, item.ID, item.UserID)
-	if err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-		logError(i.logger, err)
-		return err
-	}
-	i.logger.Info(formatLogMsg(item.UserID, "Find item("+item.ID+")"))
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, item.UserID, func(tx Transaction) error {
+		// Get item's info (e.g. item.ListID...) and rewrite 'item'.
+		userID := item.UserID
+		expected := item.Version
+		item, err := i.itemRepo.FindByID(ctx, tx, item.ID)
+		if err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(userID, "Find item("+item.ID+")"))
 
-	if item.Before != "" {
-		// Update item before deleting item
-		before := model.Item{
-			ID:     item.Before,
-			UserID: item.UserID,
+		list, err := authorizeList(ctx, tx, i.listRepo, i.memberRepo, item.ListID, userID, model.EDITOR)
+		if err != nil {
+			return err
 		}
-		query := map[string]interface{}{
-			"After": item.After,
+		if err := checkVersion(expected, item.Version, item, userID, item.ID, "check version of item to delete"); err != nil {
+			return err
 		}
-		if err := i.itemRepo.Update(tx, before, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+
+		if err := i.itemRepo.Delete(ctx, tx, item); err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(item.UserID, "Update item("+before.ID+") before deleted item("+item.ID+")"))
-	}
+		i.logger.Info(formatLogMsg(item.UserID, "Delete item("+item.ID+")"))
 
-	if item.After != "" {
-		// Update item after deleting item
-		after := model.Item{
-			ID:     item.After,
-			UserID: item.UserID,
+		if err := deleteChecklists(ctx, tx, i.checklistRepo, i.checkItemRepo, item); err != nil {
+			return err
 		}
-		query := map[string]interface{}{
-			"Before": item.Before,
+		if err := deleteComments(ctx, tx, i.commentRepo, item); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(item.UserID, "Delete checklists and comments in deleted item("+item.ID+")"))
+
+		activity := model.Activity{
+			BoardID:  list.BoardID,
+			UserID:   userID,
+			Action:   model.DELETE,
+			Target:   model.ITEM,
+			TargetID: item.ID,
 		}
-		if err := i.itemRepo.Update(tx, after, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, item, nil)
+		if err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(item.UserID, "Update item("+after.ID+") after deleted item("+item.ID+")"))
-	}
+		events = append(events, recorded)
 
-	if err := i.itemRepo.Delete(tx, item); err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-		logError(i.logger, err)
+		return nil
+	})
+	if err != nil {
 		return err
 	}
-	i.logger.Info(formatLogMsg(item.UserID, "Delete item("+item.ID+")"))
-
-	tx.Commit()
-	i.logger.Info(formatLogMsg(item.UserID, "Commit transaction"))
 
+	i.bus.Publish(events...)
 	return nil
 }
 
 // Update replaces a Item and returns new Item.
-func (i *ItemInteractor) Update(item model.Item) (model.Item, error) {
-	if err := i.validateItem(item); err != nil {
-		logError(i.logger, err)
-		return model.Item{}, err
-	}
-
-	tags := []string{}
-	for _, t := range item.Tags {
-		tags = append(tags, t.ID)
-	}
-
-	query := map[string]interface{}{
-		"Title": item.Title,
-		"Text":  item.Text,
-		"Tags":  tags,
-	}
+func (i *ItemInteractor) Update(ctx context.Context, item model.Item) (model.Item, error) {
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, item.UserID, func(tx Transaction) error {
+		// A Item can not be moved to other List by Update. Use List of saved Item.
+		old, err := i.itemRepo.FindByID(ctx, tx, item.ID)
+		if err != nil {
+			return err
+		}
+		item.ListID = old.ListID
 
-	tx := i.txRepo.BeginTransaction(false)
-	if err := i.itemRepo.Update(tx, item, query); err != nil {
-		logError(i.logger, err)
-		return model.Item{}, err
-	}
-	i.logger.Info(formatLogMsg(item.UserID, "Update item("+item.ID+")"))
-	return item, nil
-}
+		list, err := i.validateItem(ctx, tx, item, old.Tags)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(item.Version, old.Version, old, item.UserID, item.ID, "check version of item to update"); err != nil {
+			return err
+		}
 
-// Move moves Items.
-func (i *ItemInteractor) Move(item model.Item) error {
-	item.Title = "dummy title"
-	if err := i.validateItem(item); err != nil {
-		logError(i.logger, err)
-		return err
-	}
-	item.Title = ""
+		tags := []string{}
+		for _, t := range item.Tags {
+			tags = append(tags, t.ID)
+		}
 
-	tx := i.txRepo.BeginTransaction(true)
-	i.logger.Info(formatLogMsg(item.UserID, "Start transaction"))
+		query := map[string]interface{}{
+			"Title":     item.Title,
+			"Text":      item.Text,
+			"Tags":      tags,
+			"StartDate": item.StartDate,
+			"DueDate":   item.DueDate,
+			"Completed": item.Completed,
+		}
 
-	// Get a item to move
-	if item.Before != "" {
-		before, err := i.itemRepo.FindByID(tx, item.Before, item.UserID)
-		if err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+		if err := i.itemRepo.Update(ctx, tx, old, query); err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(item.UserID, "Find a item("+before.ID+") before item to move"))
-		if item.ListID != before.ListID {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-			i.logger.Info(formatLogMsg(item.UserID, "Invalid list id("+item.ListID+"). Does not equal before item's list id("+before.ListID+")"))
+		i.logger.Info(formatLogMsg(item.UserID, "Update item("+item.ID+")"))
+
+		updated := old
+		updated.Title = item.Title
+		updated.Text = item.Text
+		updated.Tags = item.Tags
+		updated.StartDate = item.StartDate
+		updated.DueDate = item.DueDate
+		updated.Completed = item.Completed
+		updated.Version = old.Version + 1
+		item.Version = updated.Version
+		activity := model.Activity{
+			BoardID:  list.BoardID,
+			UserID:   item.UserID,
+			Action:   model.UPDATE,
+			Target:   model.ITEM,
+			TargetID: item.ID,
+		}
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, old, updated)
+		if err != nil {
 			return err
 		}
-	}
+		events = append(events, recorded)
 
-	// Get a item to move
-	old, err := i.itemRepo.FindByID(tx, item.ID, item.UserID)
+		return nil
+	})
 	if err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-		logError(i.logger, err)
-		return err
+		return model.Item{}, err
 	}
-	i.logger.Info(formatLogMsg(item.UserID, "Find item("+item.ID+") to move"))
 
-	// Get a item before item to move
-	if old.Before != "" {
-		beforeOld := model.Item{
-			ID:     old.Before,
-			UserID: old.UserID,
-		}
-		query := map[string]interface{}{
-			"After": old.After,
-		}
-		if err := i.itemRepo.Update(tx, beforeOld, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+	i.bus.Publish(events...)
+	return item, nil
+}
+
+// Move moves Items and returns the moved Item.
+func (i *ItemInteractor) Move(ctx context.Context, item model.Item) (model.Item, error) {
+	var moved model.Item
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, item.UserID, func(tx Transaction) error {
+		item.Title = "dummy title"
+		list, err := i.validateItem(ctx, tx, item, nil)
+		if err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(item.UserID, "Find a item("+beforeOld.ID+") before item("+item.ID+") to move"))
-	}
+		item.Title = ""
 
-	// Get a item after item to move
-	if old.After != "" {
-		afterOld := model.Item{
-			ID:     old.After,
-			UserID: old.UserID,
+		// Get a item to move
+		old, err := i.itemRepo.FindByID(ctx, tx, item.ID)
+		if err != nil {
+			return err
 		}
-		query := map[string]interface{}{
-			"Before": old.Before,
+		i.logger.Info(formatLogMsg(item.UserID, "Find item("+item.ID+") to move"))
+
+		// A user needs permission for both Lists to move a Item between Lists.
+		oldList := list
+		if old.ListID != item.ListID {
+			oldList, err = authorizeList(ctx, tx, i.listRepo, i.memberRepo, old.ListID, item.UserID, model.EDITOR)
+			if err != nil {
+				return err
+			}
 		}
-		if err := i.itemRepo.Update(tx, afterOld, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+		if err := checkVersion(item.Version, old.Version, old, item.UserID, item.ID, "check version of item to move"); err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(item.UserID, "Find a item("+afterOld.ID+") after item("+item.ID+") to move"))
-	}
 
-	// Get a item after moved item
-	if item.Before == "" {
-		conditions := map[string]interface{}{
-			"ListID": item.ListID,
-			"UserID": item.UserID,
-			"Before": "",
-		}
-		l, err := i.itemRepo.Find(tx, conditions)
+		// Get other items in destination list
+		items, err := i.itemRepo.Find(ctx, tx, ItemFilter{
+			ListIDs: []string{item.ListID},
+		})
 		if err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-			logError(i.logger, err)
 			return err
 		}
-		if len(l) == 1 {
-			item.After = l[0].ID
-		} else if len(l) > 1 {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-			err = model.ServerError{
-				ID:     item.ID,
+		others := model.Items{}
+		ids := []string{}
+		for _, it := range sortItems(items) {
+			if it.ID != old.ID {
+				others = append(others, it)
+				ids = append(ids, it.ID)
+			}
+		}
+
+		index := positionAfter(ids, item.Before)
+		if index < 0 {
+			return model.InvalidContentError{
 				UserID: item.UserID,
 				Err:    nil,
-				Act:    "find a item before moved item",
+				ID:     item.ID,
+				Act:    "validate item(" + item.Before + ") before moved item in list(" + item.ListID + ")",
 			}
-			logError(i.logger, err)
-			return err
 		}
-	} else {
-		before, err := i.itemRepo.FindByID(tx, item.Before, item.UserID)
+
+		rank, err := rankItem(ctx, tx, i.itemRepo, others, index)
 		if err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-			logError(i.logger, err)
 			return err
 		}
-		item.After = before.After
-	}
-	i.logger.Info(formatLogMsg(item.UserID, "Find a item("+item.After+") after moved item("+item.ID+")"))
 
-	// Update a item before moved item
-	if item.Before != "" {
-		before := model.Item{
-			ID:     item.Before,
-			UserID: item.UserID,
-		}
+		// Move item
 		query := map[string]interface{}{
-			"After": item.ID,
+			"ListID": item.ListID,
+			"Rank":   rank,
 		}
-		if err := i.itemRepo.Update(tx, before, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-			logError(i.logger, err)
-			return err
+		moved = old
+		moved.ListID = item.ListID
+		moved.Rank = rank
+		moved.Version = old.Version + 1
+
+		// Tags in a Board are detached from a Item moved to other Board.
+		if oldList.BoardID != list.BoardID {
+			moved.Tags, err = tagsForBoard(ctx, tx, i.tagRepo, old.Tags, list.BoardID)
+			if err != nil {
+				return err
+			}
+			tags := []string{}
+			for _, t := range moved.Tags {
+				tags = append(tags, t.ID)
+			}
+			query["Tags"] = tags
 		}
-		i.logger.Info(formatLogMsg(item.UserID, "Update item("+before.ID+") before item("+item.ID+") in list("+item.ListID+")"))
-	}
 
-	// Update a item after moved item
-	if item.After != "" {
-		after := model.Item{
-			ID:     item.After,
-			UserID: item.UserID,
+		if err := i.itemRepo.Update(ctx, tx, old, query); err != nil {
+			return err
 		}
-		query := map[string]interface{}{
-			"Before": item.ID,
+		i.logger.Info(formatLogMsg(item.UserID, "Move item("+item.ID+") after item("+item.Before+") in list("+item.ListID+")"))
+
+		// A Item moved between Boards is recorded in both Boards.
+		// They are recorded in order of Board IDs, so that repositories locking Boards lock them in the same order.
+		boardIDs := []string{list.BoardID}
+		if oldList.BoardID != list.BoardID {
+			boardIDs = append(boardIDs, oldList.BoardID)
 		}
-		if err := i.itemRepo.Update(tx, after, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-			logError(i.logger, err)
-			return err
+		sort.Strings(boardIDs)
+		for _, boardID := range boardIDs {
+			activity := model.Activity{
+				BoardID:  boardID,
+				UserID:   item.UserID,
+				Action:   model.MOVE,
+				Target:   model.ITEM,
+				TargetID: item.ID,
+			}
+			recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, old, moved)
+			if err != nil {
+				return err
+			}
+			events = append(events, recorded)
 		}
-		i.logger.Info(formatLogMsg(item.UserID, "Update item("+after.ID+") after item("+item.ID+") in list("+item.ListID+")"))
-	}
 
-	// Move item
-	query := map[string]interface{}{
-		"ListID": item.ListID,
-		"Before": item.Before,
-		"After":  item.After,
-	}
-	if err := i.itemRepo.Update(tx, item, query); err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-		logError(i.logger, err)
-		return err
+		return nil
+	})
+	if err != nil {
+		return model.Item{}, err
 	}
-	i.logger.Info(formatLogMsg(item.UserID, "Move item("+item.ID+") after item("+item.Before+") in list("+item.ListID+")"))
-
-	tx.Commit()
-	i.logger.Info(formatLogMsg(item.UserID, "Commit transaction"))
 
-	return nil
+	i.bus.Publish(events...)
+	return moved, nil
 }
 
-func (i *ItemInteractor) validateItem(item model.Item) error {
+// validateItem returns List of the Item if the Item is valid.
+func (i *ItemInteractor) validateItem(ctx context.Context, tx Transaction, item model.Item, attached model.Tags) (model.List, error) {
 	if item.ID == "" || item.Title == "" || item.ListID == "" || item.UserID == "" {
-		return model.InvalidContentError{
+		return model.List{}, model.InvalidContentError{
 			UserID: item.UserID,
 			Err:    nil,
 			ID:     item.ID,
@@ -382,28 +388,31 @@ func (i *ItemInteractor) validateItem(item model.Item) error {
 		}
 	}
 
-	tx := i.txRepo.BeginTransaction(false)
-	_, err := i.listRepo.FindByID(tx, item.ListID, item.UserID)
-	if err != nil {
-		return err
+	if !item.StartDate.IsZero() && !item.DueDate.IsZero() && item.StartDate.After(item.DueDate) {
+		return model.List{}, model.InvalidContentError{
+			UserID: item.UserID,
+			Err:    nil,
+			ID:     item.ID,
+			Act:    "validate start date and due date of item",
+		}
 	}
 
-	allTags, err := i.tagRepo.Find(tx, map[string]interface{}{})
+	list, err := authorizeList(ctx, tx, i.listRepo, i.memberRepo, item.ListID, item.UserID, model.EDITOR)
 	if err != nil {
-		return err
+		return model.List{}, err
 	}
 
-	// Validate tags attached to item
+	// Validate tags attached to item. Tags attached already are kept even if they are of other users.
 	for _, tag := range item.Tags {
-		isValid := false
-		for _, t := range allTags {
-			if t.ID == tag.ID {
-				isValid = true
-				break
-			}
+		if hasTag(attached, tag.ID) {
+			continue
 		}
-		if !isValid {
-			return model.InvalidContentError{
+		t, err := i.tagRepo.FindByID(ctx, tx, tag.ID)
+		if err != nil && !errors.Is(err, model.NotFoundError{}) {
+			return model.List{}, err
+		}
+		if err != nil || !usableTag(t, list.BoardID, item.UserID) {
+			return model.List{}, model.InvalidContentError{
 				UserID: item.UserID,
 				Err:    nil,
 				ID:     item.ID,
@@ -411,21 +420,104 @@ func (i *ItemInteractor) validateItem(item model.Item) error {
 			}
 		}
 	}
-	return nil
+	return list, nil
 }
 
-func sortItems(items model.Items) model.Items {
-	l := map[string]model.Item{}
-	for _, i := range items {
-		l[i.Before] = i
+// GetDue returns incomplete Items in all Boards of User which have due date.
+// The first Items are overdue, and the second Items are due within a specific duration.
+func (i *ItemInteractor) GetDue(ctx context.Context, user model.User, within time.Duration) (model.Items, model.Items, error) {
+	if within < 0 {
+		err := model.InvalidContentError{
+			UserID: user.ID,
+			Err:    nil,
+			ID:     "(No-ID)",
+			Act:    "validate duration of due items",
+		}
+		logError(i.logger, err)
+		return model.Items{}, model.Items{}, err
 	}
 
-	item := l[""]
-	r := model.Items{}
-	for range items {
-		r = append(r, item)
-		item = l[item.ID]
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	members, err := i.memberRepo.Find(ctx, tx, MemberFilter{
+		UserIDs: []string{user.ID},
+	})
+	if err != nil {
+		logError(i.logger, err)
+		return model.Items{}, model.Items{}, err
 	}
 
-	return r
+	boardIDs := []string{}
+	for _, m := range members {
+		boardIDs = append(boardIDs, m.BoardID)
+	}
+	lists, err := i.listRepo.Find(ctx, tx, ListFilter{
+		BoardIDs: boardIDs,
+	})
+	if err != nil {
+		logError(i.logger, err)
+		return model.Items{}, model.Items{}, err
+	}
+	listIDs := []string{}
+	for _, l := range lists {
+		listIDs = append(listIDs, l.ID)
+	}
+	i.logger.Info(formatLogMsg(user.ID, "Find lists in boards of user"))
+
+	now := time.Now()
+	incomplete := false
+	items, err := i.itemRepo.Find(ctx, tx, ItemFilter{
+		ListIDs:   listIDs,
+		Completed: &incomplete,
+		DueUntil:  now.Add(within),
+		Sort:      Sort{Key: SortByDueDate},
+	})
+	if err != nil {
+		logError(i.logger, err)
+		return model.Items{}, model.Items{}, err
+	}
+
+	overdue := model.Items{}
+	upcoming := model.Items{}
+	for _, item := range items {
+		if item.DueDate.Before(now) {
+			overdue = append(overdue, item)
+		} else {
+			upcoming = append(upcoming, item)
+		}
+	}
+	i.logger.Info(formatLogMsg(user.ID, "Get due items"))
+
+	return overdue, upcoming, nil
+}
+
+// rankItem returns a rank to put a Item at index in sorted Items of a List.
+// The Items are rebalanced if the rank gets too long.
+func rankItem(ctx context.Context, tx Transaction, itemRepo ItemRepository, items model.Items, index int) (string, error) {
+	ranks := []string{}
+	for _, x := range items {
+		ranks = append(ranks, x.Rank)
+	}
+	if rank, ok := rankAt(ranks, index); ok {
+		return rank, nil
+	}
+
+	ranks = model.Ranks(len(items))
+	for j, x := range items {
+		if err := itemRepo.Update(ctx, tx, x, map[string]interface{}{"Rank": ranks[j]}); err != nil {
+			return "", err
+		}
+	}
+	rank, _ := rankAt(ranks, index)
+	return rank, nil
+}
+
+func sortItems(items model.Items) model.Items {
+	sort.SliceStable(items, func(a, b int) bool {
+		if items[a].Rank != items[b].Rank {
+			return items[a].Rank < items[b].Rank
+		}
+		return items[a].ID < items[b].ID
+	})
+	return items
 }
@@ -1,25 +1,35 @@
 package usecase
 
 import (
+	"context"
+	"sort"
+
 	"github.com/google/uuid"
 	"github.com/x-color/vue-trello/model"
 )
 
 // ListUsecase is interface. It defines to control a List.
 type ListUsecase interface {
-	Create(list model.List) (model.List, error)
-	Delete(list model.List) error
-	Update(list model.List) (model.List, error)
-	Move(list model.List) error
+	Create(ctx context.Context, list model.List) (model.List, error)
+	Delete(ctx context.Context, list model.List) error
+	Update(ctx context.Context, list model.List) (model.List, error)
+	Move(ctx context.Context, list model.List) (model.List, error)
 }
 
-// ListInteractor includes repogitories and a logger.
+// ListInteractor includes repogitories, a bus to publish changes and a logger.
 type ListInteractor struct {
-	txRepo    TransactionRepository
-	itemRepo  ItemRepository
-	listRepo  ListRepository
-	boardRepo BoardRepository
-	logger    Logger
+	txRepo        TransactionRepository
+	itemRepo      ItemRepository
+	listRepo      ListRepository
+	boardRepo     BoardRepository
+	memberRepo    MemberRepository
+	checklistRepo ChecklistRepository
+	checkItemRepo CheckItemRepository
+	commentRepo   CommentRepository
+	activityRepo  ActivityRepository
+	tagRepo       TagRepository
+	bus           EventBus
+	logger        Logger
 }
 
 // NewListInteractor generates new interactor for a List.
@@ -28,78 +38,87 @@ func NewListInteractor(
 	itemRepo ItemRepository,
 	listRepo ListRepository,
 	boardRepo BoardRepository,
+	memberRepo MemberRepository,
+	checklistRepo ChecklistRepository,
+	checkItemRepo CheckItemRepository,
+	commentRepo CommentRepository,
+	activityRepo ActivityRepository,
+	tagRepo TagRepository,
+	bus EventBus,
 	logger Logger,
 ) (ListInteractor, error) {
 	i := ListInteractor{
-		txRepo:    txRepo,
-		itemRepo:  itemRepo,
-		listRepo:  listRepo,
-		boardRepo: boardRepo,
-		logger:    logger,
+		txRepo:        txRepo,
+		itemRepo:      itemRepo,
+		listRepo:      listRepo,
+		boardRepo:     boardRepo,
+		memberRepo:    memberRepo,
+		checklistRepo: checklistRepo,
+		checkItemRepo: checkItemRepo,
+		commentRepo:   commentRepo,
+		activityRepo:  activityRepo,
+		tagRepo:       tagRepo,
+		bus:           bus,
+		logger:        logger,
 	}
 	return i, nil
 }
 
 // Create saves new List to a repository and returns created List.
-func (i *ListInteractor) Create(list model.List) (model.List, error) {
+func (i *ListInteractor) Create(ctx context.Context, list model.List) (model.List, error) {
 	list.ID = uuid.New().String()
-	if err := i.validateList(list); err != nil {
-		logError(i.logger, err)
-		return model.List{}, err
-	}
+	list.Version = 1
 
-	tx := i.txRepo.BeginTransaction(true)
-	i.logger.Info(formatLogMsg(list.UserID, "Start transaction"))
-
-	// Get last list in board
-	lists, err := i.listRepo.Find(tx, map[string]interface{}{
-		"BoardID": list.BoardID,
-		"UserID":  list.UserID,
-		"After":   "",
-	})
-	if err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-		logError(i.logger, err)
-		return model.List{}, err
-	}
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, list.UserID, func(tx Transaction) error {
+		if err := i.validateList(ctx, tx, list); err != nil {
+			return err
+		}
 
-	if len(lists) > 0 {
-		lastList := lists[0]
-		i.logger.Info(formatLogMsg(list.UserID, "Find last list("+lastList.ID+") in board("+lastList.BoardID+")"))
+		// Put new list at the end of board
+		lists, err := i.listRepo.Find(ctx, tx, ListFilter{
+			BoardIDs: []string{list.BoardID},
+		})
+		if err != nil {
+			return err
+		}
+		lists = sortLists(lists)
 
-		list.Before = lastList.ID
+		list.Rank, err = rankList(ctx, tx, i.listRepo, lists, len(lists))
+		if err != nil {
+			return err
+		}
 
-		query := map[string]interface{}{
-			"After": list.ID,
+		if err := i.listRepo.Create(ctx, tx, list); err != nil {
+			return err
 		}
-		if err := i.listRepo.Update(tx, lastList, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-			logError(i.logger, err)
-			return model.List{}, err
+		i.logger.Info(formatLogMsg(list.UserID, "Create list("+list.ID+")"))
+
+		activity := model.Activity{
+			BoardID:  list.BoardID,
+			UserID:   list.UserID,
+			Action:   model.CREATE,
+			Target:   model.LIST,
+			TargetID: list.ID,
 		}
-		i.logger.Info(formatLogMsg(list.UserID, "Update list("+lastList.ID+")"))
-	} else {
-		i.logger.Info(formatLogMsg(list.UserID, "Find no list in board("+list.BoardID+")"))
-	}
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, nil, list)
+		if err != nil {
+			return err
+		}
+		events = append(events, recorded)
 
-	if err := i.listRepo.Create(tx, list); err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-		logError(i.logger, err)
+		return nil
+	})
+	if err != nil {
 		return model.List{}, err
 	}
-	i.logger.Info(formatLogMsg(list.UserID, "Create list("+list.ID+")"))
-
-	tx.Commit()
-	i.logger.Info(formatLogMsg(list.UserID, "Commit transaction"))
 
+	i.bus.Publish(events...)
 	return list, nil
 }
 
 // Delete removes List in repository.
-func (i *ListInteractor) Delete(list model.List) error {
+func (i *ListInteractor) Delete(ctx context.Context, list model.List) error {
 	if list.ID == "" {
 		i.logger.Info(formatLogMsg(list.UserID, "Invalid list. ID is empty"))
 		return model.InvalidContentError{
@@ -110,285 +129,260 @@ func (i *ListInteractor) Delete(list model.List) error {
 		}
 	}
 
-	tx := i.txRepo.BeginTransaction(true)
-	i.logger.Info(formatLogMsg(list.UserID, "Start transaction"))
-
-	// Get list's info (e.g. list.Before, list.After...) and rewrite 'list'.
-	list, err := i.listRepo.FindByID(tx, list.ID, list.UserID)
-	if err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-		logError(i.logger, err)
-		return err
-	}
-	i.logger.Info(formatLogMsg(list.UserID, "Find list("+list.ID+")"))
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, list.UserID, func(tx Transaction) error {
+		// Get list's info (e.g. list.BoardID...) and rewrite 'list'.
+		userID := list.UserID
+		expected := list.Version
+		list, err := i.listRepo.FindByID(ctx, tx, list.ID)
+		if err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(userID, "Find list("+list.ID+")"))
 
-	if list.Before != "" {
-		// Update list before deleting list
-		before := model.List{
-			ID:     list.Before,
-			UserID: list.UserID,
+		if _, err := authorize(ctx, tx, i.memberRepo, list.BoardID, userID, model.EDITOR); err != nil {
+			return err
 		}
-		query := map[string]interface{}{
-			"After": list.After,
+		if err := checkVersion(expected, list.Version, list, userID, list.ID, "check version of list to delete"); err != nil {
+			return err
 		}
-		if err := i.listRepo.Update(tx, before, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+
+		if err := i.listRepo.Delete(ctx, tx, list); err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(list.UserID, "Update list("+before.ID+") before deleted list("+list.ID+")"))
-	}
+		i.logger.Info(formatLogMsg(list.UserID, "Delete list("+list.ID+")"))
 
-	if list.After != "" {
-		// Update list after deleting list
-		after := model.List{
-			ID:     list.After,
-			UserID: list.UserID,
+		items, err := i.itemRepo.Find(ctx, tx, ItemFilter{
+			ListIDs: []string{list.ID},
+		})
+		if err != nil {
+			return err
 		}
-		query := map[string]interface{}{
-			"Before": list.Before,
+		i.logger.Info(formatLogMsg(list.UserID, "Find items in deleted list("+list.ID+")"))
+
+		for _, item := range items {
+			if err := i.itemRepo.Delete(ctx, tx, item); err != nil {
+				return err
+			}
+			if err := deleteChecklists(ctx, tx, i.checklistRepo, i.checkItemRepo, item); err != nil {
+				return err
+			}
+			if err := deleteComments(ctx, tx, i.commentRepo, item); err != nil {
+				return err
+			}
+		}
+		i.logger.Info(formatLogMsg(list.UserID, "Delete items in deleted list("+list.ID+")"))
+
+		activity := model.Activity{
+			BoardID:  list.BoardID,
+			UserID:   userID,
+			Action:   model.DELETE,
+			Target:   model.LIST,
+			TargetID: list.ID,
 		}
-		if err := i.listRepo.Update(tx, after, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, list, nil)
+		if err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(list.UserID, "Update list("+after.ID+") after deleted list("+list.ID+")"))
-	}
+		events = append(events, recorded)
 
-	if err := i.listRepo.Delete(tx, list); err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-		logError(i.logger, err)
-		return err
-	}
-	i.logger.Info(formatLogMsg(list.UserID, "Delete list("+list.ID+")"))
-
-	items, err := i.itemRepo.Find(tx, map[string]interface{}{
-		"UserID": list.UserID,
-		"ListID": list.ID,
+		return nil
 	})
 	if err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-		logError(i.logger, err)
 		return err
 	}
-	i.logger.Info(formatLogMsg(list.UserID, "Find items in deleted list("+list.ID+")"))
-
-	for _, item := range items {
-		if err := i.itemRepo.Delete(tx, item); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(item.UserID, "Rollback transaction"))
-			logError(i.logger, err)
-			return err
-		}
-	}
-	i.logger.Info(formatLogMsg(list.UserID, "Delete items in deleted list("+list.ID+")"))
-
-	tx.Commit()
-	i.logger.Info(formatLogMsg(list.UserID, "Commit transaction"))
 
+	i.bus.Publish(events...)
 	return nil
 }
 
 // Update replaces a List and returns new List.
-func (i *ListInteractor) Update(list model.List) (model.List, error) {
-	if err := i.validateList(list); err != nil {
-		logError(i.logger, err)
-		return model.List{}, err
-	}
-
-	query := map[string]interface{}{
-		"Title": list.Title,
-	}
-
-	tx := i.txRepo.BeginTransaction(false)
-	if err := i.listRepo.Update(tx, list, query); err != nil {
-		logError(i.logger, err)
-		return model.List{}, err
-	}
-	i.logger.Info(formatLogMsg(list.UserID, "Update list("+list.ID+")"))
-
-	return list, nil
-}
+func (i *ListInteractor) Update(ctx context.Context, list model.List) (model.List, error) {
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, list.UserID, func(tx Transaction) error {
+		// A List can not be moved to other Board by Update. Use Board of saved List.
+		old, err := i.listRepo.FindByID(ctx, tx, list.ID)
+		if err != nil {
+			return err
+		}
+		list.BoardID = old.BoardID
 
-// Move moves Items.
-func (i *ListInteractor) Move(list model.List) error {
-	list.Title = "dummy title"
-	if err := i.validateList(list); err != nil {
-		logError(i.logger, err)
-		return err
-	}
-	list.Title = ""
+		if err := i.validateList(ctx, tx, list); err != nil {
+			return err
+		}
+		if err := checkVersion(list.Version, old.Version, old, list.UserID, list.ID, "check version of list to update"); err != nil {
+			return err
+		}
 
-	tx := i.txRepo.BeginTransaction(true)
-	i.logger.Info(formatLogMsg(list.UserID, "Start transaction"))
+		query := map[string]interface{}{
+			"Title": list.Title,
+		}
 
-	// Get a list to move
-	if list.Before != "" {
-		before, err := i.listRepo.FindByID(tx, list.Before, list.UserID)
-		if err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+		if err := i.listRepo.Update(ctx, tx, old, query); err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(list.UserID, "Find a list("+before.ID+") before list to move"))
-		if list.BoardID != before.BoardID {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-			i.logger.Info(formatLogMsg(list.UserID, "Invalid list id("+list.BoardID+"). Does not equal before list's list id("+before.BoardID+")"))
+		i.logger.Info(formatLogMsg(list.UserID, "Update list("+list.ID+")"))
+
+		updated := old
+		updated.Title = list.Title
+		updated.Version = old.Version + 1
+		list.Version = updated.Version
+		activity := model.Activity{
+			BoardID:  list.BoardID,
+			UserID:   list.UserID,
+			Action:   model.UPDATE,
+			Target:   model.LIST,
+			TargetID: list.ID,
+		}
+		recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, old, updated)
+		if err != nil {
 			return err
 		}
-	}
+		events = append(events, recorded)
 
-	// Get a list to move
-	old, err := i.listRepo.FindByID(tx, list.ID, list.UserID)
+		return nil
+	})
 	if err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-		logError(i.logger, err)
-		return err
+		return model.List{}, err
 	}
-	i.logger.Info(formatLogMsg(list.UserID, "Find list("+list.ID+") to move"))
 
-	// Get a list before list to move
-	if old.Before != "" {
-		beforeOld := model.List{
-			ID:     old.Before,
-			UserID: old.UserID,
-		}
-		query := map[string]interface{}{
-			"After": old.After,
-		}
-		if err := i.listRepo.Update(tx, beforeOld, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+	i.bus.Publish(events...)
+	return list, nil
+}
+
+// Move moves Lists and returns the moved List.
+func (i *ListInteractor) Move(ctx context.Context, list model.List) (model.List, error) {
+	var moved model.List
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, list.UserID, func(tx Transaction) error {
+		list.Title = "dummy title"
+		if err := i.validateList(ctx, tx, list); err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(list.UserID, "Find a list("+beforeOld.ID+") before list("+list.ID+") to move"))
-	}
+		list.Title = ""
 
-	// Get a list after list to move
-	if old.After != "" {
-		afterOld := model.List{
-			ID:     old.After,
-			UserID: old.UserID,
+		// Get a list to move
+		old, err := i.listRepo.FindByID(ctx, tx, list.ID)
+		if err != nil {
+			return err
 		}
-		query := map[string]interface{}{
-			"Before": old.Before,
+		i.logger.Info(formatLogMsg(list.UserID, "Find list("+list.ID+") to move"))
+
+		// A user needs permission for both Boards to move a List between Boards.
+		if old.BoardID != list.BoardID {
+			if _, err := authorize(ctx, tx, i.memberRepo, old.BoardID, list.UserID, model.EDITOR); err != nil {
+				return err
+			}
 		}
-		if err := i.listRepo.Update(tx, afterOld, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+		if err := checkVersion(list.Version, old.Version, old, list.UserID, list.ID, "check version of list to move"); err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(list.UserID, "Find a list("+afterOld.ID+") after list("+list.ID+") to move"))
-	}
 
-	// Get a list after moved list
-	if list.Before == "" {
-		conditions := map[string]interface{}{
-			"BoardID": list.BoardID,
-			"UserID":  list.UserID,
-			"Before":  "",
-		}
-		l, err := i.listRepo.Find(tx, conditions)
+		// Get other lists in destination board
+		lists, err := i.listRepo.Find(ctx, tx, ListFilter{
+			BoardIDs: []string{list.BoardID},
+		})
 		if err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-			logError(i.logger, err)
 			return err
 		}
-		if len(l) == 1 {
-			list.After = l[0].ID
-		} else if len(l) > 1 {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-			err = model.ServerError{
-				ID:     list.ID,
+		others := model.Lists{}
+		ids := []string{}
+		for _, l := range sortLists(lists) {
+			if l.ID != old.ID {
+				others = append(others, l)
+				ids = append(ids, l.ID)
+			}
+		}
+
+		index := positionAfter(ids, list.Before)
+		if index < 0 {
+			return model.InvalidContentError{
 				UserID: list.UserID,
 				Err:    nil,
-				Act:    "find a list before moved list",
+				ID:     list.ID,
+				Act:    "validate list(" + list.Before + ") before moved list in board(" + list.BoardID + ")",
 			}
-			logError(i.logger, err)
-			return err
 		}
-	} else {
-		before, err := i.listRepo.FindByID(tx, list.Before, list.UserID)
+
+		rank, err := rankList(ctx, tx, i.listRepo, others, index)
 		if err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-			logError(i.logger, err)
 			return err
 		}
-		list.After = before.After
-	}
-	i.logger.Info(formatLogMsg(list.UserID, "Find a list("+list.After+") after moved list("+list.ID+")"))
 
-	// Update a list before moved list
-	if list.Before != "" {
-		before := model.List{
-			ID:     list.Before,
-			UserID: list.UserID,
-		}
+		// Move list
 		query := map[string]interface{}{
-			"After": list.ID,
+			"BoardID": list.BoardID,
+			"Rank":    rank,
 		}
-		if err := i.listRepo.Update(tx, before, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-			logError(i.logger, err)
+		if err := i.listRepo.Update(ctx, tx, old, query); err != nil {
 			return err
 		}
-		i.logger.Info(formatLogMsg(list.UserID, "Update list("+before.ID+") before list("+list.ID+") in board("+list.BoardID+")"))
-	}
-
-	// Update a list after moved list
-	if list.After != "" {
-		after := model.List{
-			ID:     list.After,
-			UserID: list.UserID,
+		i.logger.Info(formatLogMsg(list.UserID, "Move list("+list.ID+") after list("+list.Before+") in board("+list.BoardID+")"))
+
+		// Tags in a Board are detached from Items in a List moved to other Board.
+		if old.BoardID != list.BoardID {
+			items, err := i.itemRepo.Find(ctx, tx, ItemFilter{
+				ListIDs: []string{list.ID},
+			})
+			if err != nil {
+				return err
+			}
+			for _, item := range items {
+				kept, err := tagsForBoard(ctx, tx, i.tagRepo, item.Tags, list.BoardID)
+				if err != nil {
+					return err
+				}
+				if len(kept) == len(item.Tags) {
+					continue
+				}
+				tags := []string{}
+				for _, t := range kept {
+					tags = append(tags, t.ID)
+				}
+				if err := i.itemRepo.Update(ctx, tx, item, map[string]interface{}{"Tags": tags}); err != nil {
+					return err
+				}
+			}
+			i.logger.Info(formatLogMsg(list.UserID, "Detach tags in board("+old.BoardID+") from items in list("+list.ID+")"))
 		}
-		query := map[string]interface{}{
-			"Before": list.ID,
+
+		// A List moved between Boards is recorded in both Boards.
+		moved = old
+		moved.BoardID = list.BoardID
+		moved.Rank = rank
+		moved.Version = old.Version + 1
+		// They are recorded in order of Board IDs, so that repositories locking Boards lock them in the same order.
+		boardIDs := []string{list.BoardID}
+		if old.BoardID != list.BoardID {
+			boardIDs = append(boardIDs, old.BoardID)
 		}
-		if err := i.listRepo.Update(tx, after, query); err != nil {
-			tx.Rollback()
-			i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-			logError(i.logger, err)
-			return err
+		sort.Strings(boardIDs)
+		for _, boardID := range boardIDs {
+			activity := model.Activity{
+				BoardID:  boardID,
+				UserID:   list.UserID,
+				Action:   model.MOVE,
+				Target:   model.LIST,
+				TargetID: list.ID,
+			}
+			recorded, err := recordActivity(ctx, tx, i.activityRepo, activity, old, moved)
+			if err != nil {
+				return err
+			}
+			events = append(events, recorded)
 		}
-		i.logger.Info(formatLogMsg(list.UserID, "Update list("+after.ID+") after list("+list.ID+") in board("+list.BoardID+")"))
-	}
 
-	// Move list
-	query := map[string]interface{}{
-		"BoardID": list.BoardID,
-		"Before":  list.Before,
-		"After":   list.After,
-	}
-	if err := i.listRepo.Update(tx, list, query); err != nil {
-		tx.Rollback()
-		i.logger.Info(formatLogMsg(list.UserID, "Rollback transaction"))
-		logError(i.logger, err)
-		return err
+		return nil
+	})
+	if err != nil {
+		return model.List{}, err
 	}
-	i.logger.Info(formatLogMsg(list.UserID, "Move list("+list.ID+") after list("+list.Before+") in board("+list.BoardID+")"))
 
-	tx.Commit()
-	i.logger.Info(formatLogMsg(list.UserID, "Commit transaction"))
-
-	return nil
+	i.bus.Publish(events...)
+	return moved, nil
 }
 
-func (i *ListInteractor) validateList(list model.List) error {
+func (i *ListInteractor) validateList(ctx context.Context, tx Transaction, list model.List) error {
 	if list.ID == "" || list.Title == "" || list.BoardID == "" || list.UserID == "" {
 		return model.InvalidContentError{
 			UserID: list.UserID,
@@ -398,27 +392,37 @@ func (i *ListInteractor) validateList(list model.List) error {
 		}
 	}
 
-	tx := i.txRepo.BeginTransaction(false)
-
-	_, err := i.boardRepo.FindByID(tx, list.BoardID, list.UserID)
-	if err != nil {
-		return err
-	}
-	return nil
+	_, err := authorize(ctx, tx, i.memberRepo, list.BoardID, list.UserID, model.EDITOR)
+	return err
 }
 
-func sortLists(lists model.Lists) model.Lists {
-	ls := map[string]model.List{}
-	for _, l := range lists {
-		ls[l.Before] = l
+// rankList returns a rank to put a List at index in sorted Lists of a Board.
+// The Lists are rebalanced if the rank gets too long.
+func rankList(ctx context.Context, tx Transaction, listRepo ListRepository, lists model.Lists, index int) (string, error) {
+	ranks := []string{}
+	for _, x := range lists {
+		ranks = append(ranks, x.Rank)
+	}
+	if rank, ok := rankAt(ranks, index); ok {
+		return rank, nil
 	}
 
-	list := ls[""]
-	r := model.Lists{}
-	for range lists {
-		r = append(r, list)
-		list = ls[list.ID]
+	ranks = model.Ranks(len(lists))
+	for j, x := range lists {
+		if err := listRepo.Update(ctx, tx, x, map[string]interface{}{"Rank": ranks[j]}); err != nil {
+			return "", err
+		}
 	}
+	rank, _ := rankAt(ranks, index)
+	return rank, nil
+}
 
-	return r
+func sortLists(lists model.Lists) model.Lists {
+	sort.SliceStable(lists, func(a, b int) bool {
+		if lists[a].Rank != lists[b].Rank {
+			return lists[a].Rank < lists[b].Rank
+		}
+		return lists[a].ID < lists[b].ID
+	})
+	return lists
 }
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -8,7 +9,7 @@ import (
 )
 
 func logError(logger Logger, err error) {
-	if errors.Is(err, model.ServerError{}) {
+	if errors.Is(err, model.ServerError{}) || errors.Is(err, model.TimeoutError{}) {
 		logger.Error(err.Error())
 	} else {
 		logger.Info(err.Error())
@@ -16,6 +17,18 @@ func logError(logger Logger, err error) {
 	return
 }
 
+// runInTx runs f in a transaction by txRepo and logs states of the transaction.
+func runInTx(ctx context.Context, txRepo TransactionRepository, logger Logger, userID string, f func(tx Transaction) error) error {
+	logger.Info(formatLogMsg(userID, "Start transaction"))
+	if err := txRepo.RunInTx(ctx, f); err != nil {
+		logger.Info(formatLogMsg(userID, "Rollback transaction"))
+		logError(logger, err)
+		return err
+	}
+	logger.Info(formatLogMsg(userID, "Commit transaction"))
+	return nil
+}
+
 func formatLogMsg(id, msg string) string {
 	return fmt.Sprintf("%s %s", id, msg)
 }
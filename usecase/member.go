@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/x-color/vue-trello/model"
+)
+
+// MemberUsecase is interface. It defines to control members of a Board.
+type MemberUsecase interface {
+	GetMembers(ctx context.Context, user model.User, board model.Board) (model.Members, error)
+	Invite(ctx context.Context, user model.User, member model.Member) (model.Member, error)
+	Update(ctx context.Context, user model.User, member model.Member) (model.Member, error)
+	Remove(ctx context.Context, user model.User, member model.Member) error
+}
+
+// MemberInteractor includes repogitories and a logger.
+type MemberInteractor struct {
+	txRepo     TransactionRepository
+	memberRepo MemberRepository
+	userRepo   UserRepository
+	logger     Logger
+}
+
+// NewMemberInteractor generates new interactor for a Member.
+func NewMemberInteractor(
+	txRepo TransactionRepository,
+	memberRepo MemberRepository,
+	userRepo UserRepository,
+	logger Logger,
+) (MemberInteractor, error) {
+	i := MemberInteractor{
+		txRepo:     txRepo,
+		memberRepo: memberRepo,
+		userRepo:   userRepo,
+		logger:     logger,
+	}
+	return i, nil
+}
+
+// GetMembers returns members of a Board. Only members of the Board can get them.
+func (i *MemberInteractor) GetMembers(ctx context.Context, user model.User, board model.Board) (model.Members, error) {
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	if _, err := authorize(ctx, tx, i.memberRepo, board.ID, user.ID, model.VIEWER); err != nil {
+		logError(i.logger, err)
+		return model.Members{}, err
+	}
+
+	members, err := i.memberRepo.Find(ctx, tx, MemberFilter{
+		BoardIDs: []string{board.ID},
+	})
+	if err != nil {
+		logError(i.logger, err)
+		return model.Members{}, err
+	}
+
+	for j, m := range members {
+		u, err := i.userRepo.Find(ctx, tx, UserFilter{
+			IDs: []string{m.UserID},
+		})
+		if err != nil {
+			logError(i.logger, err)
+			return model.Members{}, err
+		}
+		members[j].UserName = u.Name
+	}
+	i.logger.Info(formatLogMsg(user.ID, "Get members of board("+board.ID+")"))
+
+	return members, nil
+}
+
+// Invite adds a user to members of a Board. Only an owner of the Board can invite users.
+func (i *MemberInteractor) Invite(ctx context.Context, user model.User, member model.Member) (model.Member, error) {
+	if err := validateRole(member, model.EDITOR, model.VIEWER); err != nil {
+		logError(i.logger, err)
+		return model.Member{}, err
+	}
+
+	err := runInTx(ctx, i.txRepo, i.logger, user.ID, func(tx Transaction) error {
+		if _, err := authorize(ctx, tx, i.memberRepo, member.BoardID, user.ID, model.OWNER); err != nil {
+			return err
+		}
+
+		u, err := i.userRepo.Find(ctx, tx, UserFilter{
+			Names: []string{member.UserName},
+		})
+		if err != nil {
+			return err
+		}
+		member.UserID = u.ID
+		i.logger.Info(formatLogMsg(user.ID, "Find user("+u.ID+") to invite"))
+
+		_, err = i.memberRepo.FindByID(ctx, tx, member.BoardID, member.UserID)
+		if err == nil {
+			return model.ConflictError{
+				UserID: user.ID,
+				Err:    nil,
+				ID:     member.UserID,
+				Act:    "validate member",
+			}
+		}
+		if !errors.Is(err, model.NotFoundError{}) {
+			return err
+		}
+
+		if err := i.memberRepo.Create(ctx, tx, member); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(user.ID, "Invite user("+member.UserID+") to board("+member.BoardID+") as "+string(member.Role)))
+
+		return nil
+	})
+	if err != nil {
+		return model.Member{}, err
+	}
+
+	return member, nil
+}
+
+// Update changes a role of a member. Only an owner of the Board can change roles.
+func (i *MemberInteractor) Update(ctx context.Context, user model.User, member model.Member) (model.Member, error) {
+	if err := validateRole(member, model.EDITOR, model.VIEWER); err != nil {
+		logError(i.logger, err)
+		return model.Member{}, err
+	}
+
+	err := runInTx(ctx, i.txRepo, i.logger, user.ID, func(tx Transaction) error {
+		if _, err := authorize(ctx, tx, i.memberRepo, member.BoardID, user.ID, model.OWNER); err != nil {
+			return err
+		}
+
+		old, err := i.memberRepo.FindByID(ctx, tx, member.BoardID, member.UserID)
+		if err != nil {
+			return err
+		}
+		if old.Role == model.OWNER {
+			return model.InvalidContentError{
+				UserID: user.ID,
+				Err:    nil,
+				ID:     member.UserID,
+				Act:    "change role of owner",
+			}
+		}
+
+		query := map[string]interface{}{
+			"Role": string(member.Role),
+		}
+		if err := i.memberRepo.Update(ctx, tx, member, query); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(user.ID, "Change role of user("+member.UserID+") in board("+member.BoardID+") to "+string(member.Role)))
+
+		return nil
+	})
+	if err != nil {
+		return model.Member{}, err
+	}
+
+	return member, nil
+}
+
+// Remove removes a member from a Board.
+// An owner can remove any other members, and other members can only leave the Board by themselves.
+func (i *MemberInteractor) Remove(ctx context.Context, user model.User, member model.Member) error {
+	return runInTx(ctx, i.txRepo, i.logger, user.ID, func(tx Transaction) error {
+		required := model.OWNER
+		if member.UserID == user.ID {
+			required = model.VIEWER
+		}
+		if _, err := authorize(ctx, tx, i.memberRepo, member.BoardID, user.ID, required); err != nil {
+			return err
+		}
+
+		member, err := i.memberRepo.FindByID(ctx, tx, member.BoardID, member.UserID)
+		if err != nil {
+			return err
+		}
+		if member.Role == model.OWNER {
+			return model.InvalidContentError{
+				UserID: user.ID,
+				Err:    nil,
+				ID:     member.UserID,
+				Act:    "remove owner from board",
+			}
+		}
+
+		if err := i.memberRepo.Delete(ctx, tx, member); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(user.ID, "Remove user("+member.UserID+") from board("+member.BoardID+")"))
+
+		return nil
+	})
+}
+
+func validateRole(member model.Member, roles ...model.Role) error {
+	for _, r := range roles {
+		if member.Role == r {
+			return nil
+		}
+	}
+	return model.InvalidContentError{
+		UserID: member.UserID,
+		Err:    nil,
+		ID:     member.BoardID,
+		Act:    "validate role of member",
+	}
+}
+
+// authorize checks that a user is a member of a Board and has the required role at least.
+// If a request is authenticated by AccessToken, the Board and the role must also be in its scopes.
+func authorize(ctx context.Context, tx Transaction, memberRepo MemberRepository, boardID, userID string, required model.Role) (model.Member, error) {
+	if token, ok := accessTokenOf(ctx); ok {
+		if (token.BoardID != "" && token.BoardID != boardID) || (token.ReadOnly && roleLevel(required) > roleLevel(model.VIEWER)) {
+			return model.Member{}, model.ForbiddenError{
+				UserID: userID,
+				Err:    nil,
+				ID:     boardID,
+				Act:    "authorize scopes of access token(" + token.ID + ")",
+			}
+		}
+	}
+
+	member, err := memberRepo.FindByID(ctx, tx, boardID, userID)
+	if err != nil {
+		return model.Member{}, err
+	}
+
+	if roleLevel(member.Role) < roleLevel(required) {
+		return model.Member{}, model.ForbiddenError{
+			UserID: userID,
+			Err:    nil,
+			ID:     boardID,
+			Act:    "authorize " + string(required) + " of board",
+		}
+	}
+	return member, nil
+}
+
+// authorizeList checks that a user has the required role in a Board including a List.
+func authorizeList(ctx context.Context, tx Transaction, listRepo ListRepository, memberRepo MemberRepository, listID, userID string, required model.Role) (model.List, error) {
+	list, err := listRepo.FindByID(ctx, tx, listID)
+	if err != nil {
+		return model.List{}, err
+	}
+
+	if _, err := authorize(ctx, tx, memberRepo, list.BoardID, userID, required); err != nil {
+		return model.List{}, err
+	}
+	return list, nil
+}
+
+// authorizeItem checks that a user has the required role in a Board including a Item.
+func authorizeItem(ctx context.Context, tx Transaction, itemRepo ItemRepository, listRepo ListRepository, memberRepo MemberRepository, itemID, userID string, required model.Role) (model.Item, error) {
+	item, err := itemRepo.FindByID(ctx, tx, itemID)
+	if err != nil {
+		return model.Item{}, err
+	}
+
+	if _, err := authorizeList(ctx, tx, listRepo, memberRepo, item.ListID, userID, required); err != nil {
+		return model.Item{}, err
+	}
+	return item, nil
+}
+
+func roleLevel(role model.Role) int {
+	switch role {
+	case model.OWNER:
+		return 3
+	case model.EDITOR:
+		return 2
+	case model.VIEWER:
+		return 1
+	default:
+		return 0
+	}
+}
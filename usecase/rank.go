@@ -0,0 +1,40 @@
+package usecase
+
+import "github.com/x-color/vue-trello/model"
+
+// maxRankLength is the longest length of a rank. Ranks in a parent are rebalanced
+// if a new rank gets longer than it.
+const maxRankLength = 24
+
+// rankAt returns a rank to put data at index in ranks sorted in ascending order.
+// It returns false if the ranks have to be rebalanced to put data (e.g. ranks are too long or duplicated).
+func rankAt(ranks []string, index int) (string, bool) {
+	prev := ""
+	if index > 0 {
+		prev = ranks[index-1]
+	}
+	next := ""
+	if index < len(ranks) {
+		next = ranks[index]
+	}
+	if next != "" && prev >= next {
+		return "", false
+	}
+
+	rank := model.RankBetween(prev, next)
+	return rank, len(rank) <= maxRankLength
+}
+
+// positionAfter returns an index to put data after data had before as ID.
+// It returns 0 if before is empty and -1 if before is not in ids.
+func positionAfter(ids []string, before string) int {
+	if before == "" {
+		return 0
+	}
+	for j, id := range ids {
+		if id == before {
+			return j + 1
+		}
+	}
+	return -1
+}
@@ -1,43 +1,49 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/x-color/vue-trello/model"
 )
 
 // ResourceUsecase is interface. It defines getter for tags and colors.
 type ResourceUsecase interface {
-	GetAllTagsandColors() (model.Tags, model.Colors, error)
+	GetAllTagsandColors(ctx context.Context, user model.User) (model.Tags, model.Colors, error)
 }
 
 // ResourceInteractor includes repogitories and a logger.
 type ResourceInteractor struct {
-	txRepo  TransactionRepository
-	tagRepo TagRepository
-	logger  Logger
+	txRepo     TransactionRepository
+	tagRepo    TagRepository
+	memberRepo MemberRepository
+	logger     Logger
 }
 
 // NewResourceInteractor generates new interactor for resources.
 func NewResourceInteractor(
 	txRepo TransactionRepository,
 	tagRepo TagRepository,
+	memberRepo MemberRepository,
 	logger Logger,
 ) (ResourceInteractor, error) {
 	i := ResourceInteractor{
-		txRepo:  txRepo,
-		tagRepo: tagRepo,
-		logger:  logger,
+		txRepo:     txRepo,
+		tagRepo:    tagRepo,
+		memberRepo: memberRepo,
+		logger:     logger,
 	}
 	return i, nil
 }
 
-// GetAllTagsandColors returns all Tags and Colors.
-func (i *ResourceInteractor) GetAllTagsandColors() (model.Tags, model.Colors, error) {
-	tx := i.txRepo.BeginTransaction(false)
-	tags, err := i.tagRepo.Find(tx, map[string]interface{}{})
+// GetAllTagsandColors returns all Tags visible to User and Colors.
+// Visible Tags are default Tags, Tags of User and Tags in Boards which User is a member of.
+func (i *ResourceInteractor) GetAllTagsandColors(ctx context.Context, user model.User) (model.Tags, model.Colors, error) {
+	tx := i.txRepo.BeginTransaction(ctx, false)
+	tags, err := visibleTags(ctx, tx, i.tagRepo, i.memberRepo, user.ID)
 	if err != nil {
 		logError(i.logger, err)
 		return model.Tags{}, model.Colors{}, err
 	}
-	i.logger.Info(formatLogMsg("(No-ID)", "Get resources"))
+	i.logger.Info(formatLogMsg(user.ID, "Get resources"))
 	return tags, model.COLORS, nil
 }
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/x-color/vue-trello/model"
+)
+
+// sessionTTL is a lifetime of a Session and a JWT of it.
+const sessionTTL = 72 * time.Hour
+
+// SessionUsecase is interface. It defines to control Sessions of signed in users.
+type SessionUsecase interface {
+	Create(ctx context.Context, user model.User) (model.Session, error)
+	Authenticate(ctx context.Context, session model.Session) (model.Session, error)
+	GetSessions(ctx context.Context, user model.User) (model.Sessions, error)
+	Revoke(ctx context.Context, session model.Session) error
+	RevokeAll(ctx context.Context, user model.User) error
+}
+
+// SessionInteractor includes repogitories and a logger.
+type SessionInteractor struct {
+	txRepo      TransactionRepository
+	sessionRepo SessionRepository
+	logger      Logger
+}
+
+// NewSessionInteractor generates new interactor for a Session.
+func NewSessionInteractor(
+	txRepo TransactionRepository,
+	sessionRepo SessionRepository,
+	logger Logger,
+) (SessionInteractor, error) {
+	i := SessionInteractor{
+		txRepo:      txRepo,
+		sessionRepo: sessionRepo,
+		logger:      logger,
+	}
+	return i, nil
+}
+
+// Create starts new Session of a signed in user. Expired Sessions of the user are removed together.
+func (i *SessionInteractor) Create(ctx context.Context, user model.User) (model.Session, error) {
+	now := time.Now()
+	session := model.Session{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		ExpiresAt: now.Add(sessionTTL),
+		CreatedAt: now,
+	}
+
+	err := runInTx(ctx, i.txRepo, i.logger, user.ID, func(tx Transaction) error {
+		sessions, err := i.sessionRepo.Find(ctx, tx, SessionFilter{
+			UserIDs: []string{user.ID},
+		})
+		if err != nil {
+			return err
+		}
+		for _, s := range sessions {
+			if !s.Expired(now) {
+				continue
+			}
+			if err := i.sessionRepo.Delete(ctx, tx, s); err != nil {
+				return err
+			}
+		}
+
+		if err := i.sessionRepo.Create(ctx, tx, session); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(user.ID, "Create session("+session.ID+")"))
+
+		return nil
+	})
+	if err != nil {
+		return model.Session{}, err
+	}
+
+	return session, nil
+}
+
+// Authenticate returns a Session had session.ID if it is a Session of session.UserID and it is not expired.
+// Otherwise it returns NotFoundError.
+func (i *SessionInteractor) Authenticate(ctx context.Context, session model.Session) (model.Session, error) {
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	saved, err := i.sessionRepo.FindByID(ctx, tx, session.ID)
+	if err != nil {
+		logError(i.logger, err)
+		return model.Session{}, err
+	}
+	if saved.UserID != session.UserID || saved.Expired(time.Now()) {
+		err := model.NotFoundError{
+			UserID: session.UserID,
+			Err:    nil,
+			ID:     session.ID,
+			Act:    "validate session",
+		}
+		logError(i.logger, err)
+		return model.Session{}, err
+	}
+
+	return saved, nil
+}
+
+// GetSessions returns Sessions of a user which are not expired.
+func (i *SessionInteractor) GetSessions(ctx context.Context, user model.User) (model.Sessions, error) {
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	sessions, err := i.sessionRepo.Find(ctx, tx, SessionFilter{
+		UserIDs: []string{user.ID},
+	})
+	if err != nil {
+		logError(i.logger, err)
+		return model.Sessions{}, err
+	}
+
+	now := time.Now()
+	active := model.Sessions{}
+	for _, s := range sessions {
+		if !s.Expired(now) {
+			active = append(active, s)
+		}
+	}
+
+	i.logger.Info(formatLogMsg(user.ID, "Get sessions"))
+	return active, nil
+}
+
+// Revoke removes a Session of a user, so that the JWT of it can not be used.
+func (i *SessionInteractor) Revoke(ctx context.Context, session model.Session) error {
+	return runInTx(ctx, i.txRepo, i.logger, session.UserID, func(tx Transaction) error {
+		saved, err := i.sessionRepo.FindByID(ctx, tx, session.ID)
+		if err != nil {
+			return err
+		}
+		if saved.UserID != session.UserID {
+			return model.NotFoundError{
+				UserID: session.UserID,
+				Err:    nil,
+				ID:     session.ID,
+				Act:    "find session of user",
+			}
+		}
+
+		if err := i.sessionRepo.Delete(ctx, tx, saved); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(session.UserID, "Revoke session("+session.ID+")"))
+
+		return nil
+	})
+}
+
+// RevokeAll removes all Sessions of a user to sign out of all devices.
+func (i *SessionInteractor) RevokeAll(ctx context.Context, user model.User) error {
+	return runInTx(ctx, i.txRepo, i.logger, user.ID, func(tx Transaction) error {
+		sessions, err := i.sessionRepo.Find(ctx, tx, SessionFilter{
+			UserIDs: []string{user.ID},
+		})
+		if err != nil {
+			return err
+		}
+		for _, s := range sessions {
+			if err := i.sessionRepo.Delete(ctx, tx, s); err != nil {
+				return err
+			}
+		}
+		i.logger.Info(formatLogMsg(user.ID, "Revoke all sessions"))
+
+		return nil
+	})
+}
@@ -0,0 +1,473 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/x-color/vue-trello/model"
+)
+
+// syncPageSize is a maximum number of Activities read for a page of changes.
+const syncPageSize = 500
+
+// SyncUsecase is interface. It defines to sync Boards with offline clients.
+type SyncUsecase interface {
+	Changes(ctx context.Context, user model.User, cursor string) (Feed, error)
+	Apply(ctx context.Context, user model.User, ops []Operation) []OperationResult
+}
+
+// Change is a Board, List or Item changed since a cursor. Only a field for Target is set to current data.
+// A deleted data has only ID. Children of a deleted data are deleted too, even if they are not included.
+type Change struct {
+	Target  model.Target
+	ID      string
+	Deleted bool
+	Board   model.Board
+	List    model.List
+	Item    model.Item
+}
+
+// Feed is a page of changes for a user. Cursor is given to get next changes, and More is true if there are
+// already next changes. BoardIDs has all Boards the user can view now. Boards not in it are deleted or
+// left, and a Board not known by a client (e.g. the user is invited to it) has to be got whole.
+type Feed struct {
+	Cursor   string
+	More     bool
+	BoardIDs []string
+	Changes  []Change
+}
+
+// Operation is a change made by an offline client. A field for Target has arguments of Action,
+// and its Version is a version expected like If-Match. IDs given to created data by a client can be used
+// in following Operations instead of real IDs.
+type Operation struct {
+	Action model.Action
+	Target model.Target
+	Board  model.Board
+	List   model.List
+	Item   model.Item
+}
+
+// OperationResult is a result of an Operation. A field for Target is set to the data applied.
+type OperationResult struct {
+	Board model.Board
+	List  model.List
+	Item  model.Item
+	Err   error
+}
+
+// SyncInteractor includes repogitories, interactors to apply operations and a logger.
+type SyncInteractor struct {
+	txRepo        TransactionRepository
+	activityRepo  ActivityRepository
+	memberRepo    MemberRepository
+	boardRepo     BoardRepository
+	listRepo      ListRepository
+	itemRepo      ItemRepository
+	checkItemRepo CheckItemRepository
+	boards        BoardUsecase
+	lists         ListUsecase
+	items         ItemUsecase
+	logger        Logger
+}
+
+// NewSyncInteractor generates new interactor to sync Boards.
+func NewSyncInteractor(
+	txRepo TransactionRepository,
+	activityRepo ActivityRepository,
+	memberRepo MemberRepository,
+	boardRepo BoardRepository,
+	listRepo ListRepository,
+	itemRepo ItemRepository,
+	checkItemRepo CheckItemRepository,
+	boards BoardUsecase,
+	lists ListUsecase,
+	items ItemUsecase,
+	logger Logger,
+) (SyncInteractor, error) {
+	i := SyncInteractor{
+		txRepo:        txRepo,
+		activityRepo:  activityRepo,
+		memberRepo:    memberRepo,
+		boardRepo:     boardRepo,
+		listRepo:      listRepo,
+		itemRepo:      itemRepo,
+		checkItemRepo: checkItemRepo,
+		boards:        boards,
+		lists:         lists,
+		items:         items,
+		logger:        logger,
+	}
+	return i, nil
+}
+
+// Changes returns Boards, Lists and Items changed in Boards of a user since cursor. Each changed data is
+// included once with its current state. If cursor is empty, all data in the Boards is returned.
+func (i *SyncInteractor) Changes(ctx context.Context, user model.User, cursor string) (Feed, error) {
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	members, err := i.memberRepo.Find(ctx, tx, MemberFilter{
+		UserIDs: []string{user.ID},
+	})
+	if err != nil {
+		logError(i.logger, err)
+		return Feed{}, err
+	}
+	boardIDs := []string{}
+	for _, m := range members {
+		boardIDs = append(boardIDs, m.BoardID)
+	}
+
+	feed := Feed{
+		Cursor:   cursor,
+		BoardIDs: boardIDs,
+		Changes:  []Change{},
+	}
+	if cursor == "" {
+		err = i.snapshot(ctx, tx, &feed)
+	} else {
+		err = i.changesSince(ctx, tx, &feed)
+	}
+	if err != nil {
+		logError(i.logger, err)
+		return Feed{}, err
+	}
+
+	i.logger.Info(formatLogMsg(user.ID, "Get changes since cursor("+cursor+")"))
+	return feed, nil
+}
+
+// snapshot adds all data in Boards of a feed. The cursor is got first, so that changes made while
+// reading are got again by the next feed.
+func (i *SyncInteractor) snapshot(ctx context.Context, tx Transaction, feed *Feed) error {
+	latest, err := i.activityRepo.Find(ctx, tx, ActivityFilter{
+		BoardIDs: feed.BoardIDs,
+		Desc:     true,
+		Page:     Page{Limit: 1},
+	})
+	if err != nil {
+		return err
+	}
+	if len(latest) > 0 {
+		feed.Cursor = latest[0].ID
+	}
+
+	boards, err := i.boardRepo.Find(ctx, tx, BoardFilter{
+		IDs: feed.BoardIDs,
+	})
+	if err != nil {
+		return err
+	}
+	lists, err := i.listRepo.Find(ctx, tx, ListFilter{
+		BoardIDs: feed.BoardIDs,
+	})
+	if err != nil {
+		return err
+	}
+	listIDs := []string{}
+	for _, l := range lists {
+		listIDs = append(listIDs, l.ID)
+	}
+	items, err := i.itemRepo.Find(ctx, tx, ItemFilter{
+		ListIDs: listIDs,
+	})
+	if err != nil {
+		return err
+	}
+
+	return i.addChanges(ctx, tx, feed, boards, lists, items)
+}
+
+// changesSince adds data changed after the cursor of a feed. Siblings of moved data are also added,
+// because their ranks may be renumbered by the move.
+func (i *SyncInteractor) changesSince(ctx context.Context, tx Transaction, feed *Feed) error {
+	activities, err := i.activityRepo.Find(ctx, tx, ActivityFilter{
+		BoardIDs: feed.BoardIDs,
+		After:    feed.Cursor,
+		Page:     Page{Limit: syncPageSize},
+	})
+	if err != nil {
+		return err
+	}
+	if len(activities) > 0 {
+		feed.Cursor = activities[len(activities)-1].ID
+	}
+	feed.More = len(activities) == syncPageSize
+
+	changed := map[model.Target][]string{
+		model.BOARD: {},
+		model.LIST:  {},
+		model.ITEM:  {},
+	}
+	seen := map[string]bool{}
+	boardMoved := false
+	listMovedIn := []string{}
+	movedItems := map[string]bool{}
+	for _, a := range activities {
+		if _, ok := changed[a.Target]; !ok {
+			continue
+		}
+		if !seen[a.TargetID] {
+			seen[a.TargetID] = true
+			changed[a.Target] = append(changed[a.Target], a.TargetID)
+		}
+		if a.Action != model.MOVE {
+			continue
+		}
+		switch a.Target {
+		case model.BOARD:
+			boardMoved = true
+		case model.LIST:
+			listMovedIn = append(listMovedIn, a.BoardID)
+		case model.ITEM:
+			movedItems[a.TargetID] = true
+		}
+	}
+
+	// Data moved out to a Board which a user is not a member of is reported as deleted,
+	// so only data in Boards of the user is added.
+	member := map[string]bool{}
+	for _, id := range feed.BoardIDs {
+		member[id] = true
+	}
+
+	// Get Boards. Any Board of a user can be renumbered by a move.
+	boardFilter := BoardFilter{IDs: changed[model.BOARD]}
+	if boardMoved {
+		boardFilter.IDs = feed.BoardIDs
+	}
+	found, err := i.boardRepo.Find(ctx, tx, boardFilter)
+	if err != nil {
+		return err
+	}
+	boards := model.Boards{}
+	for _, b := range found {
+		if member[b.ID] {
+			boards = append(boards, b)
+		}
+	}
+
+	// Get Lists and Lists in Boards where a List is moved.
+	foundLists, err := i.listRepo.Find(ctx, tx, ListFilter{IDs: changed[model.LIST]})
+	if err != nil {
+		return err
+	}
+	lists := model.Lists{}
+	for _, l := range foundLists {
+		if member[l.BoardID] {
+			lists = append(lists, l)
+		}
+	}
+	listMovedInMember := []string{}
+	for _, id := range listMovedIn {
+		if member[id] {
+			listMovedInMember = append(listMovedInMember, id)
+		}
+	}
+	if len(listMovedInMember) > 0 {
+		siblings, err := i.listRepo.Find(ctx, tx, ListFilter{BoardIDs: listMovedInMember})
+		if err != nil {
+			return err
+		}
+		for _, l := range siblings {
+			if !seen[l.ID] {
+				lists = append(lists, l)
+			}
+		}
+	}
+
+	// Get Items and Items in Lists where an Item is moved.
+	foundItems, err := i.itemRepo.Find(ctx, tx, ItemFilter{IDs: changed[model.ITEM]})
+	if err != nil {
+		return err
+	}
+	parentIDs := []string{}
+	for _, item := range foundItems {
+		parentIDs = append(parentIDs, item.ListID)
+	}
+	parents, err := i.listRepo.Find(ctx, tx, ListFilter{IDs: parentIDs})
+	if err != nil {
+		return err
+	}
+	memberList := map[string]bool{}
+	for _, l := range parents {
+		memberList[l.ID] = member[l.BoardID]
+	}
+	items := model.Items{}
+	itemMovedIn := []string{}
+	for _, item := range foundItems {
+		if !memberList[item.ListID] {
+			continue
+		}
+		items = append(items, item)
+		if movedItems[item.ID] {
+			itemMovedIn = append(itemMovedIn, item.ListID)
+		}
+	}
+	if len(itemMovedIn) > 0 {
+		siblings, err := i.itemRepo.Find(ctx, tx, ItemFilter{ListIDs: itemMovedIn})
+		if err != nil {
+			return err
+		}
+		for _, item := range siblings {
+			if !seen[item.ID] {
+				items = append(items, item)
+			}
+		}
+	}
+
+	if err := i.addChanges(ctx, tx, feed, boards, lists, items); err != nil {
+		return err
+	}
+
+	// Changed data which is not added has been deleted or moved out of Boards of a user.
+	added := map[string]bool{}
+	for _, c := range feed.Changes {
+		added[c.ID] = true
+	}
+	for _, target := range []model.Target{model.BOARD, model.LIST, model.ITEM} {
+		for _, id := range changed[target] {
+			if !added[id] {
+				feed.Changes = append(feed.Changes, Change{Target: target, ID: id, Deleted: true})
+			}
+		}
+	}
+	return nil
+}
+
+// addChanges adds data to a feed in order of Boards, Lists and Items, so that parents are added first.
+func (i *SyncInteractor) addChanges(ctx context.Context, tx Transaction, feed *Feed, boards model.Boards, lists model.Lists, items model.Items) error {
+	for _, b := range sortBoards(boards) {
+		feed.Changes = append(feed.Changes, Change{Target: model.BOARD, ID: b.ID, Board: b})
+	}
+	for _, l := range sortLists(lists) {
+		feed.Changes = append(feed.Changes, Change{Target: model.LIST, ID: l.ID, List: l})
+	}
+	if err := countCheckItems(ctx, tx, i.checkItemRepo, items); err != nil {
+		return err
+	}
+	for _, item := range sortItems(items) {
+		feed.Changes = append(feed.Changes, Change{Target: model.ITEM, ID: item.ID, Item: item})
+	}
+	return nil
+}
+
+// Apply applies Operations in order by a user. Each Operation is applied in its own transaction,
+// so an Operation failed (e.g. by a conflict) does not prevent following Operations.
+func (i *SyncInteractor) Apply(ctx context.Context, user model.User, ops []Operation) []OperationResult {
+	// IDs given by a client are replaced with real IDs of created data.
+	ids := map[string]string{}
+	realID := func(id string) string {
+		if r, ok := ids[id]; ok {
+			return r
+		}
+		return id
+	}
+
+	results := []OperationResult{}
+	for _, op := range ops {
+		var r OperationResult
+		var clientID, createdID string
+		switch op.Target {
+		case model.BOARD:
+			board := op.Board
+			clientID = board.ID
+			board.ID = realID(board.ID)
+			board.UserID = user.ID
+			board.Before = realID(board.Before)
+			r = i.applyBoard(ctx, op.Action, board)
+			createdID = r.Board.ID
+		case model.LIST:
+			list := op.List
+			clientID = list.ID
+			list.ID = realID(list.ID)
+			list.UserID = user.ID
+			list.BoardID = realID(list.BoardID)
+			list.Before = realID(list.Before)
+			r = i.applyList(ctx, op.Action, list)
+			createdID = r.List.ID
+		case model.ITEM:
+			item := op.Item
+			clientID = item.ID
+			item.ID = realID(item.ID)
+			item.UserID = user.ID
+			item.ListID = realID(item.ListID)
+			item.Before = realID(item.Before)
+			r = i.applyItem(ctx, op.Action, item)
+			createdID = r.Item.ID
+		default:
+			r.Err = invalidOperation(user.ID, op)
+			logError(i.logger, r.Err)
+		}
+
+		if op.Action == model.CREATE && r.Err == nil && clientID != "" {
+			ids[clientID] = createdID
+		}
+		results = append(results, r)
+	}
+
+	i.logger.Info(formatLogMsg(user.ID, "Apply operations"))
+	return results
+}
+
+func (i *SyncInteractor) applyBoard(ctx context.Context, action model.Action, board model.Board) OperationResult {
+	r := OperationResult{}
+	switch action {
+	case model.CREATE:
+		r.Board, r.Err = i.boards.Create(ctx, board)
+	case model.UPDATE:
+		r.Board, r.Err = i.boards.Update(ctx, board)
+	case model.MOVE:
+		r.Board, r.Err = i.boards.Move(ctx, board)
+	case model.DELETE:
+		r.Board, r.Err = model.Board{ID: board.ID}, i.boards.Delete(ctx, board)
+	default:
+		r.Err = invalidOperation(board.UserID, Operation{Action: action, Target: model.BOARD})
+		logError(i.logger, r.Err)
+	}
+	return r
+}
+
+func (i *SyncInteractor) applyList(ctx context.Context, action model.Action, list model.List) OperationResult {
+	r := OperationResult{}
+	switch action {
+	case model.CREATE:
+		r.List, r.Err = i.lists.Create(ctx, list)
+	case model.UPDATE:
+		r.List, r.Err = i.lists.Update(ctx, list)
+	case model.MOVE:
+		r.List, r.Err = i.lists.Move(ctx, list)
+	case model.DELETE:
+		r.List, r.Err = model.List{ID: list.ID}, i.lists.Delete(ctx, list)
+	default:
+		r.Err = invalidOperation(list.UserID, Operation{Action: action, Target: model.LIST})
+		logError(i.logger, r.Err)
+	}
+	return r
+}
+
+func (i *SyncInteractor) applyItem(ctx context.Context, action model.Action, item model.Item) OperationResult {
+	r := OperationResult{}
+	switch action {
+	case model.CREATE:
+		r.Item, r.Err = i.items.Create(ctx, item)
+	case model.UPDATE:
+		r.Item, r.Err = i.items.Update(ctx, item)
+	case model.MOVE:
+		r.Item, r.Err = i.items.Move(ctx, item)
+	case model.DELETE:
+		r.Item, r.Err = model.Item{ID: item.ID}, i.items.Delete(ctx, item)
+	default:
+		r.Err = invalidOperation(item.UserID, Operation{Action: action, Target: model.ITEM})
+		logError(i.logger, r.Err)
+	}
+	return r
+}
+
+func invalidOperation(userID string, op Operation) error {
+	return model.InvalidContentError{
+		UserID: userID,
+		Err:    nil,
+		ID:     "(No-ID)",
+		Act:    "validate operation(" + string(op.Action) + " " + string(op.Target) + ")",
+	}
+}
@@ -0,0 +1,313 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/x-color/vue-trello/model"
+)
+
+// maxTagNameLength is the longest length of a name of Tag.
+const maxTagNameLength = 30
+
+// TagUsecase is interface. It defines to control a Tag in a Board or of a User.
+type TagUsecase interface {
+	Create(ctx context.Context, tag model.Tag) (model.Tag, error)
+	Update(ctx context.Context, tag model.Tag) (model.Tag, error)
+	Delete(ctx context.Context, tag model.Tag) error
+}
+
+// TagInteractor includes repogitories, a bus to publish changes and a logger.
+type TagInteractor struct {
+	txRepo       TransactionRepository
+	tagRepo      TagRepository
+	itemRepo     ItemRepository
+	listRepo     ListRepository
+	memberRepo   MemberRepository
+	activityRepo ActivityRepository
+	bus          EventBus
+	logger       Logger
+}
+
+// NewTagInteractor generates new interactor for a Tag.
+func NewTagInteractor(
+	txRepo TransactionRepository,
+	tagRepo TagRepository,
+	itemRepo ItemRepository,
+	listRepo ListRepository,
+	memberRepo MemberRepository,
+	activityRepo ActivityRepository,
+	bus EventBus,
+	logger Logger,
+) (TagInteractor, error) {
+	i := TagInteractor{
+		txRepo:       txRepo,
+		tagRepo:      tagRepo,
+		itemRepo:     itemRepo,
+		listRepo:     listRepo,
+		memberRepo:   memberRepo,
+		activityRepo: activityRepo,
+		bus:          bus,
+		logger:       logger,
+	}
+	return i, nil
+}
+
+// Create saves new Tag to a repository and returns created Tag.
+// The Tag belongs to a Board if tag.BoardID is not empty, otherwise it belongs to a user had tag.UserID.
+// Editors of a Board can create Tags in it.
+func (i *TagInteractor) Create(ctx context.Context, tag model.Tag) (model.Tag, error) {
+	tag.ID = uuid.New().String()
+	if err := validateTag(tag); err != nil {
+		logError(i.logger, err)
+		return model.Tag{}, err
+	}
+
+	err := runInTx(ctx, i.txRepo, i.logger, tag.UserID, func(tx Transaction) error {
+		if tag.BoardID != "" {
+			if _, err := authorize(ctx, tx, i.memberRepo, tag.BoardID, tag.UserID, model.EDITOR); err != nil {
+				return err
+			}
+		}
+
+		if err := i.tagRepo.Create(ctx, tx, tag); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(tag.UserID, "Create tag("+tag.ID+")"))
+
+		return nil
+	})
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+// Update changes a name and a color of a Tag and returns new Tag.
+func (i *TagInteractor) Update(ctx context.Context, tag model.Tag) (model.Tag, error) {
+	var updated model.Tag
+	err := runInTx(ctx, i.txRepo, i.logger, tag.UserID, func(tx Transaction) error {
+		old, err := i.authorizeTag(ctx, tx, tag.ID, tag.UserID)
+		if err != nil {
+			return err
+		}
+
+		updated = old
+		updated.Name = tag.Name
+		updated.Color = tag.Color
+		if err := validateTag(updated); err != nil {
+			return err
+		}
+
+		query := map[string]interface{}{
+			"Name":  updated.Name,
+			"Color": updated.Color,
+		}
+		if err := i.tagRepo.Update(ctx, tx, old, query); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(tag.UserID, "Update tag("+tag.ID+")"))
+
+		return nil
+	})
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	return updated, nil
+}
+
+// Delete removes a Tag in repository and detaches it from all Items.
+func (i *TagInteractor) Delete(ctx context.Context, tag model.Tag) error {
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, tag.UserID, func(tx Transaction) error {
+		old, err := i.authorizeTag(ctx, tx, tag.ID, tag.UserID)
+		if err != nil {
+			return err
+		}
+
+		events, err = detachTag(ctx, tx, i.itemRepo, i.listRepo, i.activityRepo, old, tag.UserID)
+		if err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(tag.UserID, "Detach tag("+tag.ID+") from items"))
+
+		if err := i.tagRepo.Delete(ctx, tx, old); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(tag.UserID, "Delete tag("+tag.ID+")"))
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	i.bus.Publish(events...)
+	return nil
+}
+
+// detachTag removes a Tag from Items having it. The Items are recorded as updated by a user,
+// so that clients following activities get the Items without the Tag.
+func detachTag(ctx context.Context, tx Transaction, itemRepo ItemRepository, listRepo ListRepository, activityRepo ActivityRepository, tag model.Tag, userID string) (model.Activities, error) {
+	items, err := itemRepo.Find(ctx, tx, ItemFilter{
+		TagIDs: []string{tag.ID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	listIDs := []string{}
+	for _, item := range items {
+		listIDs = append(listIDs, item.ListID)
+	}
+	lists, err := listRepo.Find(ctx, tx, ListFilter{
+		IDs: listIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	boardOf := map[string]string{}
+	for _, l := range lists {
+		boardOf[l.ID] = l.BoardID
+	}
+
+	events := model.Activities{}
+	for _, item := range items {
+		tagIDs := []string{}
+		tags := model.Tags{}
+		for _, t := range item.Tags {
+			if t.ID != tag.ID {
+				tagIDs = append(tagIDs, t.ID)
+				tags = append(tags, t)
+			}
+		}
+		if err := itemRepo.Update(ctx, tx, item, map[string]interface{}{"Tags": tagIDs}); err != nil {
+			return nil, err
+		}
+
+		updated := item
+		updated.Tags = tags
+		updated.Version = item.Version + 1
+		activity := model.Activity{
+			BoardID:  boardOf[item.ListID],
+			UserID:   userID,
+			Action:   model.UPDATE,
+			Target:   model.ITEM,
+			TargetID: item.ID,
+		}
+		recorded, err := recordActivity(ctx, tx, activityRepo, activity, item, updated)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, recorded)
+	}
+	return events, nil
+}
+
+// authorizeTag checks that a user can change a Tag and returns the Tag.
+// Editors of a Board can change Tags in it, and a user can change own Tags. Default Tags can not be changed.
+func (i *TagInteractor) authorizeTag(ctx context.Context, tx Transaction, tagID, userID string) (model.Tag, error) {
+	tag, err := i.tagRepo.FindByID(ctx, tx, tagID)
+	if err != nil {
+		return model.Tag{}, err
+	}
+
+	if tag.BoardID != "" {
+		if _, err := authorize(ctx, tx, i.memberRepo, tag.BoardID, userID, model.EDITOR); err != nil {
+			return model.Tag{}, err
+		}
+		return tag, nil
+	}
+
+	if tag.UserID == "" || tag.UserID != userID {
+		return model.Tag{}, model.ForbiddenError{
+			UserID: userID,
+			Err:    nil,
+			ID:     tagID,
+			Act:    "authorize owner of tag",
+		}
+	}
+	return tag, nil
+}
+
+func validateTag(tag model.Tag) error {
+	if tag.ID == "" || tag.Name == "" || tag.UserID == "" || utf8.RuneCountInString(tag.Name) > maxTagNameLength {
+		return model.InvalidContentError{
+			UserID: tag.UserID,
+			Err:    nil,
+			ID:     tag.ID,
+			Act:    "validate contents in tag",
+		}
+	}
+
+	if !validColor(tag.Color) {
+		return model.InvalidContentError{
+			UserID: tag.UserID,
+			Err:    nil,
+			ID:     tag.ID,
+			Act:    "validate color of tag",
+		}
+	}
+	return nil
+}
+
+// visibleTags returns default Tags, Tags of a user and Tags in Boards which the user is a member of.
+func visibleTags(ctx context.Context, tx Transaction, tagRepo TagRepository, memberRepo MemberRepository, userID string) (model.Tags, error) {
+	tags, err := tagRepo.Find(ctx, tx, TagFilter{
+		UserIDs:  []string{"", userID},
+		BoardIDs: []string{""},
+	})
+	if err != nil {
+		return model.Tags{}, err
+	}
+
+	members, err := memberRepo.Find(ctx, tx, MemberFilter{
+		UserIDs: []string{userID},
+	})
+	if err != nil {
+		return model.Tags{}, err
+	}
+	boardIDs := []string{}
+	for _, m := range members {
+		boardIDs = append(boardIDs, m.BoardID)
+	}
+
+	inBoards, err := tagRepo.Find(ctx, tx, TagFilter{
+		BoardIDs: boardIDs,
+	})
+	if err != nil {
+		return model.Tags{}, err
+	}
+	return append(tags, inBoards...), nil
+}
+
+// usableTag checks that a user can attach a Tag to Items in a Board.
+// Default Tags, Tags in the Board and Tags of the user are usable.
+func usableTag(tag model.Tag, boardID, userID string) bool {
+	if tag.BoardID != "" {
+		return tag.BoardID == boardID
+	}
+	return tag.UserID == "" || tag.UserID == userID
+}
+
+// tagsForBoard returns Tags except ones in other Boards. It is used to move Items between Boards.
+func tagsForBoard(ctx context.Context, tx Transaction, tagRepo TagRepository, tags model.Tags, boardID string) (model.Tags, error) {
+	kept := model.Tags{}
+	for _, t := range tags {
+		tag, err := tagRepo.FindByID(ctx, tx, t.ID)
+		if err != nil {
+			if errors.Is(err, model.NotFoundError{}) {
+				continue
+			}
+			return model.Tags{}, err
+		}
+		if tag.BoardID == "" || tag.BoardID == boardID {
+			kept = append(kept, t)
+		}
+	}
+	return kept, nil
+}
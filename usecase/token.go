@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/x-color/vue-trello/model"
+)
+
+// accessTokenPrefix is a prefix of personal access tokens to tell them from other tokens.
+const accessTokenPrefix = "vtp_"
+
+// AccessTokenUsecase is interface. It defines to control personal access tokens of a user.
+type AccessTokenUsecase interface {
+	GetTokens(ctx context.Context, user model.User) (model.AccessTokens, error)
+	Create(ctx context.Context, token model.AccessToken) (model.AccessToken, error)
+	Revoke(ctx context.Context, token model.AccessToken) error
+	Authenticate(ctx context.Context, token string) (model.AccessToken, error)
+}
+
+// AccessTokenInteractor includes repogitories and a logger.
+type AccessTokenInteractor struct {
+	txRepo     TransactionRepository
+	tokenRepo  AccessTokenRepository
+	memberRepo MemberRepository
+	logger     Logger
+}
+
+// NewAccessTokenInteractor generates new interactor for a AccessToken.
+func NewAccessTokenInteractor(
+	txRepo TransactionRepository,
+	tokenRepo AccessTokenRepository,
+	memberRepo MemberRepository,
+	logger Logger,
+) (AccessTokenInteractor, error) {
+	i := AccessTokenInteractor{
+		txRepo:     txRepo,
+		tokenRepo:  tokenRepo,
+		memberRepo: memberRepo,
+		logger:     logger,
+	}
+	return i, nil
+}
+
+// GetTokens returns AccessTokens of a user without the tokens themselves.
+func (i *AccessTokenInteractor) GetTokens(ctx context.Context, user model.User) (model.AccessTokens, error) {
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	tokens, err := i.tokenRepo.Find(ctx, tx, AccessTokenFilter{
+		UserIDs: []string{user.ID},
+	})
+	if err != nil {
+		logError(i.logger, err)
+		return model.AccessTokens{}, err
+	}
+
+	i.logger.Info(formatLogMsg(user.ID, "Get access tokens"))
+	return tokens, nil
+}
+
+// Create generates new AccessToken and returns it with the token. Only a hash of the token is saved,
+// so the token can not be got again. A token restricted to a Board can be created by members of it.
+func (i *AccessTokenInteractor) Create(ctx context.Context, token model.AccessToken) (model.AccessToken, error) {
+	token.ID = uuid.New().String()
+	token.CreatedAt = time.Now()
+	if token.Name == "" || token.UserID == "" || (!token.ExpiresAt.IsZero() && token.Expired(token.CreatedAt)) {
+		err := model.InvalidContentError{
+			UserID: token.UserID,
+			Err:    nil,
+			ID:     token.ID,
+			Act:    "validate contents in access token",
+		}
+		logError(i.logger, err)
+		return model.AccessToken{}, err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		err = model.ServerError{
+			UserID: token.UserID,
+			Err:    err,
+			ID:     token.ID,
+			Act:    "generate access token",
+		}
+		logError(i.logger, err)
+		return model.AccessToken{}, err
+	}
+	token.Token = accessTokenPrefix + secret
+	token.Hash = hashToken(token.Token)
+
+	err = runInTx(ctx, i.txRepo, i.logger, token.UserID, func(tx Transaction) error {
+		if token.BoardID != "" {
+			if _, err := authorize(ctx, tx, i.memberRepo, token.BoardID, token.UserID, model.VIEWER); err != nil {
+				return err
+			}
+		}
+
+		if err := i.tokenRepo.Create(ctx, tx, token); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(token.UserID, "Create access token("+token.ID+")"))
+
+		return nil
+	})
+	if err != nil {
+		return model.AccessToken{}, err
+	}
+
+	return token, nil
+}
+
+// Revoke removes a AccessToken of the user. The token can not be used after that.
+func (i *AccessTokenInteractor) Revoke(ctx context.Context, token model.AccessToken) error {
+	return runInTx(ctx, i.txRepo, i.logger, token.UserID, func(tx Transaction) error {
+		saved, err := i.tokenRepo.FindByID(ctx, tx, token.ID)
+		if err != nil {
+			return err
+		}
+		if saved.UserID != token.UserID {
+			return model.NotFoundError{
+				UserID: token.UserID,
+				Err:    nil,
+				ID:     token.ID,
+				Act:    "find access token of user",
+			}
+		}
+
+		if err := i.tokenRepo.Delete(ctx, tx, saved); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(token.UserID, "Revoke access token("+token.ID+")"))
+
+		return nil
+	})
+}
+
+// Authenticate returns AccessToken of a token. It returns NotFoundError if the token is unknown or expired.
+func (i *AccessTokenInteractor) Authenticate(ctx context.Context, token string) (model.AccessToken, error) {
+	if !strings.HasPrefix(token, accessTokenPrefix) {
+		err := model.NotFoundError{
+			UserID: "(No-ID)",
+			Err:    nil,
+			ID:     "(No-ID)",
+			Act:    "validate access token",
+		}
+		logError(i.logger, err)
+		return model.AccessToken{}, err
+	}
+
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	saved, err := i.tokenRepo.FindByHash(ctx, tx, hashToken(token))
+	if err != nil {
+		logError(i.logger, err)
+		return model.AccessToken{}, err
+	}
+	if saved.Expired(time.Now()) {
+		err := model.NotFoundError{
+			UserID: saved.UserID,
+			Err:    nil,
+			ID:     saved.ID,
+			Act:    "check expiry of access token",
+		}
+		logError(i.logger, err)
+		return model.AccessToken{}, err
+	}
+
+	return saved, nil
+}
+
+// hashToken returns a hash of a token saved instead of it.
+// A plain SHA-256 is enough because tokens are random and long, unlike passwords.
+func hashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+type accessTokenKey struct{}
+
+// WithAccessToken returns a context of a request authenticated by AccessToken.
+// Boards which can be accessed in the context are restricted by scopes of the AccessToken.
+func WithAccessToken(ctx context.Context, token model.AccessToken) context.Context {
+	return context.WithValue(ctx, accessTokenKey{}, token)
+}
+
+// accessTokenOf returns AccessToken authenticating a request of ctx.
+func accessTokenOf(ctx context.Context) (model.AccessToken, bool) {
+	token, ok := ctx.Value(accessTokenKey{}).(model.AccessToken)
+	return token, ok
+}
@@ -0,0 +1,298 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/x-color/vue-trello/interface/presenter/logging"
+	"github.com/x-color/vue-trello/interface/pubsub"
+	"github.com/x-color/vue-trello/interface/repository/memory"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+var errActivity = errors.New("failed to record activity")
+
+// failingActivityRepository fails to record Activities, so transactions recording them are rolled back.
+type failingActivityRepository struct {
+	usecase.ActivityRepository
+}
+
+func (failingActivityRepository) Create(ctx context.Context, tx usecase.Transaction, activity model.Activity) error {
+	return errActivity
+}
+
+// interactors includes interactors sharing repositories in memory.
+type interactors struct {
+	dbm       *memory.DBManager
+	bus       *pubsub.Bus
+	board     usecase.BoardInteractor
+	list      usecase.ListInteractor
+	item      usecase.ItemInteractor
+	checklist usecase.ChecklistInteractor
+}
+
+// newInteractors generates interactors on dbm. Activities are recorded by activity if it is not nil.
+func newInteractors(t *testing.T, dbm *memory.DBManager, activity usecase.ActivityRepository) interactors {
+	t.Helper()
+	if activity == nil {
+		activity = &dbm.ActivityDBManager
+	}
+	logger, err := logging.NewLogger(ioutil.Discard, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus := pubsub.NewBus()
+
+	board, err := usecase.NewBoardInteractor(
+		&dbm.TransactionManager,
+		&dbm.BoardDBManager,
+		&dbm.ListDBManager,
+		&dbm.ItemDBManager,
+		&dbm.MemberDBManager,
+		&dbm.ChecklistDBManager,
+		&dbm.CheckItemDBManager,
+		&dbm.CommentDBManager,
+		activity,
+		&dbm.TagDBManager,
+		&dbm.WebhookDBManager,
+		&dbm.DeliveryDBManager,
+		bus,
+		&logger,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := usecase.NewListInteractor(
+		&dbm.TransactionManager,
+		&dbm.ItemDBManager,
+		&dbm.ListDBManager,
+		&dbm.BoardDBManager,
+		&dbm.MemberDBManager,
+		&dbm.ChecklistDBManager,
+		&dbm.CheckItemDBManager,
+		&dbm.CommentDBManager,
+		activity,
+		&dbm.TagDBManager,
+		bus,
+		&logger,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	item, err := usecase.NewItemInteractor(
+		&dbm.TransactionManager,
+		&dbm.ItemDBManager,
+		&dbm.ListDBManager,
+		&dbm.TagDBManager,
+		&dbm.MemberDBManager,
+		&dbm.ChecklistDBManager,
+		&dbm.CheckItemDBManager,
+		&dbm.CommentDBManager,
+		activity,
+		bus,
+		&logger,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checklist, err := usecase.NewChecklistInteractor(
+		&dbm.TransactionManager,
+		&dbm.ChecklistDBManager,
+		&dbm.CheckItemDBManager,
+		&dbm.ItemDBManager,
+		&dbm.ListDBManager,
+		&dbm.MemberDBManager,
+		activity,
+		bus,
+		&logger,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return interactors{dbm: dbm, bus: bus, board: board, list: list, item: item, checklist: checklist}
+}
+
+// createList creates a Board of a user and a List in it.
+func createList(t *testing.T, i interactors, userID string) model.List {
+	t.Helper()
+	ctx := context.Background()
+	board, err := i.board.Create(ctx, model.Board{UserID: userID, Title: "board", Color: model.RED})
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := i.list.Create(ctx, model.List{BoardID: board.ID, UserID: userID, Title: "list"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return list
+}
+
+func findItems(t *testing.T, dbm *memory.DBManager, listID string) model.Items {
+	t.Helper()
+	ctx := context.Background()
+	tx := dbm.TransactionManager.BeginTransaction(ctx, false)
+	items, err := dbm.ItemDBManager.Find(ctx, tx, usecase.ItemFilter{
+		ListIDs: []string{listID},
+		Sort:    usecase.Sort{Key: usecase.SortByRank},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return items
+}
+
+func TestRollbackCreate(t *testing.T) {
+	ctx := context.Background()
+	dbm := memory.NewDBManager()
+	i := newInteractors(t, &dbm, failingActivityRepository{})
+
+	// A Board and its owner are not kept if recording an Activity fails
+	_, err := i.board.Create(ctx, model.Board{UserID: "u1", Title: "board", Color: model.RED})
+	if !errors.Is(err, errActivity) {
+		t.Fatalf("Create() error = %v, want %v", err, errActivity)
+	}
+
+	tx := dbm.TransactionManager.BeginTransaction(ctx, false)
+	boards, err := dbm.BoardDBManager.Find(ctx, tx, usecase.BoardFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	members, err := dbm.MemberDBManager.Find(ctx, tx, usecase.MemberFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(boards) != 0 || len(members) != 0 {
+		t.Errorf("got %d boards and %d members after rollback, want none", len(boards), len(members))
+	}
+}
+
+func TestRollbackDelete(t *testing.T) {
+	ctx := context.Background()
+	dbm := memory.NewDBManager()
+	i := newInteractors(t, &dbm, nil)
+	failing := newInteractors(t, &dbm, failingActivityRepository{})
+
+	list := createList(t, i, "u1")
+	for _, title := range []string{"first", "second"} {
+		if _, err := i.item.Create(ctx, model.Item{ListID: list.ID, UserID: "u1", Title: title}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Items deleted with a List are restored if recording an Activity fails
+	err := failing.list.Delete(ctx, model.List{ID: list.ID, UserID: "u1"})
+	if !errors.Is(err, errActivity) {
+		t.Fatalf("Delete() error = %v, want %v", err, errActivity)
+	}
+
+	tx := dbm.TransactionManager.BeginTransaction(ctx, false)
+	if _, err := dbm.ListDBManager.FindByID(ctx, tx, list.ID); err != nil {
+		t.Errorf("FindByID() error = %v after rollback", err)
+	}
+	items := findItems(t, &dbm, list.ID)
+	if len(items) != 2 || items[0].Title != "first" || items[1].Title != "second" {
+		t.Errorf("got items %+v after rollback, want first and second", items)
+	}
+}
+
+func TestConcurrentCreate(t *testing.T) {
+	ctx := context.Background()
+	dbm := memory.NewDBManager()
+	i := newInteractors(t, &dbm, nil)
+	list := createList(t, i, "u1")
+
+	// Items created at the same time get distinct ranks at the end of a List
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for j := 0; j < n; j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := i.item.Create(ctx, model.Item{ListID: list.ID, UserID: "u1", Title: "item"})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	items := findItems(t, &dbm, list.ID)
+	if len(items) != n {
+		t.Fatalf("got %d items, want %d", len(items), n)
+	}
+	for j := 1; j < len(items); j++ {
+		if items[j-1].Rank >= items[j].Rank {
+			t.Errorf("rank %q of item %d is not before rank %q of item %d", items[j-1].Rank, j-1, items[j].Rank, j)
+		}
+	}
+
+	tx := dbm.TransactionManager.BeginTransaction(ctx, false)
+	activities, err := dbm.ActivityDBManager.Find(ctx, tx, usecase.ActivityFilter{BoardIDs: []string{list.BoardID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Activities of the Board, the List and all Items
+	if len(activities) != n+2 {
+		t.Errorf("got %d activities, want %d", len(activities), n+2)
+	}
+}
+
+func TestConcurrentUpdate(t *testing.T) {
+	ctx := context.Background()
+	dbm := memory.NewDBManager()
+	i := newInteractors(t, &dbm, nil)
+	list := createList(t, i, "u1")
+	item, err := i.item.Create(ctx, model.Item{ListID: list.ID, UserID: "u1", Title: "item"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only one of updates based on the same version succeeds
+	const n = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for j := 0; j < n; j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			updated := item
+			updated.Title = "updated"
+			_, err := i.item.Update(ctx, updated)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, model.PreconditionFailedError{}):
+			t.Errorf("Update() error = %v, want PreconditionFailedError", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("%d updates succeeded, want 1", succeeded)
+	}
+
+	tx := dbm.TransactionManager.BeginTransaction(ctx, false)
+	got, err := dbm.ItemDBManager.FindByID(ctx, tx, item.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != item.Version+1 {
+		t.Errorf("version = %d, want %d", got.Version, item.Version+1)
+	}
+}
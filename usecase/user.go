@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 
 	"github.com/google/uuid"
@@ -10,52 +11,55 @@ import (
 
 // UserUsecase is interface. It defines to control a User authentication.
 type UserUsecase interface {
-	SignUp(user model.User) (model.User, error)
-	SignIn(user model.User) (model.User, error)
+	SignUp(ctx context.Context, user model.User) (model.User, error)
+	SignIn(ctx context.Context, user model.User) (model.User, error)
+	ChangeName(ctx context.Context, user model.User) (model.User, error)
+	ChangePassword(ctx context.Context, user model.User, password string) error
+	Delete(ctx context.Context, user model.User) error
 }
 
-// UserInteractor includes repogitories and a logger.
+// UserInteractor includes repogitories, a bus to publish changes and a logger.
+// It deletes Boards of a deleted account by boards in the same transaction.
 type UserInteractor struct {
-	txRepo   TransactionRepository
-	userRepo UserRepository
-	logger   Logger
+	txRepo      TransactionRepository
+	userRepo    UserRepository
+	sessionRepo SessionRepository
+	tokenRepo   AccessTokenRepository
+	memberRepo  MemberRepository
+	tagRepo     TagRepository
+	boards      *BoardInteractor
+	bus         EventBus
+	logger      Logger
 }
 
 // NewUserInteractor generates new interactor for a User.
 func NewUserInteractor(
 	txRepo TransactionRepository,
 	userRepo UserRepository,
+	sessionRepo SessionRepository,
+	tokenRepo AccessTokenRepository,
+	memberRepo MemberRepository,
+	tagRepo TagRepository,
+	boards *BoardInteractor,
+	bus EventBus,
 	logger Logger,
 ) (UserInteractor, error) {
 	i := UserInteractor{
-		txRepo:   txRepo,
-		userRepo: userRepo,
-		logger:   logger,
+		txRepo:      txRepo,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		tokenRepo:   tokenRepo,
+		memberRepo:  memberRepo,
+		tagRepo:     tagRepo,
+		boards:      boards,
+		bus:         bus,
+		logger:      logger,
 	}
 	return i, nil
 }
 
 // SignUp registers new User to repository.
-func (i *UserInteractor) SignUp(user model.User) (model.User, error) {
-	tx := i.txRepo.BeginTransaction(false)
-
-	u, err := i.userRepo.Find(tx, map[string]interface{}{
-		"Name": user.Name,
-	})
-	if err != nil && !errors.Is(err, model.NotFoundError{}) {
-		logError(i.logger, err)
-		return model.User{}, err
-	}
-	if user.Name == u.Name {
-		i.logger.Info(formatLogMsg(user.ID, "New user name conflicts. '"+user.Name+"' already exists"))
-		return model.User{}, model.ConflictError{
-			UserID: "(No-ID)",
-			Err:    nil,
-			ID:     user.Name,
-			Act:    "validate name",
-		}
-	}
-
+func (i *UserInteractor) SignUp(ctx context.Context, user model.User) (model.User, error) {
 	user.ID = uuid.New().String()
 	p, err := hashPassword(user.Password)
 	if err != nil {
@@ -68,19 +72,43 @@ func (i *UserInteractor) SignUp(user model.User) (model.User, error) {
 		}
 	}
 	user.Password = p
-	if err := i.userRepo.Create(tx, user); err != nil {
-		logError(i.logger, err)
+
+	err = runInTx(ctx, i.txRepo, i.logger, user.ID, func(tx Transaction) error {
+		u, err := i.userRepo.Find(ctx, tx, UserFilter{
+			Names: []string{user.Name},
+		})
+		if err != nil && !errors.Is(err, model.NotFoundError{}) {
+			return err
+		}
+		if user.Name == u.Name {
+			i.logger.Info(formatLogMsg(user.ID, "New user name conflicts. '"+user.Name+"' already exists"))
+			return model.ConflictError{
+				UserID: "(No-ID)",
+				Err:    nil,
+				ID:     user.Name,
+				Act:    "validate name",
+			}
+		}
+
+		if err := i.userRepo.Create(ctx, tx, user); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(user.ID, "Create user("+user.ID+")"))
+
+		return nil
+	})
+	if err != nil {
 		return model.User{}, err
 	}
-	i.logger.Info(formatLogMsg(user.ID, "Create user("+user.ID+")"))
+
 	return user, nil
 }
 
 // SignIn returns User data if a user succeds at authentication.
-func (i *UserInteractor) SignIn(user model.User) (model.User, error) {
-	tx := i.txRepo.BeginTransaction(false)
-	u, err := i.userRepo.Find(tx, map[string]interface{}{
-		"Name": user.Name,
+func (i *UserInteractor) SignIn(ctx context.Context, user model.User) (model.User, error) {
+	tx := i.txRepo.BeginTransaction(ctx, false)
+	u, err := i.userRepo.Find(ctx, tx, UserFilter{
+		Names: []string{user.Name},
 	})
 	if err != nil {
 		logError(i.logger, err)
@@ -101,6 +129,227 @@ func (i *UserInteractor) SignIn(user model.User) (model.User, error) {
 	return u, nil
 }
 
+// ChangeName changes a name of a User to user.Name and returns new User.
+// It fails if another User has the name.
+func (i *UserInteractor) ChangeName(ctx context.Context, user model.User) (model.User, error) {
+	if user.Name == "" {
+		err := model.InvalidContentError{
+			UserID: user.ID,
+			Err:    nil,
+			ID:     user.ID,
+			Act:    "validate name",
+		}
+		logError(i.logger, err)
+		return model.User{}, err
+	}
+
+	var changed model.User
+	err := runInTx(ctx, i.txRepo, i.logger, user.ID, func(tx Transaction) error {
+		u, err := i.userRepo.Find(ctx, tx, UserFilter{
+			Names: []string{user.Name},
+		})
+		if err != nil && !errors.Is(err, model.NotFoundError{}) {
+			return err
+		}
+		if err == nil && u.ID != user.ID {
+			i.logger.Info(formatLogMsg(user.ID, "New user name conflicts. '"+user.Name+"' already exists"))
+			return model.ConflictError{
+				UserID: user.ID,
+				Err:    nil,
+				ID:     user.Name,
+				Act:    "validate name",
+			}
+		}
+
+		old, err := i.userRepo.Find(ctx, tx, UserFilter{
+			IDs: []string{user.ID},
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := i.userRepo.Update(ctx, tx, old, map[string]interface{}{
+			"Name": user.Name,
+		}); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(user.ID, "Change name of user("+user.ID+")"))
+
+		changed = old
+		changed.Name = user.Name
+		return nil
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return changed, nil
+}
+
+// ChangePassword changes a password of a User if user.Password is the current one.
+// All Sessions of the User are revoked, so that other devices must sign in with the new password.
+func (i *UserInteractor) ChangePassword(ctx context.Context, user model.User, password string) error {
+	h, err := hashPassword(password)
+	if err != nil || password == "" {
+		err := model.InvalidContentError{
+			UserID: user.ID,
+			Err:    err,
+			ID:     user.ID,
+			Act:    "hash password",
+		}
+		logError(i.logger, err)
+		return err
+	}
+
+	return runInTx(ctx, i.txRepo, i.logger, user.ID, func(tx Transaction) error {
+		u, err := i.verifyPassword(ctx, tx, user)
+		if err != nil {
+			return err
+		}
+
+		if err := i.userRepo.Update(ctx, tx, u, map[string]interface{}{
+			"Password": h,
+		}); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(user.ID, "Change password of user("+user.ID+")"))
+
+		if err := i.revokeSessions(ctx, tx, u); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(user.ID, "Revoke sessions of user("+user.ID+")"))
+
+		return nil
+	})
+}
+
+// Delete removes a User if user.Password is the current one. Boards owned by the User are deleted with
+// Lists and Items in them, and the User leaves other Boards. Tags, AccessTokens and Sessions of the User
+// are also deleted. All of them are deleted in one transaction.
+func (i *UserInteractor) Delete(ctx context.Context, user model.User) error {
+	var events model.Activities
+	err := runInTx(ctx, i.txRepo, i.logger, user.ID, func(tx Transaction) error {
+		u, err := i.verifyPassword(ctx, tx, user)
+		if err != nil {
+			return err
+		}
+
+		members, err := i.memberRepo.Find(ctx, tx, MemberFilter{
+			UserIDs: []string{u.ID},
+		})
+		if err != nil {
+			return err
+		}
+		for _, member := range members {
+			if member.Role != model.OWNER {
+				if err := i.memberRepo.Delete(ctx, tx, member); err != nil {
+					return err
+				}
+				i.logger.Info(formatLogMsg(u.ID, "Remove user("+u.ID+") from board("+member.BoardID+")"))
+				continue
+			}
+
+			board, err := i.boards.boardRepo.FindByID(ctx, tx, member.BoardID)
+			if err != nil {
+				return err
+			}
+			recorded, err := i.boards.deleteBoard(ctx, tx, board)
+			if err != nil {
+				return err
+			}
+			events = append(events, recorded)
+		}
+
+		tags, err := i.tagRepo.Find(ctx, tx, TagFilter{
+			UserIDs: []string{u.ID},
+		})
+		if err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			if tag.BoardID != "" {
+				continue
+			}
+			recorded, err := detachTag(ctx, tx, i.boards.itemRepo, i.boards.listRepo, i.boards.activityRepo, tag, u.ID)
+			if err != nil {
+				return err
+			}
+			events = append(events, recorded...)
+			if err := i.tagRepo.Delete(ctx, tx, tag); err != nil {
+				return err
+			}
+		}
+		i.logger.Info(formatLogMsg(u.ID, "Delete tags of user("+u.ID+")"))
+
+		tokens, err := i.tokenRepo.Find(ctx, tx, AccessTokenFilter{
+			UserIDs: []string{u.ID},
+		})
+		if err != nil {
+			return err
+		}
+		for _, token := range tokens {
+			if err := i.tokenRepo.Delete(ctx, tx, token); err != nil {
+				return err
+			}
+		}
+		i.logger.Info(formatLogMsg(u.ID, "Delete access tokens of user("+u.ID+")"))
+
+		if err := i.revokeSessions(ctx, tx, u); err != nil {
+			return err
+		}
+
+		if err := i.userRepo.Delete(ctx, tx, u); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(u.ID, "Delete user("+u.ID+")"))
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	i.bus.Publish(events...)
+	return nil
+}
+
+// verifyPassword returns a User had user.ID if user.Password is the password of it.
+func (i *UserInteractor) verifyPassword(ctx context.Context, tx Transaction, user model.User) (model.User, error) {
+	u, err := i.userRepo.Find(ctx, tx, UserFilter{
+		IDs: []string{user.ID},
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if err := comparePassword(user.Password, u.Password); err != nil {
+		i.logger.Info(formatLogMsg(u.ID, "Invalid password of user("+u.ID+")"))
+		return model.User{}, model.ForbiddenError{
+			UserID: u.ID,
+			Err:    err,
+			ID:     u.ID,
+			Act:    "validate password",
+		}
+	}
+	return u, nil
+}
+
+// revokeSessions removes all Sessions of a User.
+func (i *UserInteractor) revokeSessions(ctx context.Context, tx Transaction, user model.User) error {
+	sessions, err := i.sessionRepo.Find(ctx, tx, SessionFilter{
+		UserIDs: []string{user.ID},
+	})
+	if err != nil {
+		return err
+	}
+	for _, s := range sessions {
+		if err := i.sessionRepo.Delete(ctx, tx, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func hashPassword(password string) (string, error) {
 	if len(password) > 72 {
 		return "", errors.New("password length is too long")
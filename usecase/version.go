@@ -0,0 +1,18 @@
+package usecase
+
+import "github.com/x-color/vue-trello/model"
+
+// checkVersion returns PreconditionFailedError including current content if a user expects
+// other version of the content. expected is zero if the user does not expect any version.
+func checkVersion(expected, current int, content interface{}, userID, id, act string) error {
+	if expected == 0 || expected == current {
+		return nil
+	}
+	return model.PreconditionFailedError{
+		UserID:  userID,
+		Err:     nil,
+		ID:      id,
+		Act:     act,
+		Current: content,
+	}
+}
@@ -0,0 +1,521 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/x-color/vue-trello/model"
+)
+
+// Settings of deliveries. A Delivery failed is retried after backoff, which doubles on each attempt
+// up to webhookMaxBackoff, and fails at last after webhookMaxAttempts attempts. A Delivery being sent
+// is not attempted by others for webhookLease. Up to webhookWorkers Webhooks are sent to at once.
+const (
+	webhookMaxAttempts  = 8
+	webhookMaxBackoff   = time.Hour
+	webhookSendTimeout  = 10 * time.Second
+	webhookLease        = time.Minute
+	webhookPollInterval = time.Second
+	webhookBatchSize    = 20
+	webhookWorkers      = 4
+)
+
+// WebhookUsecase is interface. It defines to control Webhooks in a Board.
+type WebhookUsecase interface {
+	GetWebhooks(ctx context.Context, board model.Board) (model.Webhooks, error)
+	Create(ctx context.Context, webhook model.Webhook) (model.Webhook, error)
+	Delete(ctx context.Context, webhook model.Webhook) error
+	GetDeliveries(ctx context.Context, webhook model.Webhook, offset, limit int) (model.Deliveries, bool, error)
+}
+
+// WebhookInteractor includes repogitories, a sender of Activities and a logger.
+// It also delivers Activities published to Webhooks in background.
+type WebhookInteractor struct {
+	txRepo       TransactionRepository
+	webhookRepo  WebhookRepository
+	deliveryRepo DeliveryRepository
+	activityRepo ActivityRepository
+	memberRepo   MemberRepository
+	userRepo     UserRepository
+	sender       WebhookSender
+	backoff      time.Duration
+	wake         chan struct{}
+	logger       Logger
+}
+
+// NewWebhookInteractor generates new interactor for a Webhook.
+// backoff is a delay before the first retry of a Delivery.
+func NewWebhookInteractor(
+	txRepo TransactionRepository,
+	webhookRepo WebhookRepository,
+	deliveryRepo DeliveryRepository,
+	activityRepo ActivityRepository,
+	memberRepo MemberRepository,
+	userRepo UserRepository,
+	sender WebhookSender,
+	backoff time.Duration,
+	logger Logger,
+) (WebhookInteractor, error) {
+	i := WebhookInteractor{
+		txRepo:       txRepo,
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		activityRepo: activityRepo,
+		memberRepo:   memberRepo,
+		userRepo:     userRepo,
+		sender:       sender,
+		backoff:      backoff,
+		wake:         make(chan struct{}, 1),
+		logger:       logger,
+	}
+	return i, nil
+}
+
+// GetWebhooks returns Webhooks in a Board without their secrets. Only an owner of the Board can get them.
+func (i *WebhookInteractor) GetWebhooks(ctx context.Context, board model.Board) (model.Webhooks, error) {
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	if _, err := authorize(ctx, tx, i.memberRepo, board.ID, board.UserID, model.OWNER); err != nil {
+		logError(i.logger, err)
+		return model.Webhooks{}, err
+	}
+
+	webhooks, err := i.webhookRepo.Find(ctx, tx, WebhookFilter{
+		BoardIDs: []string{board.ID},
+	})
+	if err != nil {
+		logError(i.logger, err)
+		return model.Webhooks{}, err
+	}
+	for j := range webhooks {
+		webhooks[j].Secret = ""
+	}
+
+	i.logger.Info(formatLogMsg(board.UserID, "Get webhooks in board("+board.ID+")"))
+	return webhooks, nil
+}
+
+// Create registers new Webhook and returns it. A secret is generated if it is not given.
+func (i *WebhookInteractor) Create(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	webhook.ID = uuid.New().String()
+	if err := validateWebhook(webhook); err != nil {
+		logError(i.logger, err)
+		return model.Webhook{}, err
+	}
+	// Requests to the URL are also refused when they are sent, because its host can be resolved to another address.
+	if err := i.sender.Validate(ctx, webhook.URL); err != nil {
+		err := model.InvalidContentError{
+			UserID: webhook.UserID,
+			Err:    err,
+			ID:     webhook.ID,
+			Act:    "validate address of webhook",
+		}
+		logError(i.logger, err)
+		return model.Webhook{}, err
+	}
+	if webhook.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			err = model.ServerError{
+				UserID: webhook.UserID,
+				Err:    err,
+				ID:     webhook.ID,
+				Act:    "generate secret of webhook",
+			}
+			logError(i.logger, err)
+			return model.Webhook{}, err
+		}
+		webhook.Secret = secret
+	}
+	if webhook.Events == nil {
+		webhook.Events = []string{}
+	}
+
+	err := runInTx(ctx, i.txRepo, i.logger, webhook.UserID, func(tx Transaction) error {
+		if _, err := authorize(ctx, tx, i.memberRepo, webhook.BoardID, webhook.UserID, model.OWNER); err != nil {
+			return err
+		}
+
+		webhook.CreatedAt = time.Now()
+		if err := i.webhookRepo.Create(ctx, tx, webhook); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(webhook.UserID, "Create webhook("+webhook.ID+") in board("+webhook.BoardID+")"))
+
+		return nil
+	})
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	return webhook, nil
+}
+
+// Delete removes a Webhook with its Deliveries.
+func (i *WebhookInteractor) Delete(ctx context.Context, webhook model.Webhook) error {
+	return runInTx(ctx, i.txRepo, i.logger, webhook.UserID, func(tx Transaction) error {
+		saved, err := i.findWebhook(ctx, tx, webhook)
+		if err != nil {
+			return err
+		}
+
+		if err := deleteWebhook(ctx, tx, i.webhookRepo, i.deliveryRepo, saved); err != nil {
+			return err
+		}
+		i.logger.Info(formatLogMsg(webhook.UserID, "Delete webhook("+webhook.ID+")"))
+
+		return nil
+	})
+}
+
+// GetDeliveries returns a page of Deliveries of a Webhook from newest to oldest.
+// The second returned value reports whether older Deliveries remain.
+func (i *WebhookInteractor) GetDeliveries(ctx context.Context, webhook model.Webhook, offset, limit int) (model.Deliveries, bool, error) {
+	if offset < 0 || limit <= 0 {
+		err := model.InvalidContentError{
+			UserID: webhook.UserID,
+			Err:    nil,
+			ID:     webhook.ID,
+			Act:    "validate page of deliveries",
+		}
+		logError(i.logger, err)
+		return model.Deliveries{}, false, err
+	}
+
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	if _, err := i.findWebhook(ctx, tx, webhook); err != nil {
+		logError(i.logger, err)
+		return model.Deliveries{}, false, err
+	}
+
+	// Get one more delivery to know whether a next page exists.
+	deliveries, err := i.deliveryRepo.Find(ctx, tx, DeliveryFilter{
+		WebhookIDs: []string{webhook.ID},
+		Sort:       Sort{Key: SortByCreatedAt, Desc: true},
+		Page:       Page{Offset: offset, Limit: limit + 1},
+	})
+	if err != nil {
+		logError(i.logger, err)
+		return model.Deliveries{}, false, err
+	}
+	hasNext := len(deliveries) > limit
+	if hasNext {
+		deliveries = deliveries[:limit]
+	}
+	i.logger.Info(formatLogMsg(webhook.UserID, "Get deliveries of webhook("+webhook.ID+")"))
+
+	return deliveries, hasNext, nil
+}
+
+// deleteWebhook removes a Webhook and its Deliveries.
+func deleteWebhook(ctx context.Context, tx Transaction, webhookRepo WebhookRepository, deliveryRepo DeliveryRepository, webhook model.Webhook) error {
+	deliveries, err := deliveryRepo.Find(ctx, tx, DeliveryFilter{
+		WebhookIDs: []string{webhook.ID},
+	})
+	if err != nil {
+		return err
+	}
+	for _, d := range deliveries {
+		if err := deliveryRepo.Delete(ctx, tx, d); err != nil {
+			return err
+		}
+	}
+	return webhookRepo.Delete(ctx, tx, webhook)
+}
+
+// findWebhook returns a saved Webhook in a Board of webhook if the user of webhook owns the Board.
+func (i *WebhookInteractor) findWebhook(ctx context.Context, tx Transaction, webhook model.Webhook) (model.Webhook, error) {
+	if _, err := authorize(ctx, tx, i.memberRepo, webhook.BoardID, webhook.UserID, model.OWNER); err != nil {
+		return model.Webhook{}, err
+	}
+
+	saved, err := i.webhookRepo.FindByID(ctx, tx, webhook.ID)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	if saved.BoardID != webhook.BoardID {
+		return model.Webhook{}, model.NotFoundError{
+			UserID: webhook.UserID,
+			Err:    nil,
+			ID:     webhook.ID,
+			Act:    "find webhook in board(" + webhook.BoardID + ")",
+		}
+	}
+	return saved, nil
+}
+
+// Wake wakes Run up to deliver Deliveries of published Activities.
+// The Deliveries have been enqueued with the Activities by DeliveringActivityRepository.
+func (i *WebhookInteractor) Wake(activities ...model.Activity) {
+	select {
+	case i.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers pending Deliveries until ctx is done. A Delivery is claimed before it is sent, so that
+// other servers sharing the DB do not send it at the same time. Deliveries left by a stopped server are
+// delivered after their lease, so an Activity can be sent more than once.
+func (i *WebhookInteractor) Run(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		i.deliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-i.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverDue delivers due Deliveries. Deliveries to a Webhook are sent in order by a worker,
+// and workers for different Webhooks send them concurrently.
+func (i *WebhookInteractor) deliverDue(ctx context.Context) {
+	tx := i.txRepo.BeginTransaction(ctx, false)
+
+	deliveries, err := i.deliveryRepo.Find(ctx, tx, DeliveryFilter{
+		Statuses: []model.DeliveryStatus{model.PENDING},
+		DueUntil: time.Now(),
+		Sort:     Sort{Key: SortByCreatedAt},
+		Page:     Page{Limit: webhookBatchSize},
+	})
+	if err != nil {
+		logError(i.logger, err)
+		return
+	}
+
+	webhookIDs := []string{}
+	queues := map[string]model.Deliveries{}
+	for _, d := range deliveries {
+		if _, ok := queues[d.WebhookID]; !ok {
+			webhookIDs = append(webhookIDs, d.WebhookID)
+		}
+		queues[d.WebhookID] = append(queues[d.WebhookID], d)
+	}
+
+	workers := make(chan struct{}, webhookWorkers)
+	var wg sync.WaitGroup
+	for _, id := range webhookIDs {
+		workers <- struct{}{}
+		wg.Add(1)
+		go func(queue model.Deliveries) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			tx := i.txRepo.BeginTransaction(ctx, false)
+			for _, d := range queue {
+				if err := i.deliver(ctx, tx, d); err != nil {
+					logError(i.logger, err)
+				}
+			}
+		}(queues[id])
+	}
+	wg.Wait()
+}
+
+// deliver attempts a Delivery and saves its result. The Delivery is skipped if another worker has claimed it.
+func (i *WebhookInteractor) deliver(ctx context.Context, tx Transaction, delivery model.Delivery) error {
+	err := i.deliveryRepo.Claim(ctx, tx, delivery, time.Now().Add(webhookLease))
+	if errors.Is(err, model.PreconditionFailedError{}) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	delivery.Attempts++
+
+	webhook, err := i.webhookRepo.FindByID(ctx, tx, delivery.WebhookID)
+	if errors.Is(err, model.NotFoundError{}) {
+		delivery.Status = model.FAILED
+		delivery.Error = "webhook is deleted"
+		return i.saveDelivery(ctx, tx, delivery)
+	}
+	if err != nil {
+		return err
+	}
+
+	activities, err := i.activityRepo.Find(ctx, tx, ActivityFilter{
+		IDs: []string{delivery.ActivityID},
+	})
+	if err != nil {
+		return err
+	}
+	if len(activities) == 0 {
+		delivery.Status = model.FAILED
+		delivery.Error = "activity is not found"
+		return i.saveDelivery(ctx, tx, delivery)
+	}
+	if err := fillUserNames(ctx, tx, i.userRepo, activities, map[string]string{}); err != nil {
+		return err
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, webhookSendTimeout)
+	status, err := i.sender.Send(sendCtx, webhook, delivery, activities[0])
+	cancel()
+
+	delivery.ResponseStatus = status
+	delivery.Error = ""
+	switch {
+	case err != nil:
+		delivery.Error = err.Error()
+	case status < 200 || status >= 300:
+		delivery.Error = "unexpected status " + strconv.Itoa(status)
+	}
+
+	if delivery.Error == "" {
+		delivery.Status = model.SUCCEEDED
+		i.logger.Info(formatLogMsg(webhook.UserID, "Deliver delivery("+delivery.ID+") to webhook("+webhook.ID+")"))
+	} else if delivery.Attempts >= webhookMaxAttempts {
+		delivery.Status = model.FAILED
+		i.logger.Info(formatLogMsg(webhook.UserID, "Give up delivery("+delivery.ID+") to webhook("+webhook.ID+"): "+delivery.Error))
+	} else {
+		delivery.NextAttemptAt = time.Now().Add(i.backoffOf(delivery.Attempts))
+		i.logger.Info(formatLogMsg(webhook.UserID, "Retry delivery("+delivery.ID+") to webhook("+webhook.ID+") later: "+delivery.Error))
+	}
+	return i.saveDelivery(ctx, tx, delivery)
+}
+
+func (i *WebhookInteractor) saveDelivery(ctx context.Context, tx Transaction, delivery model.Delivery) error {
+	return i.deliveryRepo.Update(ctx, tx, delivery, map[string]interface{}{
+		"Status":         delivery.Status,
+		"Attempts":       delivery.Attempts,
+		"ResponseStatus": delivery.ResponseStatus,
+		"Error":          delivery.Error,
+		"NextAttemptAt":  delivery.NextAttemptAt,
+	})
+}
+
+// DeliveringActivityRepository is an ActivityRepository which enqueues Deliveries of an Activity
+// to Webhooks accepting it whenever the Activity is recorded. The Deliveries are saved in the transaction
+// recording the Activity, so they are not lost even if the server stops before the Activity is published.
+type DeliveringActivityRepository struct {
+	ActivityRepository
+	webhookRepo  WebhookRepository
+	deliveryRepo DeliveryRepository
+	logger       Logger
+}
+
+// NewDeliveringActivityRepository returns a DeliveringActivityRepository recording Activities by activityRepo.
+func NewDeliveringActivityRepository(
+	activityRepo ActivityRepository,
+	webhookRepo WebhookRepository,
+	deliveryRepo DeliveryRepository,
+	logger Logger,
+) *DeliveringActivityRepository {
+	return &DeliveringActivityRepository{
+		ActivityRepository: activityRepo,
+		webhookRepo:        webhookRepo,
+		deliveryRepo:       deliveryRepo,
+		logger:             logger,
+	}
+}
+
+// Create records an Activity and enqueues its Deliveries.
+func (r *DeliveringActivityRepository) Create(ctx context.Context, tx Transaction, activity model.Activity) error {
+	if err := r.ActivityRepository.Create(ctx, tx, activity); err != nil {
+		return err
+	}
+
+	webhooks, err := r.webhookRepo.Find(ctx, tx, WebhookFilter{
+		BoardIDs: []string{activity.BoardID},
+	})
+	if err != nil {
+		return err
+	}
+	for _, w := range webhooks {
+		if !w.Accepts(activity) {
+			continue
+		}
+		delivery := model.Delivery{
+			ID:            uuid.New().String(),
+			WebhookID:     w.ID,
+			ActivityID:    activity.ID,
+			Event:         activity.Event(),
+			Status:        model.PENDING,
+			NextAttemptAt: activity.CreatedAt,
+			CreatedAt:     activity.CreatedAt,
+			UpdatedAt:     activity.CreatedAt,
+		}
+		if err := r.deliveryRepo.Create(ctx, tx, delivery); err != nil {
+			return err
+		}
+		r.logger.Info(formatLogMsg(w.UserID, "Enqueue delivery("+delivery.ID+") of activity("+activity.ID+") to webhook("+w.ID+")"))
+	}
+	return nil
+}
+
+// backoffOf returns a delay before a retry after attempts.
+func (i *WebhookInteractor) backoffOf(attempts int) time.Duration {
+	d := i.backoff
+	for j := 1; j < attempts && d < webhookMaxBackoff; j++ {
+		d *= 2
+	}
+	if d > webhookMaxBackoff {
+		d = webhookMaxBackoff
+	}
+	return d
+}
+
+func validateWebhook(webhook model.Webhook) error {
+	err := model.InvalidContentError{
+		UserID: webhook.UserID,
+		Err:    nil,
+		ID:     webhook.ID,
+		Act:    "validate contents in webhook",
+	}
+	if webhook.ID == "" || webhook.BoardID == "" || webhook.UserID == "" {
+		return err
+	}
+
+	u, e := url.Parse(webhook.URL)
+	if e != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		err.Act = "validate url of webhook"
+		return err
+	}
+
+	for _, event := range webhook.Events {
+		if !validEvent(event) {
+			err.Act = "validate event(" + event + ") of webhook"
+			return err
+		}
+	}
+	return nil
+}
+
+// validEvent checks that event is a type of Activity as "<target>.<action>".
+func validEvent(event string) bool {
+	s := strings.SplitN(event, ".", 2)
+	if len(s) != 2 {
+		return false
+	}
+	targetOK, actionOK := false, false
+	for _, t := range model.TARGETS {
+		targetOK = targetOK || s[0] == string(t)
+	}
+	for _, a := range model.ACTIONS {
+		actionOK = actionOK || s[1] == string(a)
+	}
+	return targetOK && actionOK
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
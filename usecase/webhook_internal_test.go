@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffOf(t *testing.T) {
+	i := WebhookInteractor{backoff: 10 * time.Second}
+
+	// Backoff doubles after each attempt up to an hour
+	want := []time.Duration{
+		10 * time.Second,
+		20 * time.Second,
+		40 * time.Second,
+		80 * time.Second,
+		160 * time.Second,
+		320 * time.Second,
+		640 * time.Second,
+		1280 * time.Second,
+		2560 * time.Second,
+		time.Hour,
+		time.Hour,
+	}
+	for j, w := range want {
+		if got := i.backoffOf(j + 1); got != w {
+			t.Errorf("backoffOf(%d) = %v, want %v", j+1, got, w)
+		}
+	}
+}
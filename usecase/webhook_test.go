@@ -0,0 +1,231 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/x-color/vue-trello/interface/presenter/logging"
+	"github.com/x-color/vue-trello/interface/repository/memory"
+	"github.com/x-color/vue-trello/model"
+	"github.com/x-color/vue-trello/usecase"
+)
+
+// funcSender sends Activities to Webhooks by a function.
+type funcSender func(ctx context.Context, webhook model.Webhook, delivery model.Delivery) (int, error)
+
+func (f funcSender) Send(ctx context.Context, webhook model.Webhook, delivery model.Delivery, activity model.Activity) (int, error) {
+	return f(ctx, webhook, delivery)
+}
+
+func (f funcSender) Validate(ctx context.Context, url string) error {
+	return nil
+}
+
+func newWebhookInteractor(t *testing.T, dbm *memory.DBManager, sender usecase.WebhookSender, backoff time.Duration) usecase.WebhookInteractor {
+	t.Helper()
+	logger, err := logging.NewLogger(ioutil.Discard, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i, err := usecase.NewWebhookInteractor(
+		&dbm.TransactionManager,
+		&dbm.WebhookDBManager,
+		&dbm.DeliveryDBManager,
+		&dbm.ActivityDBManager,
+		&dbm.MemberDBManager,
+		&dbm.UserDBManager,
+		sender,
+		backoff,
+		&logger,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return i
+}
+
+// enqueue saves Webhooks having webhookIDs and n pending Deliveries to each of them.
+func enqueue(t *testing.T, dbm *memory.DBManager, n int, webhookIDs ...string) {
+	t.Helper()
+	ctx := context.Background()
+	tx := dbm.TransactionManager.BeginTransaction(ctx, false)
+
+	for _, w := range webhookIDs {
+		webhook := model.Webhook{ID: w, BoardID: "b1", UserID: "u1", URL: "http://example.com/" + w, Events: []string{}}
+		if err := dbm.WebhookDBManager.Create(ctx, tx, webhook); err != nil {
+			t.Fatal(err)
+		}
+		for j := 0; j < n; j++ {
+			id := w + "-" + strconv.Itoa(j)
+			activity := model.Activity{ID: id, BoardID: "b1", UserID: "u1", Action: model.CREATE, Target: model.ITEM}
+			if err := dbm.ActivityDBManager.Create(ctx, tx, activity); err != nil {
+				t.Fatal(err)
+			}
+			delivery := model.Delivery{ID: id, WebhookID: w, ActivityID: id, Status: model.PENDING, CreatedAt: time.Now()}
+			if err := dbm.DeliveryDBManager.Create(ctx, tx, delivery); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+// waitDeliveries waits until no Delivery is pending.
+func waitDeliveries(t *testing.T, dbm *memory.DBManager) {
+	t.Helper()
+	ctx := context.Background()
+	tx := dbm.TransactionManager.BeginTransaction(ctx, false)
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		pending, err := dbm.DeliveryDBManager.Find(ctx, tx, usecase.DeliveryFilter{
+			Statuses: []model.DeliveryStatus{model.PENDING},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pending) == 0 {
+			return
+		}
+	}
+	t.Fatal("deliveries are still pending")
+}
+
+func TestDeliverOnce(t *testing.T) {
+	dbm := memory.NewDBManager()
+	enqueue(t, &dbm, 5, "w1", "w2", "w3")
+
+	var mu sync.Mutex
+	sent := map[string]int{}
+	sender := funcSender(func(ctx context.Context, webhook model.Webhook, delivery model.Delivery) (int, error) {
+		mu.Lock()
+		sent[delivery.ID]++
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		return 200, nil
+	})
+
+	// Servers sharing DB do not send the same Delivery even if they find it at the same time
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for j := 0; j < 2; j++ {
+		i := newWebhookInteractor(t, &dbm, sender, time.Minute)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			i.Run(ctx)
+		}()
+	}
+	waitDeliveries(t, &dbm)
+	cancel()
+	wg.Wait()
+
+	if len(sent) != 15 {
+		t.Errorf("%d deliveries are sent, want 15", len(sent))
+	}
+	for id, n := range sent {
+		if n != 1 {
+			t.Errorf("delivery(%s) is sent %d times, want once", id, n)
+		}
+	}
+}
+
+func TestDeliverConcurrently(t *testing.T) {
+	dbm := memory.NewDBManager()
+	enqueue(t, &dbm, 1, "slow", "fast")
+
+	// The slow Webhook responds after the fast one has got its Delivery
+	fastSent := make(chan bool)
+	sender := funcSender(func(ctx context.Context, webhook model.Webhook, delivery model.Delivery) (int, error) {
+		if webhook.ID == "fast" {
+			close(fastSent)
+			return 200, nil
+		}
+		select {
+		case <-fastSent:
+			return 200, nil
+		case <-time.After(2 * time.Second):
+			return 0, errors.New("fast webhook is blocked by slow webhook")
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	i := newWebhookInteractor(t, &dbm, sender, time.Minute)
+	done := make(chan bool)
+	go func() {
+		i.Run(ctx)
+		close(done)
+	}()
+	waitDeliveries(t, &dbm)
+	cancel()
+	<-done
+
+	tx := dbm.TransactionManager.BeginTransaction(context.Background(), false)
+	deliveries, err := dbm.DeliveryDBManager.Find(context.Background(), tx, usecase.DeliveryFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range deliveries {
+		if d.Status != model.SUCCEEDED || d.Attempts != 1 {
+			t.Errorf("delivery(%s) is %s after %d attempts with %q, want succeeded after 1 attempt", d.ID, d.Status, d.Attempts, d.Error)
+		}
+	}
+}
+
+func TestDeliverGiveUp(t *testing.T) {
+	dbm := memory.NewDBManager()
+	enqueue(t, &dbm, 1, "w1")
+
+	var mu sync.Mutex
+	attempts := []int{}
+	sent := []time.Time{}
+	sender := funcSender(func(ctx context.Context, webhook model.Webhook, delivery model.Delivery) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts = append(attempts, delivery.Attempts)
+		sent = append(sent, time.Now())
+		return http.StatusInternalServerError, nil
+	})
+
+	// Run is woken up often, so that retries are sent soon after their backoff
+	const backoff = 10 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	i := newWebhookInteractor(t, &dbm, sender, backoff)
+	done := make(chan bool)
+	go func() {
+		i.Run(ctx)
+		close(done)
+	}()
+	go func() {
+		for ctx.Err() == nil {
+			i.Wake()
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	waitDeliveries(t, &dbm)
+	cancel()
+	<-done
+
+	if !reflect.DeepEqual(attempts, []int{1, 2, 3, 4, 5, 6, 7, 8}) {
+		t.Errorf("attempts = %v, want 8 attempts", attempts)
+	}
+	for j := 1; j < len(sent); j++ {
+		if d, min := sent[j].Sub(sent[j-1]), backoff<<uint(j-1); d < min {
+			t.Errorf("attempt %d is sent %v after the previous one, want %v or more", j+1, d, min)
+		}
+	}
+
+	tx := dbm.TransactionManager.BeginTransaction(context.Background(), false)
+	deliveries, err := dbm.DeliveryDBManager.Find(context.Background(), tx, usecase.DeliveryFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Status != model.FAILED || deliveries[0].Attempts != 8 {
+		t.Errorf("got %+v, want a delivery failed after 8 attempts", deliveries)
+	}
+}
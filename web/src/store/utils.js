@@ -1,8 +1,14 @@
+// CSRF token is issued in a cookie at sign-in and must be sent back in a header.
+function csrfToken() {
+  const cookie = document.cookie.split('; ').find((c) => c.startsWith('XSRF-TOKEN='));
+  return cookie ? decodeURIComponent(cookie.slice('XSRF-TOKEN='.length)) : '';
+}
+
 export function fetchAPI(url, method = 'GET', body = '') {
   const options = {
     method,
     headers: {
-      'X-XSRF-TOKEN': 'csrf',
+      'X-XSRF-TOKEN': csrfToken(),
       'Content-Type': 'application/json; charset=UTF-8',
     },
     credentials: 'same-origin',